@@ -0,0 +1,7 @@
+module github.com/SkySock/lode/libs/clients
+
+go 1.24.3
+
+require github.com/SkySock/lode/libs/shared-dto v0.0.0
+
+replace github.com/SkySock/lode/libs/shared-dto => ../shared-dto
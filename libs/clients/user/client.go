@@ -0,0 +1,353 @@
+package user
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	v1 "github.com/SkySock/lode/libs/shared-dto/user/http/v1"
+)
+
+const (
+	refreshCookieName = "refreshToken"
+	// refreshMargin renews the access token slightly before it expires, so
+	// that a request does not race the expiry on its way to the server.
+	refreshMargin = 10 * time.Second
+)
+
+// Client calls the user-service HTTP API, directly or through the gateway.
+// After SignIn it keeps the access and refresh tokens and renews the access
+// token on its own. A Client is safe for concurrent use.
+type Client struct {
+	baseURL    *url.URL
+	httpClient *http.Client
+	retries    int
+	backoff    time.Duration
+
+	mu           sync.Mutex
+	accessToken  string
+	refreshToken string
+	refreshMu    sync.Mutex
+}
+
+type Option func(*Client)
+
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Client) {
+		c.httpClient = httpClient
+	}
+}
+
+// WithRetries retries idempotent requests that failed with a network error or
+// 502, 503 or 504 up to attempts times, doubling backoff between attempts.
+func WithRetries(attempts int, backoff time.Duration) Option {
+	return func(c *Client) {
+		c.retries = attempts
+		c.backoff = backoff
+	}
+}
+
+// WithTokens restores a session, e.g. one persisted by a previous process.
+func WithTokens(accessToken, refreshToken string) Option {
+	return func(c *Client) {
+		c.accessToken = accessToken
+		c.refreshToken = refreshToken
+	}
+}
+
+// New creates a client for the service at baseURL, e.g. http://api-gateway:8080.
+func New(baseURL string, opts ...Option) (*Client, error) {
+	u, err := url.Parse(strings.TrimSuffix(baseURL, "/"))
+	if err != nil {
+		return nil, fmt.Errorf("user client: invalid base url: %w", err)
+	}
+	if u.Scheme == "" || u.Host == "" {
+		return nil, fmt.Errorf("user client: base url must be absolute: %q", baseURL)
+	}
+
+	c := &Client{
+		baseURL:    u,
+		httpClient: &http.Client{Timeout: 30 * time.Second},
+		retries:    2,
+		backoff:    100 * time.Millisecond,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	return c, nil
+}
+
+func (c *Client) SignUp(ctx context.Context, req v1.SignUpRequest) (*v1.SignUpResponse, error) {
+	var resp v1.SignUpResponse
+	if err := c.do(ctx, http.MethodPost, "/api/v1/auth/sign-up", req, &resp, callOptions{}); err != nil {
+		return nil, err
+	}
+
+	return &resp, nil
+}
+
+// SignIn authenticates the client; later calls are made on behalf of the user.
+func (c *Client) SignIn(ctx context.Context, req v1.SignInRequest) (*v1.SignInResponse, error) {
+	var resp v1.SignInResponse
+	if err := c.do(ctx, http.MethodPost, "/api/v1/auth/sign-in", req, &resp, callOptions{}); err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	c.accessToken = resp.AccessToken
+	c.mu.Unlock()
+
+	return &resp, nil
+}
+
+// SignOut revokes the session and forgets the tokens.
+func (c *Client) SignOut(ctx context.Context) error {
+	if _, refresh := c.Tokens(); refresh == "" {
+		return ErrNoSession
+	}
+
+	if err := c.do(ctx, http.MethodPost, "/api/v1/auth/sign-out", nil, nil, callOptions{withRefreshCookie: true}); err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	c.accessToken, c.refreshToken = "", ""
+	c.mu.Unlock()
+
+	return nil
+}
+
+// Refresh rotates the session tokens.
+func (c *Client) Refresh(ctx context.Context) (*v1.RefreshResponse, error) {
+	if _, refresh := c.Tokens(); refresh == "" {
+		return nil, ErrNoSession
+	}
+
+	var resp v1.RefreshResponse
+	if err := c.do(ctx, http.MethodPost, "/api/v1/auth/refresh", nil, &resp, callOptions{withRefreshCookie: true}); err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	c.accessToken = resp.AccessToken
+	c.mu.Unlock()
+
+	return &resp, nil
+}
+
+func (c *Client) GetProfile(ctx context.Context, profileName string) (*v1.ProfileResponse, error) {
+	var resp v1.ProfileResponse
+	path := "/api/v1/profiles/" + url.PathEscape(profileName)
+	if err := c.do(ctx, http.MethodGet, path, nil, &resp, callOptions{authenticated: true}); err != nil {
+		return nil, err
+	}
+
+	return &resp, nil
+}
+
+// Tokens returns the current access and refresh tokens, e.g. to persist them.
+func (c *Client) Tokens() (accessToken, refreshToken string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.accessToken, c.refreshToken
+}
+
+type callOptions struct {
+	// authenticated calls carry the access token and refresh it when needed.
+	authenticated     bool
+	withRefreshCookie bool
+}
+
+func (c *Client) do(ctx context.Context, method, path string, in, out any, opts callOptions) error {
+	var body []byte
+	if in != nil {
+		var err error
+		if body, err = json.Marshal(in); err != nil {
+			return fmt.Errorf("user client: encode request: %w", err)
+		}
+	}
+
+	if opts.authenticated {
+		if access, refresh := c.Tokens(); refresh != "" && expiresSoon(access) {
+			if err := c.renew(ctx, access); err != nil {
+				return err
+			}
+		}
+	}
+
+	refreshed := false
+	for {
+		access, _ := c.Tokens()
+
+		resp, err := c.send(ctx, method, path, body, opts)
+		if err != nil {
+			return err
+		}
+
+		if resp.StatusCode == http.StatusUnauthorized && opts.authenticated && !refreshed {
+			if _, refresh := c.Tokens(); refresh != "" {
+				drain(resp)
+				if err := c.renew(ctx, access); err != nil {
+					return err
+				}
+				refreshed = true
+				continue
+			}
+		}
+
+		return c.handleResponse(resp, out)
+	}
+}
+
+// send performs one logical request, retrying it on transient failures.
+func (c *Client) send(ctx context.Context, method, path string, body []byte, opts callOptions) (*http.Response, error) {
+	idempotent := method == http.MethodGet || method == http.MethodHead
+
+	for attempt := 0; ; attempt++ {
+		req, err := c.newRequest(ctx, method, path, body, opts)
+		if err != nil {
+			return nil, err
+		}
+
+		resp, err := c.httpClient.Do(req)
+
+		retryable := idempotent && attempt < c.retries && ctx.Err() == nil
+		if err != nil {
+			if !retryable {
+				return nil, fmt.Errorf("user client: %s %s: %w", method, path, err)
+			}
+		} else {
+			switch resp.StatusCode {
+			case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+				if !retryable {
+					return resp, nil
+				}
+				drain(resp)
+			default:
+				return resp, nil
+			}
+		}
+
+		select {
+		case <-time.After(c.backoff << attempt):
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
+
+func (c *Client) newRequest(ctx context.Context, method, path string, body []byte, opts callOptions) (*http.Request, error) {
+	var r io.Reader
+	if body != nil {
+		r = bytes.NewReader(body)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL.String()+path, r)
+	if err != nil {
+		return nil, fmt.Errorf("user client: build request: %w", err)
+	}
+
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	access, refresh := c.Tokens()
+	if opts.authenticated && access != "" {
+		req.Header.Set("Authorization", "Bearer "+access)
+	}
+	// The refresh cookie is Secure, so a cookie jar would not send it over
+	// plain HTTP inside the cluster; it is managed by hand instead.
+	if opts.withRefreshCookie && refresh != "" {
+		req.AddCookie(&http.Cookie{Name: refreshCookieName, Value: refresh})
+	}
+
+	return req, nil
+}
+
+func (c *Client) handleResponse(resp *http.Response, out any) error {
+	defer drain(resp)
+
+	for _, cookie := range resp.Cookies() {
+		if cookie.Name != refreshCookieName {
+			continue
+		}
+
+		c.mu.Lock()
+		if cookie.MaxAge < 0 || cookie.Value == "" {
+			c.refreshToken = ""
+		} else {
+			c.refreshToken = cookie.Value
+		}
+		c.mu.Unlock()
+	}
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		return decodeError(resp)
+	}
+
+	if out == nil {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("user client: decode response: %w", err)
+	}
+
+	return nil
+}
+
+// renew refreshes the access token unless another call already replaced the
+// stale one.
+func (c *Client) renew(ctx context.Context, stale string) error {
+	c.refreshMu.Lock()
+	defer c.refreshMu.Unlock()
+
+	if access, _ := c.Tokens(); access != stale {
+		return nil
+	}
+
+	_, err := c.Refresh(ctx)
+	return err
+}
+
+// expiresSoon peeks into the exp claim without verifying the signature;
+// the server remains the one that decides.
+func expiresSoon(token string) bool {
+	if token == "" {
+		return true
+	}
+
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return false
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return false
+	}
+
+	var claims struct {
+		Exp int64 `json:"exp"`
+	}
+	if err := json.Unmarshal(payload, &claims); err != nil || claims.Exp == 0 {
+		return false
+	}
+
+	return time.Until(time.Unix(claims.Exp, 0)) < refreshMargin
+}
+
+func drain(resp *http.Response) {
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, maxErrorBodyBytes))
+	resp.Body.Close()
+}
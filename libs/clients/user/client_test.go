@@ -0,0 +1,112 @@
+package user_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/SkySock/lode/libs/clients/user"
+	"github.com/SkySock/lode/libs/clients/user/usertest"
+	v1 "github.com/SkySock/lode/libs/shared-dto/user/http/v1"
+)
+
+func TestClient(t *testing.T) {
+	ctx := context.Background()
+
+	srv := usertest.NewServer()
+	defer srv.Close()
+
+	c, err := user.New(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("Sign up and sign in", func(t *testing.T) {
+		if _, err := c.SignUp(ctx, v1.SignUpRequest{Username: "Alice", Email: "alice@example.com", Password: "secret"}); err != nil {
+			t.Fatalf("SignUp: %v", err)
+		}
+
+		_, err := c.SignUp(ctx, v1.SignUpRequest{Username: "alice", Email: "other@example.com", Password: "secret"})
+		if !errors.Is(err, user.ErrConflict) {
+			t.Errorf("Expected ErrConflict, got %v", err)
+		}
+
+		if _, err := c.SignIn(ctx, v1.SignInRequest{Login: "alice", Password: "wrong"}); !errors.Is(err, user.ErrUnauthorized) {
+			t.Errorf("Expected ErrUnauthorized, got %v", err)
+		}
+
+		if _, err := c.SignIn(ctx, v1.SignInRequest{Login: "alice@example.com", Password: "secret"}); err != nil {
+			t.Fatalf("SignIn: %v", err)
+		}
+		if access, refresh := c.Tokens(); access == "" || refresh == "" {
+			t.Error("Client should keep both tokens after sign in")
+		}
+	})
+	t.Run("Profile", func(t *testing.T) {
+		p, err := c.GetProfile(ctx, "alice")
+		if err != nil {
+			t.Fatalf("GetProfile: %v", err)
+		}
+		if p.ProfileName != "alice" {
+			t.Errorf("Wrong profile: %+v", p)
+		}
+
+		_, err = c.GetProfile(ctx, "bob")
+		var apiErr *user.Error
+		if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusNotFound || apiErr.Detail == "" {
+			t.Errorf("Expected a not found problem, got %v", err)
+		}
+	})
+	t.Run("Access token is refreshed", func(t *testing.T) {
+		access, refresh := c.Tokens()
+		srv.ExpireAccessTokens()
+
+		if _, err := c.GetProfile(ctx, "alice"); err != nil {
+			t.Fatalf("GetProfile after expiry: %v", err)
+		}
+		if newAccess, newRefresh := c.Tokens(); newAccess == access || newRefresh == refresh {
+			t.Error("Tokens should be rotated")
+		}
+	})
+	t.Run("Sign out", func(t *testing.T) {
+		if err := c.SignOut(ctx); err != nil {
+			t.Fatalf("SignOut: %v", err)
+		}
+		if err := c.SignOut(ctx); !errors.Is(err, user.ErrNoSession) {
+			t.Errorf("Expected ErrNoSession, got %v", err)
+		}
+	})
+}
+
+func TestClientRetries(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"profileName":"alice"}`))
+	}))
+	defer srv.Close()
+
+	c, _ := user.New(srv.URL, user.WithRetries(2, time.Millisecond))
+	if _, err := c.GetProfile(context.Background(), "alice"); err != nil {
+		t.Fatalf("GetProfile: %v", err)
+	}
+	if calls.Load() != 3 {
+		t.Errorf("Server called %d times, want 3", calls.Load())
+	}
+
+	calls.Store(0)
+	if _, err := c.SignUp(context.Background(), v1.SignUpRequest{}); !errors.Is(err, &user.Error{StatusCode: http.StatusServiceUnavailable}) {
+		t.Errorf("Non-idempotent request should not be retried, got %v", err)
+	}
+	if calls.Load() != 1 {
+		t.Errorf("Server called %d times, want 1", calls.Load())
+	}
+}
@@ -0,0 +1,68 @@
+package user
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// Sentinels for errors.Is. An *Error matches a sentinel with the same status
+// code, e.g. errors.Is(err, user.ErrNotFound).
+var (
+	ErrBadRequest   = &Error{StatusCode: http.StatusBadRequest}
+	ErrUnauthorized = &Error{StatusCode: http.StatusUnauthorized}
+	ErrForbidden    = &Error{StatusCode: http.StatusForbidden}
+	ErrNotFound     = &Error{StatusCode: http.StatusNotFound}
+	ErrConflict     = &Error{StatusCode: http.StatusConflict}
+
+	ErrNoSession = errors.New("user client: no refresh token")
+)
+
+// Error is a failed response of the user-service, decoded from its RFC 7807
+// problem details when available.
+type Error struct {
+	StatusCode int    `json:"status"`
+	Type       string `json:"type"`
+	Title      string `json:"title"`
+	Detail     string `json:"detail"`
+}
+
+func (e *Error) Error() string {
+	title := e.Title
+	if title == "" {
+		title = http.StatusText(e.StatusCode)
+	}
+
+	if e.Detail != "" {
+		return fmt.Sprintf("user client: %d %s: %s", e.StatusCode, title, e.Detail)
+	}
+	return fmt.Sprintf("user client: %d %s", e.StatusCode, title)
+}
+
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	return ok && t.StatusCode == e.StatusCode
+}
+
+const maxErrorBodyBytes = 64 << 10
+
+func decodeError(resp *http.Response) error {
+	e := &Error{}
+
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBodyBytes))
+	if strings.Contains(resp.Header.Get("Content-Type"), "json") {
+		_ = json.Unmarshal(body, e)
+	} else if text := strings.TrimSpace(string(body)); text != "" {
+		e.Detail = text
+	}
+
+	e.StatusCode = resp.StatusCode
+	if e.Title == "" {
+		e.Title = http.StatusText(resp.StatusCode)
+	}
+
+	return e
+}
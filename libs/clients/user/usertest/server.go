@@ -0,0 +1,230 @@
+// Package usertest provides an in-memory fake of the user-service HTTP API
+// for tests of code that uses the user client.
+package usertest
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"time"
+
+	v1 "github.com/SkySock/lode/libs/shared-dto/user/http/v1"
+)
+
+type account struct {
+	id       string
+	username string
+	email    string
+	password string
+	profile  v1.ProfileResponse
+}
+
+// Server is a fake user-service. Access tokens are opaque strings that stay
+// valid until ExpireAccessTokens is called.
+type Server struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	accounts map[string]*account // by username
+	access   map[string]string   // access token -> username
+	sessions map[string]string   // refresh token -> username
+}
+
+func NewServer() *Server {
+	s := &Server{
+		accounts: make(map[string]*account),
+		access:   make(map[string]string),
+		sessions: make(map[string]string),
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /api/v1/auth/sign-up", s.signUp)
+	mux.HandleFunc("POST /api/v1/auth/sign-in", s.signIn)
+	mux.HandleFunc("POST /api/v1/auth/sign-out", s.signOut)
+	mux.HandleFunc("POST /api/v1/auth/refresh", s.refresh)
+	mux.HandleFunc("GET /api/v1/profiles/{profileName}", s.getProfile)
+
+	s.Server = httptest.NewServer(mux)
+
+	return s
+}
+
+// AddUser registers an account directly and returns its id.
+func (s *Server) AddUser(username, email, password string) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.addUser(username, email, password).id
+}
+
+// ExpireAccessTokens invalidates all issued access tokens, so that clients
+// have to refresh them.
+func (s *Server) ExpireAccessTokens() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	clear(s.access)
+}
+
+func (s *Server) addUser(username, email, password string) *account {
+	id := newToken()[:32]
+	a := &account{
+		id:       id,
+		username: strings.ToLower(username),
+		email:    strings.ToLower(email),
+		password: password,
+		profile: v1.ProfileResponse{
+			ID:          newToken()[:32],
+			UserID:      id,
+			ProfileName: strings.ToLower(username),
+			CreatedAt:   time.Now().UTC(),
+		},
+	}
+	s.accounts[a.username] = a
+
+	return a
+}
+
+func (s *Server) signUp(w http.ResponseWriter, r *http.Request) {
+	var req v1.SignUpRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Username == "" || req.Email == "" || req.Password == "" {
+		writeProblem(w, http.StatusBadRequest, "Error input data")
+		return
+	}
+	req.Normalize()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, a := range s.accounts {
+		if a.username == req.Username || a.email == req.Email {
+			writeProblem(w, http.StatusConflict, "Username or email already exists")
+			return
+		}
+	}
+
+	a := s.addUser(req.Username, req.Email, req.Password)
+	writeJSON(w, http.StatusCreated, v1.SignUpResponse{UserId: a.id})
+}
+
+func (s *Server) signIn(w http.ResponseWriter, r *http.Request) {
+	var req v1.SignInRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeProblem(w, http.StatusBadRequest, "Error input data")
+		return
+	}
+	req.Normalize()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var found *account
+	for _, a := range s.accounts {
+		if a.username == req.Login || a.email == req.Login {
+			found = a
+		}
+	}
+	if found == nil || found.password != req.Password {
+		writeProblem(w, http.StatusUnauthorized, "Invalid credentials")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, v1.SignInResponse{AccessToken: s.startSession(w, found.username)})
+}
+
+func (s *Server) refresh(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	username, ok := s.session(r)
+	if !ok {
+		writeProblem(w, http.StatusUnauthorized, "Session is no longer valid")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, v1.RefreshResponse{AccessToken: s.startSession(w, username)})
+}
+
+func (s *Server) signOut(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.session(r); !ok {
+		writeProblem(w, http.StatusBadRequest, "Missing or empty refreshToken cookie")
+		return
+	}
+
+	http.SetCookie(w, &http.Cookie{Name: "refreshToken", Path: "/api/v1/auth", MaxAge: -1})
+	w.WriteHeader(http.StatusOK)
+}
+
+func (s *Server) getProfile(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if auth := r.Header.Get("Authorization"); auth != "" {
+		if _, ok := s.access[strings.TrimPrefix(auth, "Bearer ")]; !ok {
+			writeProblem(w, http.StatusUnauthorized, "Invalid access token")
+			return
+		}
+	}
+
+	a, ok := s.accounts[strings.ToLower(r.PathValue("profileName"))]
+	if !ok {
+		writeProblem(w, http.StatusNotFound, "Profile not found")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, a.profile)
+}
+
+// session consumes the refresh token of the request; tokens are single-use
+// like in the real service.
+func (s *Server) session(r *http.Request) (string, bool) {
+	cookie, err := r.Cookie("refreshToken")
+	if err != nil {
+		return "", false
+	}
+
+	username, ok := s.sessions[cookie.Value]
+	delete(s.sessions, cookie.Value)
+
+	return username, ok
+}
+
+func (s *Server) startSession(w http.ResponseWriter, username string) string {
+	access, refresh := newToken(), newToken()
+	s.access[access] = username
+	s.sessions[refresh] = username
+
+	http.SetCookie(w, &http.Cookie{Name: "refreshToken", Value: refresh, Path: "/api/v1/auth", HttpOnly: true, Secure: true})
+
+	return access
+}
+
+func newToken() string {
+	b := make([]byte, 32)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func writeProblem(w http.ResponseWriter, status int, detail string) {
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(map[string]any{
+		"type":   "about:blank",
+		"title":  http.StatusText(status),
+		"status": status,
+		"detail": detail,
+	})
+}
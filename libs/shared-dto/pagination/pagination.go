@@ -0,0 +1,15 @@
+// Package pagination holds the types of cursor paginated list endpoints.
+package pagination
+
+const (
+	DefaultLimit = 20
+	MaxLimit     = 100
+)
+
+// Page is a page of a list in a stable order. NextCursor is passed back in the
+// cursor query parameter to get the page after it.
+type Page[T any] struct {
+	Items []T `json:"items"`
+	// NextCursor is omitted on the last page.
+	NextCursor string `json:"nextCursor,omitempty"`
+}
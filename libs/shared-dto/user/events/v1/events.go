@@ -0,0 +1,99 @@
+// Package v1 holds the domain events published by the user-service.
+//
+// Delivery is at least once: an event may arrive more than once, and
+// consumers should deduplicate by its ID.
+package v1
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Stream is the Valkey stream the events are appended to.
+const Stream = "user-events"
+
+// Fields of a stream entry. The payload is the JSON encoding of the event
+// struct of its type.
+const (
+	FieldID         = "id"
+	FieldType       = "type"
+	FieldKey        = "key"
+	FieldOccurredAt = "occurred_at"
+	FieldPayload    = "payload"
+)
+
+const (
+	TypeUserRegistered    = "user.registered"
+	TypeProfileUpdated    = "profile.updated"
+	TypeProfileFollowed   = "profile.followed"
+	TypeProfileUnfollowed = "profile.unfollowed"
+	TypeAccountLocked     = "account.locked"
+	TypeAccountUnlocked   = "account.unlocked"
+	TypeAccountDeleted    = "account.deleted"
+	TypeAccountRestored   = "account.restored"
+	TypeAccountPurged     = "account.purged"
+)
+
+type UserRegistered struct {
+	AccountID   string `json:"accountId"`
+	ProfileID   string `json:"profileId"`
+	ProfileName string `json:"profileName"`
+}
+
+// ProfileUpdated carries the profile as anyone sees it: Bio and Avatar are
+// empty when the privacy of the profile hides them.
+type ProfileUpdated struct {
+	ProfileID   string `json:"profileId"`
+	AccountID   string `json:"accountId"`
+	ProfileName string `json:"profileName"`
+	DisplayName string `json:"displayName"`
+	Bio         string `json:"bio"`
+	Avatar      string `json:"avatar"`
+}
+
+type ProfileFollowed struct {
+	FollowerID string `json:"followerId"`
+	FolloweeID string `json:"followeeId"`
+}
+
+// ProfileUnfollowed is also published for follows removed by a block.
+type ProfileUnfollowed struct {
+	FollowerID string `json:"followerId"`
+	FolloweeID string `json:"followeeId"`
+}
+
+type AccountLocked struct {
+	AccountID string `json:"accountId"`
+}
+
+type AccountUnlocked struct {
+	AccountID string `json:"accountId"`
+}
+
+// AccountDeleted is published when the account is marked as deleted. Its
+// data should be hidden, but kept until the account is purged, since it may
+// be restored before PurgeAfter.
+type AccountDeleted struct {
+	AccountID  string    `json:"accountId"`
+	PurgeAfter time.Time `json:"purgeAfter"`
+}
+
+type AccountRestored struct {
+	AccountID string `json:"accountId"`
+}
+
+// AccountPurged is published when the account is removed for good; consumers
+// should remove what they store about it and its profiles.
+type AccountPurged struct {
+	AccountID  string   `json:"accountId"`
+	ProfileIDs []string `json:"profileIds"`
+}
+
+// WebhookBody is the body of webhook deliveries. Data is the event struct of
+// its type. ID stays the same across retries of a delivery.
+type WebhookBody struct {
+	ID         string          `json:"id"`
+	Type       string          `json:"type"`
+	OccurredAt time.Time       `json:"occurredAt"`
+	Data       json.RawMessage `json:"data"`
+}
@@ -23,3 +23,64 @@ func (s *SignUpRequest) Normalize() {
 func (s *SignInRequest) Normalize() {
 	s.Login = strings.ToLower(s.Login)
 }
+
+type ChangePasswordRequest struct {
+	Login       string `json:"login" example:"ozon671games" validate:"required"`
+	Password    string `json:"password" example:"Da1dfshgn$" validate:"required"`
+	NewPassword string `json:"newPassword" example:"Nw2dfshgn$" validate:"required,password"`
+}
+
+func (s *ChangePasswordRequest) Normalize() {
+	s.Login = strings.ToLower(s.Login)
+}
+
+type LockAccountRequest struct {
+	Reason string `json:"reason" example:"Spam" validate:"lte=500"`
+}
+
+// UpdateProfileRequest changes only the fields that are present.
+type UpdateProfileRequest struct {
+	DisplayName *string `json:"displayName,omitempty" validate:"omitempty,lte=100"`
+	Bio         *string `json:"bio,omitempty" validate:"omitempty,lte=1000"`
+	Avatar      *string `json:"avatar,omitempty" validate:"omitempty,lte=500"`
+}
+
+// UpdatePrivacyRequest changes only the settings that are present.
+type UpdatePrivacyRequest struct {
+	IsPrivate        *bool   `json:"isPrivate,omitempty"`
+	BioVisibility    *string `json:"bioVisibility,omitempty" example:"followers" validate:"omitempty,oneof=everyone followers nobody"`
+	AvatarVisibility *string `json:"avatarVisibility,omitempty" example:"everyone" validate:"omitempty,oneof=everyone followers nobody"`
+	Discoverable     *bool   `json:"discoverable,omitempty"`
+}
+
+type DeleteAccountRequest struct {
+	Password string `json:"password" example:"Da1dfshgn$" validate:"required"`
+}
+
+type ChangeUsernameRequest struct {
+	Username string `json:"username" example:"ozon671games" validate:"required,alphanum,gte=1,lte=50"`
+	Password string `json:"password" example:"Da1dfshgn$" validate:"required"`
+}
+
+func (s *ChangeUsernameRequest) Normalize() {
+	s.Username = strings.ToLower(s.Username)
+}
+
+type ChangeEmailRequest struct {
+	Email    string `json:"email" example:"example@example.com" validate:"required,email,lte=254"`
+	Password string `json:"password" example:"Da1dfshgn$" validate:"required"`
+}
+
+func (s *ChangeEmailRequest) Normalize() {
+	s.Email = strings.ToLower(s.Email)
+}
+
+type ConfirmEmailRequest struct {
+	Token string `json:"token" validate:"required,hexadecimal,len=64"`
+}
+
+type CreateWebhookRequest struct {
+	URL         string   `json:"url" example:"https://partner.example.com/hooks/lode" validate:"required,http_url,lte=2048"`
+	EventTypes  []string `json:"eventTypes" example:"user.registered,profile.updated" validate:"required,min=1,dive,required"`
+	Description string   `json:"description" example:"CRM sync" validate:"lte=200"`
+}
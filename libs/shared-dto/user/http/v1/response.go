@@ -1,5 +1,7 @@
 package v1
 
+import "time"
+
 type SignUpResponse struct {
 	UserId string `json:"userId" example:"01976451-00b3-7e32-9340-4f999c6c5edd"`
 }
@@ -7,3 +9,183 @@ type SignUpResponse struct {
 type SignInResponse struct {
 	AccessToken string `json:"accessToken"`
 }
+
+type RefreshResponse struct {
+	AccessToken string `json:"accessToken"`
+}
+
+type ProfileResponse struct {
+	ID          string    `json:"id" example:"01976451-00b3-7e32-9340-4f999c6c5edd"`
+	UserID      string    `json:"userId" example:"01976451-00b3-7e32-9340-4f999c6c5edd"`
+	ProfileName string    `json:"profileName" example:"ozon671games"`
+	DisplayName string    `json:"displayName"`
+	Bio         string    `json:"bio"`
+	Avatar      string    `json:"avatar"`
+	CreatedAt   time.Time `json:"createdAt"`
+	// AvatarThumbnails maps square sizes of an uploaded avatar, in pixels, to
+	// their URLs. Avatar is the URL of the largest one.
+	AvatarThumbnails map[int]string `json:"avatarThumbnails,omitempty"`
+	FollowersCount   int            `json:"followersCount"`
+	FollowingCount   int            `json:"followingCount"`
+	IsPrivate        bool           `json:"isPrivate"`
+	// Privacy is only shown to the owner of the profile.
+	Privacy *ProfilePrivacyResponse `json:"privacy,omitempty"`
+}
+
+// ProfilePrivacyResponse holds the privacy settings of a profile. Bio and
+// avatar visibilities are everyone, followers or nobody.
+type ProfilePrivacyResponse struct {
+	IsPrivate        bool   `json:"isPrivate"`
+	BioVisibility    string `json:"bioVisibility" example:"followers"`
+	AvatarVisibility string `json:"avatarVisibility" example:"everyone"`
+	Discoverable     bool   `json:"discoverable"`
+}
+
+// RelationResponse is a follow, block or mute. Profile is the profile on its
+// other side.
+type RelationResponse struct {
+	ID        string          `json:"id" example:"01976451-00b3-7e32-9340-4f999c6c5edd"`
+	Profile   ProfileResponse `json:"profile"`
+	CreatedAt time.Time       `json:"createdAt"`
+}
+
+// IntrospectionResponse is the RFC 7662 token introspection response. Fields
+// other than Active are only set for active tokens. Scope holds the
+// permissions of an access token, separated by spaces.
+type IntrospectionResponse struct {
+	Active    bool     `json:"active"`
+	TokenType string   `json:"token_type,omitempty" example:"access_token"`
+	Subject   string   `json:"sub,omitempty" example:"01976451-00b3-7e32-9340-4f999c6c5edd"`
+	ProfileID string   `json:"profile_id,omitempty" example:"01976451-00b3-7e32-9340-4f999c6c5edd"`
+	SessionID string   `json:"sid,omitempty"`
+	Issuer    string   `json:"iss,omitempty" example:"user-service"`
+	Audience  []string `json:"aud,omitempty"`
+	Roles     []string `json:"roles,omitempty"`
+	Scope     string   `json:"scope,omitempty" example:"roles:read roles:write"`
+	TokenID   string   `json:"jti,omitempty"`
+	IssuedAt  int64    `json:"iat,omitempty"`
+	ExpiresAt int64    `json:"exp,omitempty"`
+}
+
+type RoleResponse struct {
+	Name        string   `json:"name" example:"admin"`
+	Description string   `json:"description"`
+	Permissions []string `json:"permissions" example:"roles:read,roles:write"`
+}
+
+type AccountRoleResponse struct {
+	Role      string    `json:"role" example:"admin"`
+	GrantedBy *string   `json:"grantedBy" example:"01976451-00b3-7e32-9340-4f999c6c5edd"`
+	GrantedAt time.Time `json:"grantedAt"`
+}
+
+type AccountResponse struct {
+	ID                    string     `json:"id" example:"01976451-00b3-7e32-9340-4f999c6c5edd"`
+	Username              string     `json:"username" example:"ozon671games"`
+	Email                 string     `json:"email" example:"example@example.com"`
+	CreatedAt             time.Time  `json:"createdAt"`
+	LockedAt              *time.Time `json:"lockedAt"`
+	LockReason            string     `json:"lockReason,omitempty"`
+	PasswordResetRequired bool       `json:"passwordResetRequired"`
+	DeletedAt             *time.Time `json:"deletedAt"`
+	UsernameChangedAt     *time.Time `json:"usernameChangedAt"`
+}
+
+type AccountListResponse struct {
+	Accounts []AccountResponse `json:"accounts"`
+	// NextCursor is omitted on the last page.
+	NextCursor string `json:"nextCursor,omitempty"`
+}
+
+type SessionResponse struct {
+	ID        string    `json:"id"`
+	ProfileID string    `json:"profileId" example:"01976451-00b3-7e32-9340-4f999c6c5edd"`
+	ExpiresAt time.Time `json:"expiresAt"`
+}
+
+type AccountDetailsResponse struct {
+	Account  AccountResponse   `json:"account"`
+	Profiles []ProfileResponse `json:"profiles"`
+	Sessions []SessionResponse `json:"sessions"`
+}
+
+type ForceLogoutResponse struct {
+	TerminatedSessions int `json:"terminatedSessions" example:"2"`
+}
+
+type AuthEventResponse struct {
+	ID string `json:"id"`
+	// AccountID is only set in the admin API.
+	AccountID *string   `json:"accountId,omitempty"`
+	Type      string    `json:"type" example:"sign_in"`
+	IP        string    `json:"ip" example:"203.0.113.7"`
+	UserAgent string    `json:"userAgent"`
+	Outcome   string    `json:"outcome" example:"failure"`
+	Reason    string    `json:"reason,omitempty" example:"incorrect_password"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+type AuthEventListResponse struct {
+	Events []AuthEventResponse `json:"events"`
+	// NextCursor is omitted on the last page.
+	NextCursor string `json:"nextCursor,omitempty"`
+}
+
+type AccountDeletionResponse struct {
+	// PurgeAfter is when the account and its data are removed for good. Until
+	// then the account can be restored.
+	PurgeAfter time.Time `json:"purgeAfter"`
+}
+
+// AccountExportResponse is the archive of the data the service stores about
+// an account.
+type AccountExportResponse struct {
+	ExportedAt time.Time           `json:"exportedAt"`
+	Account    AccountResponse     `json:"account"`
+	Profiles   []ProfileResponse   `json:"profiles"`
+	Sessions   []SessionResponse   `json:"sessions"`
+	AuthEvents []AuthEventResponse `json:"authEvents"`
+	// IdentityChanges are the past usernames and emails of the account.
+	IdentityChanges []IdentityChangeResponse `json:"identityChanges"`
+}
+
+type IdentityChangeResponse struct {
+	Kind      string    `json:"kind" example:"username"`
+	OldValue  string    `json:"oldValue" example:"ozon671games"`
+	NewValue  string    `json:"newValue" example:"ozon672games"`
+	ChangedAt time.Time `json:"changedAt"`
+}
+
+type WebhookResponse struct {
+	ID          string    `json:"id"`
+	URL         string    `json:"url" example:"https://partner.example.com/hooks/lode"`
+	EventTypes  []string  `json:"eventTypes" example:"user.registered,profile.updated"`
+	Description string    `json:"description"`
+	CreatedAt   time.Time `json:"createdAt"`
+	// Secret signs the deliveries. It is only returned when the webhook is
+	// created.
+	Secret string `json:"secret,omitempty"`
+}
+
+type WebhookListResponse struct {
+	Webhooks []WebhookResponse `json:"webhooks"`
+}
+
+type WebhookDeliveryResponse struct {
+	ID             string     `json:"id"`
+	EventID        string     `json:"eventId"`
+	EventType      string     `json:"eventType" example:"user.registered"`
+	Status         string     `json:"status" example:"dead"`
+	Attempts       int        `json:"attempts" example:"10"`
+	NextAttemptAt  time.Time  `json:"nextAttemptAt"`
+	LastStatusCode *int       `json:"lastStatusCode,omitempty" example:"502"`
+	LastError      string     `json:"lastError,omitempty"`
+	CreatedAt      time.Time  `json:"createdAt"`
+	DeliveredAt    *time.Time `json:"deliveredAt"`
+}
+
+type WebhookDeliveryListResponse struct {
+	Deliveries []WebhookDeliveryResponse `json:"deliveries"`
+	// NextCursor is omitted on the last page.
+	NextCursor string `json:"nextCursor,omitempty"`
+}
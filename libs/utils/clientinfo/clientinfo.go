@@ -0,0 +1,23 @@
+// Package clientinfo carries the origin of a request, as seen by the service,
+// through the context down to the code that records it.
+package clientinfo
+
+import "context"
+
+type Info struct {
+	IP        string
+	UserAgent string
+}
+
+type infoKey struct{}
+
+func NewContext(ctx context.Context, info Info) context.Context {
+	return context.WithValue(ctx, infoKey{}, info)
+}
+
+// FromContext returns the stored Info, or the zero Info for requests that did
+// not pass the ClientInfo middleware, e.g. gRPC calls.
+func FromContext(ctx context.Context) Info {
+	info, _ := ctx.Value(infoKey{}).(Info)
+	return info
+}
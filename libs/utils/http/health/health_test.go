@@ -0,0 +1,93 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestReadiness(t *testing.T) {
+	t.Run("All checks pass", func(t *testing.T) {
+		c := NewChecker(time.Second)
+		c.Register("db", func(ctx context.Context) error { return nil })
+
+		report, code := serveReadiness(t, c)
+		if code != http.StatusOK {
+			t.Errorf("Wrong status code: got %d, want %d", code, http.StatusOK)
+		}
+		if report.Checks["db"].Status != StatusOK {
+			t.Errorf("Wrong check status: %s", report.Checks["db"].Status)
+		}
+	})
+	t.Run("Failing check", func(t *testing.T) {
+		c := NewChecker(time.Second)
+		c.Register("db", func(ctx context.Context) error { return nil })
+		c.Register("cache", func(ctx context.Context) error { return errors.New("connection refused") })
+
+		report, code := serveReadiness(t, c)
+		if code != http.StatusServiceUnavailable {
+			t.Errorf("Wrong status code: got %d, want %d", code, http.StatusServiceUnavailable)
+		}
+		if report.Checks["cache"].Error != "connection refused" {
+			t.Errorf("Wrong check error: %q", report.Checks["cache"].Error)
+		}
+		if report.Checks["db"].Status != StatusOK {
+			t.Error("Healthy check should stay ok")
+		}
+	})
+	t.Run("Check timeout", func(t *testing.T) {
+		c := NewChecker(10 * time.Millisecond)
+		c.Register("slow", func(ctx context.Context) error {
+			<-ctx.Done()
+			return ctx.Err()
+		})
+
+		_, code := serveReadiness(t, c)
+		if code != http.StatusServiceUnavailable {
+			t.Errorf("Wrong status code: got %d, want %d", code, http.StatusServiceUnavailable)
+		}
+	})
+	t.Run("Shutting down", func(t *testing.T) {
+		c := NewChecker(time.Second)
+		c.Shutdown()
+
+		report, code := serveReadiness(t, c)
+		if code != http.StatusServiceUnavailable {
+			t.Errorf("Wrong status code: got %d, want %d", code, http.StatusServiceUnavailable)
+		}
+		if report.Status != StatusShutdown {
+			t.Errorf("Wrong status: got %s, want %s", report.Status, StatusShutdown)
+		}
+	})
+}
+
+func TestLiveness(t *testing.T) {
+	c := NewChecker(time.Second)
+	c.Register("db", func(ctx context.Context) error { return errors.New("down") })
+	c.Shutdown()
+
+	rec := httptest.NewRecorder()
+	c.LivenessHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+
+	if rec.Code != http.StatusOK {
+		t.Errorf("Liveness should not depend on checks: got %d", rec.Code)
+	}
+}
+
+func serveReadiness(t *testing.T, c *Checker) (Report, int) {
+	t.Helper()
+
+	rec := httptest.NewRecorder()
+	c.ReadinessHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+
+	var report Report
+	if err := json.NewDecoder(rec.Body).Decode(&report); err != nil {
+		t.Fatalf("Decode failed: %v", err)
+	}
+
+	return report, rec.Code
+}
@@ -0,0 +1,69 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"github.com/SkySock/lode/libs/utils/http/response"
+	"github.com/SkySock/lode/libs/utils/jwt"
+	"github.com/gorilla/mux"
+)
+
+type claimsKey struct{}
+
+// AccessTokenVerifier checks a bearer token, e.g. with jwt.Verifier plus a
+// revocation lookup, and returns its claims.
+type AccessTokenVerifier func(ctx context.Context, token string) (*jwt.Claims, error)
+
+// Authenticate requires a valid bearer access token and stores its claims in
+// the request context.
+func Authenticate(verify AccessTokenVerifier) mux.MiddlewareFunc {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+			if !ok || token == "" {
+				unauthorized(w, "missing bearer token")
+				return
+			}
+
+			claims, err := verify(r.Context(), token)
+			if err != nil {
+				unauthorized(w, "invalid access token")
+				return
+			}
+
+			next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), claimsKey{}, claims)))
+		})
+	}
+}
+
+// AuthenticateOptional is Authenticate for endpoints that anyone may call:
+// requests without a bearer token pass through without claims. A token that
+// is sent must still be valid.
+func AuthenticateOptional(verify AccessTokenVerifier) mux.MiddlewareFunc {
+	authenticate := Authenticate(verify)
+
+	return func(next http.Handler) http.Handler {
+		withClaims := authenticate(next)
+
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get("Authorization") == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+			withClaims.ServeHTTP(w, r)
+		})
+	}
+}
+
+// ClaimsFromContext returns the claims stored by Authenticate.
+func ClaimsFromContext(ctx context.Context) (*jwt.Claims, bool) {
+	claims, ok := ctx.Value(claimsKey{}).(*jwt.Claims)
+	return claims, ok
+}
+
+func unauthorized(w http.ResponseWriter, detail string) {
+	w.Header().Set("WWW-Authenticate", `Bearer realm="lode"`)
+	response.WriteProblem(w, http.StatusUnauthorized, detail)
+}
@@ -0,0 +1,55 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/SkySock/lode/libs/utils/jwt"
+)
+
+func TestAuthenticateOptional(t *testing.T) {
+	verify := func(ctx context.Context, token string) (*jwt.Claims, error) {
+		if token == "valid" {
+			return &jwt.Claims{}, nil
+		}
+		return nil, errors.New("invalid token")
+	}
+
+	handler := AuthenticateOptional(verify)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, ok := ClaimsFromContext(r.Context()); ok {
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+
+	testCases := []struct {
+		name          string
+		authorization string
+		expectCode    int
+	}{
+		{"No token", "", http.StatusNoContent},
+		{"Invalid token", "Bearer forged", http.StatusUnauthorized},
+		{"Not a bearer token", "Basic dXNlcjpwYXNz", http.StatusUnauthorized},
+		{"Valid token", "Bearer valid", http.StatusOK},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/profiles/1/followers", nil)
+			if tc.authorization != "" {
+				req.Header.Set("Authorization", tc.authorization)
+			}
+
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			if rec.Code != tc.expectCode {
+				t.Errorf("Wrong status code: got %d, want %d", rec.Code, tc.expectCode)
+			}
+		})
+	}
+}
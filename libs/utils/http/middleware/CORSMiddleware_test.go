@@ -0,0 +1,64 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestCORS(t *testing.T) {
+	handler := CORS(CORSOptions{
+		AllowedOrigins:   []string{"https://app.example.com", "https://*.preview.example.com"},
+		AllowedMethods:   []string{http.MethodGet, http.MethodPost},
+		AllowedHeaders:   []string{"Authorization", "Content-Type"},
+		AllowCredentials: true,
+		MaxAge:           10 * time.Minute,
+	})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTeapot)
+	}))
+
+	testCases := []struct {
+		name          string
+		method        string
+		origin        string
+		requestMethod string
+		expectCode    int
+		expectOrigin  string
+		expectMaxAge  string
+	}{
+		{"Preflight from allowed origin", http.MethodOptions, "https://app.example.com", "POST", http.StatusNoContent, "https://app.example.com", "600"},
+		{"Preflight from wildcard origin", http.MethodOptions, "https://pr-1.preview.example.com", "GET", http.StatusNoContent, "https://pr-1.preview.example.com", "600"},
+		{"Preflight with disallowed method", http.MethodOptions, "https://app.example.com", "DELETE", http.StatusNoContent, "https://app.example.com", ""},
+		{"Preflight from unknown origin", http.MethodOptions, "https://evil.example.org", "POST", http.StatusNoContent, "", ""},
+		{"Wildcard needs a subdomain", http.MethodOptions, "https://.preview.example.com", "POST", http.StatusNoContent, "", ""},
+		{"Actual request", http.MethodPost, "https://app.example.com", "", http.StatusTeapot, "https://app.example.com", ""},
+		{"Request from unknown origin", http.MethodPost, "https://evil.example.org", "", http.StatusTeapot, "", ""},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(tc.method, "/api/v1/auth/sign-in", nil)
+			req.Header.Set("Origin", tc.origin)
+			if tc.requestMethod != "" {
+				req.Header.Set("Access-Control-Request-Method", tc.requestMethod)
+			}
+
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			if rec.Code != tc.expectCode {
+				t.Errorf("Wrong status code: got %d, want %d", rec.Code, tc.expectCode)
+			}
+			if got := rec.Header().Get("Access-Control-Allow-Origin"); got != tc.expectOrigin {
+				t.Errorf("Wrong allowed origin: got %q, want %q", got, tc.expectOrigin)
+			}
+			if got := rec.Header().Get("Access-Control-Max-Age"); got != tc.expectMaxAge {
+				t.Errorf("Wrong max age: got %q, want %q", got, tc.expectMaxAge)
+			}
+			if tc.expectOrigin != "" && rec.Header().Get("Access-Control-Allow-Credentials") != "true" {
+				t.Error("Credentials should be allowed")
+			}
+		})
+	}
+}
@@ -0,0 +1,46 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestCSRF(t *testing.T) {
+	handler := CSRF([]string{"https://app.example.com"})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	testCases := []struct {
+		name       string
+		method     string
+		headers    map[string]string
+		expectCode int
+	}{
+		{"Safe method", http.MethodGet, map[string]string{"Origin": "https://evil.example.org"}, http.StatusOK},
+		{"Non-browser client", http.MethodPost, nil, http.StatusOK},
+		{"Same origin fetch", http.MethodPost, map[string]string{"Sec-Fetch-Site": "same-origin"}, http.StatusOK},
+		{"Trusted origin", http.MethodPost, map[string]string{"Sec-Fetch-Site": "same-site", "Origin": "https://app.example.com"}, http.StatusOK},
+		{"Same host", http.MethodPost, map[string]string{"Origin": "http://example.com"}, http.StatusOK},
+		{"Foreign origin", http.MethodPost, map[string]string{"Sec-Fetch-Site": "cross-site", "Origin": "https://evil.example.org"}, http.StatusForbidden},
+		{"Foreign referer", http.MethodPost, map[string]string{"Referer": "https://evil.example.org/form"}, http.StatusForbidden},
+		{"Null origin", http.MethodPost, map[string]string{"Origin": "null"}, http.StatusForbidden},
+		{"Cross-site without origin", http.MethodPost, map[string]string{"Sec-Fetch-Site": "cross-site"}, http.StatusForbidden},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(tc.method, "http://example.com/api/v1/auth/sign-out", nil)
+			for k, v := range tc.headers {
+				req.Header.Set(k, v)
+			}
+
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			if rec.Code != tc.expectCode {
+				t.Errorf("Wrong status code: got %d, want %d", rec.Code, tc.expectCode)
+			}
+		})
+	}
+}
@@ -0,0 +1,52 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/SkySock/lode/libs/utils/clientinfo"
+)
+
+func TestClientInfo(t *testing.T) {
+	proxies, err := ParseTrustedProxies([]string{"10.0.0.0/8", "192.168.1.1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got clientinfo.Info
+	handler := ClientInfo(proxies)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = clientinfo.FromContext(r.Context())
+	}))
+
+	testCases := []struct {
+		name       string
+		remoteAddr string
+		forwarded  []string
+		expectIP   string
+	}{
+		{"Direct client", "203.0.113.7:5555", nil, "203.0.113.7"},
+		{"Header from untrusted client", "203.0.113.7:5555", []string{"1.2.3.4"}, "203.0.113.7"},
+		{"Through trusted proxy", "10.1.2.3:5555", []string{"203.0.113.7"}, "203.0.113.7"},
+		{"Spoofed header behind proxy", "10.1.2.3:5555", []string{"1.2.3.4, 203.0.113.7"}, "203.0.113.7"},
+		{"Chain of trusted proxies", "10.1.2.3:5555", []string{"203.0.113.7", "192.168.1.1"}, "203.0.113.7"},
+		{"Garbage in header", "10.1.2.3:5555", []string{"unknown"}, "10.1.2.3"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.RemoteAddr = tc.remoteAddr
+			req.Header.Set("User-Agent", "test-agent")
+			for _, v := range tc.forwarded {
+				req.Header.Add("X-Forwarded-For", v)
+			}
+
+			handler.ServeHTTP(httptest.NewRecorder(), req)
+
+			if got.IP != tc.expectIP || got.UserAgent != "test-agent" {
+				t.Errorf("Wrong client info: got %+v, want ip %s", got, tc.expectIP)
+			}
+		})
+	}
+}
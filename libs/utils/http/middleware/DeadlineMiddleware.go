@@ -0,0 +1,71 @@
+package middleware
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+// RequestTimeoutHeader carries the time the caller is still willing to wait,
+// in milliseconds. A relative value is used so that clock skew between hosts
+// does not matter.
+const RequestTimeoutHeader = "X-Request-Timeout"
+
+// Deadline bounds the request context by the caller's X-Request-Timeout and by
+// maxTimeout, whichever is shorter. A zero maxTimeout means no upper bound.
+func Deadline(maxTimeout time.Duration) mux.MiddlewareFunc {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			timeout := maxTimeout
+			if requested, ok := ParseRequestTimeout(r.Header.Get(RequestTimeoutHeader)); ok {
+				if timeout == 0 || requested < timeout {
+					timeout = requested
+				}
+			}
+
+			if timeout == 0 {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			ctx, cancel := context.WithTimeout(r.Context(), timeout)
+			defer cancel()
+
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// ParseRequestTimeout parses a RequestTimeoutHeader value, a whole number of
+// milliseconds, into the timeout it asks for. It reports false for a missing,
+// malformed or non-positive value. Values too long for a time.Duration are
+// clamped to the longest one instead of wrapping around to negative ones.
+func ParseRequestTimeout(value string) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+
+	ms, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || ms <= 0 {
+		return 0, false
+	}
+	if maxMs := int64(math.MaxInt64 / time.Millisecond); ms > maxMs {
+		ms = maxMs
+	}
+
+	return time.Duration(ms) * time.Millisecond, true
+}
+
+// FormatRequestTimeout renders the time left until deadline for RequestTimeoutHeader.
+func FormatRequestTimeout(deadline time.Time) string {
+	ms := time.Until(deadline).Milliseconds()
+	if ms < 1 {
+		ms = 1
+	}
+
+	return strconv.FormatInt(ms, 10)
+}
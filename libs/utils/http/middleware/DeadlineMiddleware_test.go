@@ -0,0 +1,92 @@
+package middleware
+
+import (
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestDeadline(t *testing.T) {
+	testCases := []struct {
+		name        string
+		header      string
+		maxTimeout  time.Duration
+		expectBound bool
+		expectLeft  time.Duration
+	}{
+		{"No header and no bound", "", 0, false, 0},
+		{"No header", "", 5 * time.Second, true, 5 * time.Second},
+		{"Shorter header", "100", 5 * time.Second, true, 100 * time.Millisecond},
+		{"Longer header", "10000", 5 * time.Second, true, 5 * time.Second},
+		{"Header without bound", "100", 0, true, 100 * time.Millisecond},
+		{"Invalid header", "soon", 5 * time.Second, true, 5 * time.Second},
+		{"Zero header", "0", 5 * time.Second, true, 5 * time.Second},
+		{"Negative header", "-100", 5 * time.Second, true, 5 * time.Second},
+		{"Header overflowing to negative", "9300000000000", 5 * time.Second, true, 5 * time.Second},
+		{"Overflowing header", "99999999999999999", 5 * time.Second, true, 5 * time.Second},
+		{"Overflowing header without bound", "99999999999999999", 0, true, time.Duration(math.MaxInt64/time.Millisecond) * time.Millisecond},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var (
+				deadline time.Time
+				bounded  bool
+				canceled bool
+			)
+			handler := Deadline(tc.maxTimeout)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				deadline, bounded = r.Context().Deadline()
+				canceled = r.Context().Err() != nil
+			}))
+
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/profiles", nil)
+			if tc.header != "" {
+				req.Header.Set(RequestTimeoutHeader, tc.header)
+			}
+			start := time.Now()
+			handler.ServeHTTP(httptest.NewRecorder(), req)
+			end := time.Now()
+
+			if canceled {
+				t.Fatal("Request context should not be canceled")
+			}
+			if bounded != tc.expectBound {
+				t.Fatalf("Wrong deadline presence: got %v, want %v", bounded, tc.expectBound)
+			}
+			if !bounded {
+				return
+			}
+			if deadline.Before(start.Add(tc.expectLeft)) || deadline.After(end.Add(tc.expectLeft)) {
+				t.Errorf("Wrong time left: got %v, want %v", deadline.Sub(start), tc.expectLeft)
+			}
+		})
+	}
+}
+
+func TestFormatRequestTimeout(t *testing.T) {
+	testCases := []struct {
+		name      string
+		deadline  time.Time
+		expectMin int64
+		expectMax int64
+	}{
+		{"Future deadline", time.Now().Add(2 * time.Second), 1900, 2000},
+		{"Deadline now", time.Now(), 1, 1},
+		{"Past deadline", time.Now().Add(-time.Second), 1, 1},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ms, err := strconv.ParseInt(FormatRequestTimeout(tc.deadline), 10, 64)
+			if err != nil {
+				t.Fatalf("Timeout is not a number: %v", err)
+			}
+			if ms < tc.expectMin || ms > tc.expectMax {
+				t.Errorf("Wrong timeout: got %d, want between %d and %d", ms, tc.expectMin, tc.expectMax)
+			}
+		})
+	}
+}
@@ -0,0 +1,103 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+)
+
+func TestMetrics(t *testing.T) {
+	reg := prometheus.NewRegistry()
+
+	var inFlightDuringRequest float64
+	r := mux.NewRouter()
+	r.Use(Metrics(reg))
+	r.HandleFunc("/api/v1/profiles/{profileName}", func(w http.ResponseWriter, r *http.Request) {
+		inFlightDuringRequest = gaugeValue(t, reg, "http_requests_in_flight")
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte("not found"))
+	}).Methods("GET")
+
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/profiles/ozon671games", nil))
+
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("Wrong status code: got %d, want %d", rec.Code, http.StatusNotFound)
+	}
+
+	requests := findMetric(t, reg, "http_requests_total", map[string]string{
+		"method": "GET",
+		"route":  "/api/v1/profiles/{profileName}",
+		"code":   "404",
+	})
+	if requests == nil {
+		t.Fatal("Request should be counted by route template and status code")
+	}
+	if got := requests.GetCounter().GetValue(); got != 1 {
+		t.Errorf("Wrong request count: got %v, want 1", got)
+	}
+
+	size := findMetric(t, reg, "http_response_size_bytes", map[string]string{
+		"method": "GET",
+		"route":  "/api/v1/profiles/{profileName}",
+	})
+	if size == nil {
+		t.Fatal("Response size should be observed")
+	}
+	if got := size.GetHistogram().GetSampleSum(); got != float64(len("not found")) {
+		t.Errorf("Wrong response size: got %v, want %d", got, len("not found"))
+	}
+
+	if inFlightDuringRequest != 1 {
+		t.Errorf("Wrong in-flight requests during request: got %v, want 1", inFlightDuringRequest)
+	}
+	if got := gaugeValue(t, reg, "http_requests_in_flight"); got != 0 {
+		t.Errorf("Wrong in-flight requests after request: got %v, want 0", got)
+	}
+}
+
+// findMetric returns the metric of the family with exactly the given labels,
+// or nil.
+func findMetric(t *testing.T, reg prometheus.Gatherer, name string, labels map[string]string) *dto.Metric {
+	t.Helper()
+
+	families, err := reg.Gather()
+	if err != nil {
+		t.Fatalf("Gather failed: %v", err)
+	}
+
+	for _, family := range families {
+		if family.GetName() != name {
+			continue
+		}
+		for _, metric := range family.GetMetric() {
+			if len(metric.GetLabel()) != len(labels) {
+				continue
+			}
+			matches := true
+			for _, label := range metric.GetLabel() {
+				if labels[label.GetName()] != label.GetValue() {
+					matches = false
+				}
+			}
+			if matches {
+				return metric
+			}
+		}
+	}
+	return nil
+}
+
+func gaugeValue(t *testing.T, reg prometheus.Gatherer, name string) float64 {
+	t.Helper()
+
+	metric := findMetric(t, reg, name, nil)
+	if metric == nil {
+		t.Fatalf("Gauge %s not found", name)
+	}
+	return metric.GetGauge().GetValue()
+}
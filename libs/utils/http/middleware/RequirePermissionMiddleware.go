@@ -0,0 +1,28 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/SkySock/lode/libs/utils/http/response"
+	"github.com/gorilla/mux"
+)
+
+// RequirePermission lets through requests whose access token grants the
+// permission. It must run after Authenticate.
+func RequirePermission(permission string) mux.MiddlewareFunc {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			claims, ok := ClaimsFromContext(r.Context())
+			if !ok {
+				unauthorized(w, "missing bearer token")
+				return
+			}
+			if !claims.HasPermission(permission) {
+				response.WriteProblem(w, http.StatusForbidden, "missing permission "+permission)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,55 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/SkySock/lode/libs/utils/jwt"
+)
+
+func TestRequirePermission(t *testing.T) {
+	verify := func(ctx context.Context, token string) (*jwt.Claims, error) {
+		switch token {
+		case "admin":
+			return &jwt.Claims{Permissions: []string{"roles:write"}}, nil
+		case "user":
+			return &jwt.Claims{}, nil
+		}
+		return nil, errors.New("invalid token")
+	}
+
+	ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	handler := Authenticate(verify)(RequirePermission("roles:write")(ok))
+
+	testCases := []struct {
+		name          string
+		authorization string
+		expectCode    int
+	}{
+		{"No token", "", http.StatusUnauthorized},
+		{"Invalid token", "Bearer forged", http.StatusUnauthorized},
+		{"Missing permission", "Bearer user", http.StatusForbidden},
+		{"Granted", "Bearer admin", http.StatusOK},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPut, "/api/v1/admin/accounts/1/roles/admin", nil)
+			if tc.authorization != "" {
+				req.Header.Set("Authorization", tc.authorization)
+			}
+
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			if rec.Code != tc.expectCode {
+				t.Errorf("Wrong status code: got %d, want %d", rec.Code, tc.expectCode)
+			}
+		})
+	}
+}
@@ -0,0 +1,77 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestSecurityHeaders(t *testing.T) {
+	testCases := []struct {
+		name          string
+		opts          SecurityHeadersOptions
+		expectHeaders map[string]string
+	}{
+		{
+			name: "All headers",
+			opts: SecurityHeadersOptions{
+				HSTSMaxAge:            365 * 24 * time.Hour,
+				HSTSIncludeSubdomains: true,
+				HSTSPreload:           true,
+				ContentSecurityPolicy: "default-src 'none'",
+				FrameOptions:          "DENY",
+				ReferrerPolicy:        "no-referrer",
+			},
+			expectHeaders: map[string]string{
+				"X-Content-Type-Options":    "nosniff",
+				"Strict-Transport-Security": "max-age=31536000; includeSubDomains; preload",
+				"Content-Security-Policy":   "default-src 'none'",
+				"X-Frame-Options":           "DENY",
+				"Referrer-Policy":           "no-referrer",
+			},
+		},
+		{
+			name: "HSTS without subdomains",
+			opts: SecurityHeadersOptions{HSTSMaxAge: time.Hour},
+			expectHeaders: map[string]string{
+				"X-Content-Type-Options":    "nosniff",
+				"Strict-Transport-Security": "max-age=3600",
+				"Content-Security-Policy":   "",
+				"X-Frame-Options":           "",
+				"Referrer-Policy":           "",
+			},
+		},
+		{
+			name: "Disabled",
+			opts: SecurityHeadersOptions{HSTSIncludeSubdomains: true, HSTSPreload: true},
+			expectHeaders: map[string]string{
+				"X-Content-Type-Options":    "nosniff",
+				"Strict-Transport-Security": "",
+				"Content-Security-Policy":   "",
+				"X-Frame-Options":           "",
+				"Referrer-Policy":           "",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			handler := SecurityHeaders(tc.opts)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusTeapot)
+			}))
+
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/profiles", nil))
+
+			if rec.Code != http.StatusTeapot {
+				t.Errorf("Wrong status code: got %d, want %d", rec.Code, http.StatusTeapot)
+			}
+			for name, want := range tc.expectHeaders {
+				if got := rec.Header().Get(name); got != want {
+					t.Errorf("Wrong %s: got %q, want %q", name, got, want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,33 @@
+// Package jwt issues and verifies lode access tokens, so that every service
+// checks them the same way.
+package jwt
+
+import (
+	"slices"
+
+	gojwt "github.com/golang-jwt/jwt/v5"
+)
+
+// Claims of an access token. The registered claims jti, sub, iat and exp are
+// required.
+type Claims struct {
+	gojwt.RegisteredClaims
+
+	ProfileID   string   `json:"profile"`
+	SessionID   string   `json:"sid,omitempty"`
+	Roles       []string `json:"roles,omitempty"`
+	Permissions []string `json:"perms,omitempty"`
+}
+
+func (c *Claims) HasPermission(permission string) bool {
+	return slices.Contains(c.Permissions, permission)
+}
+
+// Validate is called by the parser after the registered claims have been
+// validated.
+func (c *Claims) Validate() error {
+	if c.ID == "" || c.Subject == "" || c.IssuedAt == nil {
+		return ErrMissingClaim
+	}
+	return nil
+}
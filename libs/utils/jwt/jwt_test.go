@@ -0,0 +1,93 @@
+package jwt
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	gojwt "github.com/golang-jwt/jwt/v5"
+)
+
+func TestVerifier(t *testing.T) {
+	secret := []byte("secret")
+	now := time.Now()
+
+	valid := func() *Claims {
+		return &Claims{
+			RegisteredClaims: gojwt.RegisteredClaims{
+				ID:        "jti",
+				Subject:   "user",
+				Issuer:    "user-service",
+				Audience:  gojwt.ClaimStrings{"lode-api"},
+				IssuedAt:  gojwt.NewNumericDate(now),
+				NotBefore: gojwt.NewNumericDate(now),
+				ExpiresAt: gojwt.NewNumericDate(now.Add(time.Minute)),
+			},
+			SessionID: "session",
+		}
+	}
+
+	tests := []struct {
+		name   string
+		modify func(c *Claims)
+		secret []byte
+		want   error
+	}{
+		{"Valid", func(c *Claims) {}, secret, nil},
+		{"Wrong secret", func(c *Claims) {}, []byte("other"), ErrSignature},
+		{"Expired", func(c *Claims) { c.ExpiresAt = gojwt.NewNumericDate(now.Add(-time.Minute)) }, secret, ErrExpired},
+		{"Expired within leeway", func(c *Claims) { c.ExpiresAt = gojwt.NewNumericDate(now.Add(-5 * time.Second)) }, secret, nil},
+		{"Missing exp", func(c *Claims) { c.ExpiresAt = nil }, secret, ErrMissingClaim},
+		{"Not yet valid", func(c *Claims) { c.NotBefore = gojwt.NewNumericDate(now.Add(time.Minute)) }, secret, ErrNotYetValid},
+		{"Issued in the future within leeway", func(c *Claims) { c.IssuedAt = gojwt.NewNumericDate(now.Add(5 * time.Second)) }, secret, nil},
+		{"Missing jti", func(c *Claims) { c.ID = "" }, secret, ErrMissingClaim},
+		{"Wrong issuer", func(c *Claims) { c.Issuer = "evil" }, secret, ErrIssuer},
+		{"Wrong audience", func(c *Claims) { c.Audience = gojwt.ClaimStrings{"other", "another"} }, secret, ErrAudience},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := valid()
+			tt.modify(c)
+
+			token, err := NewSigner(tt.secret).Sign(c)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			v := NewVerifier(secret,
+				WithIssuer("user-service"),
+				WithAudience("lode-api"),
+				WithLeeway(30*time.Second),
+				withTimeFunc(func() time.Time { return now }),
+			)
+
+			got, err := v.Verify(token)
+			if !errors.Is(err, tt.want) {
+				t.Fatalf("Wrong error: got %v, want %v", err, tt.want)
+			}
+			if err == nil && got.SessionID != "session" {
+				t.Errorf("Wrong claims: %+v", got)
+			}
+		})
+	}
+
+	t.Run("Algorithm not allowed", func(t *testing.T) {
+		none, err := gojwt.NewWithClaims(gojwt.SigningMethodNone, valid()).SignedString(gojwt.UnsafeAllowNoneSignatureType)
+		if err != nil {
+			t.Fatal(err)
+		}
+		hs512, err := gojwt.NewWithClaims(gojwt.SigningMethodHS512, valid()).SignedString(secret)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		for _, token := range []string{none, hs512} {
+			if _, err := NewVerifier(secret).Verify(token); !errors.Is(err, ErrSignature) {
+				t.Errorf("Expected ErrSignature, got %v", err)
+			}
+		}
+		if _, err := NewVerifier(secret, WithAlgorithms("HS512")).Verify(hs512); err != nil {
+			t.Errorf("Explicitly allowed algorithm rejected: %v", err)
+		}
+	})
+}
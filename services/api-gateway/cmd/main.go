@@ -2,9 +2,10 @@ package main
 
 import (
 	"github.com/SkySock/lode/services/api-gateway/internal/app"
+	"github.com/SkySock/lode/services/api-gateway/internal/config"
 )
 
 func main() {
-	cfg := app.MustLoad()
+	cfg := config.MustLoad()
 	app.Run(cfg)
 }
@@ -6,15 +6,20 @@ import (
 	"fmt"
 	"log/slog"
 	"net/http"
-	"net/http/httputil"
-	"net/url"
 	"os"
 	"os/signal"
 	"syscall"
 	"time"
 
+	"github.com/SkySock/lode/libs/utils/http/health"
 	"github.com/SkySock/lode/libs/utils/http/middleware"
+	"github.com/SkySock/lode/services/api-gateway/internal/cache"
+	"github.com/SkySock/lode/services/api-gateway/internal/config"
 	"github.com/gorilla/mux"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/valkey-io/valkey-go"
 )
 
 const (
@@ -23,17 +28,6 @@ const (
 	envProd  = "prod"
 )
 
-func reverseProxyWithLogger(l *slog.Logger) func(target string) http.Handler {
-	return func(target string) http.Handler {
-		targetURL, err := url.Parse(target)
-		if err != nil {
-			l.Error("Failed to parse target URL", "target", target, "error", err)
-		}
-		proxy := httputil.NewSingleHostReverseProxy(targetURL)
-		return proxy
-	}
-}
-
 func setupLogger(env string) *slog.Logger {
 	var log *slog.Logger
 
@@ -55,20 +49,62 @@ func setupLogger(env string) *slog.Logger {
 	return log
 }
 
-func Run(cfg *Config) {
+func Run(cfg *config.Config) {
 	log := setupLogger(cfg.Env)
 	log.Info("starting application", slog.String("env", cfg.Env))
 
-	newReverseProxy := reverseProxyWithLogger(log)
+	reg := prometheus.NewRegistry()
+	reg.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+	)
 
-	r := mux.NewRouter()
+	checker := health.NewChecker(cfg.Health.CheckTimeout)
+	metrics := middleware.Metrics(reg)
 
-	r.Use(middleware.Logging(log))
-	r.PathPrefix("/api/v1/auth").Handler(newReverseProxy("http://user-service:8080"))
+	var store cache.Store
+	switch cfg.Cache.Backend {
+	case config.CacheBackendValkey:
+		client, err := valkey.NewClient(valkey.ClientOption{InitAddress: []string{cfg.Cache.Valkey.Addr}})
+		if err != nil {
+			log.Error("Failed to connect to Valkey", slog.String("error", err.Error()))
+			os.Exit(1)
+		}
+		defer client.Close()
+
+		checker.Register("valkey", func(ctx context.Context) error {
+			return client.Do(ctx, client.B().Ping().Build()).Error()
+		})
+		store = cache.NewValkeyStore(client, cfg.Cache.KeyPrefix)
+	default:
+		store = cache.NewMemoryStore(cfg.Cache.MaxEntries)
+	}
+	responseCache := cache.New(log, store)
+
+	r, err := newRouter(log, cfg, checker, metrics, responseCache)
+	if err != nil {
+		log.Error("Failed to build router", slog.String("error", err.Error()))
+		os.Exit(1)
+	}
+
+	handler := &reloadableHandler{}
+	handler.Store(r)
 
 	s := &http.Server{
 		Addr:         fmt.Sprintf("%s:%d", cfg.HTTP.Host, cfg.HTTP.Port),
-		Handler:      r,
+		Handler:      handler,
+		WriteTimeout: cfg.HTTP.WriteTimeout,
+		ReadTimeout:  cfg.HTTP.ReadTimeout,
+		IdleTimeout:  cfg.HTTP.IdleTimeout,
+	}
+
+	adminRouter := mux.NewRouter()
+	adminRouter.Handle("/metrics", promhttp.HandlerFor(reg, promhttp.HandlerOpts{Registry: reg})).Methods("GET")
+	adminRouter.Handle("/cache", responseCache.PurgeHandler()).Methods("DELETE")
+
+	admin := &http.Server{
+		Addr:         fmt.Sprintf("%s:%d", cfg.Admin.Host, cfg.Admin.Port),
+		Handler:      adminRouter,
 		WriteTimeout: time.Second * 15,
 		ReadTimeout:  time.Second * 15,
 		IdleTimeout:  time.Second * 60,
@@ -77,7 +113,10 @@ func Run(cfg *Config) {
 	stop := make(chan os.Signal, 1)
 	signal.Notify(stop, os.Interrupt, syscall.SIGTERM)
 
-	serverErrors := make(chan error, 1)
+	reload := make(chan os.Signal, 1)
+	signal.Notify(reload, syscall.SIGHUP)
+
+	serverErrors := make(chan error, 2)
 
 	go func() {
 		log.Info("Starting HTTP server", slog.String("addr", s.Addr))
@@ -87,22 +126,60 @@ func Run(cfg *Config) {
 		}
 	}()
 
-	select {
-	case err := <-serverErrors:
-		log.Error("Server error", slog.String("error", err.Error()))
-		os.Exit(1)
+	go func() {
+		log.Info("Starting admin HTTP server", slog.String("addr", admin.Addr))
+		err := admin.ListenAndServe()
+		if err != nil && !errors.Is(err, http.ErrServerClosed) {
+			serverErrors <- err
+		}
+	}()
+
+	for {
+		select {
+		case err := <-serverErrors:
+			log.Error("Server error", slog.String("error", err.Error()))
+			os.Exit(1)
+
+		case <-reload:
+			log.Info("Reloading routes", slog.String("config", cfg.Path()))
+
+			newCfg, err := config.Load(cfg.Path())
+			if err != nil {
+				log.Error("Config reload failed, keeping current routes", slog.String("error", err.Error()))
+				continue
+			}
+
+			r, err := newRouter(log, newCfg, checker, metrics, responseCache)
+			if err != nil {
+				log.Error("Router rebuild failed, keeping current routes", slog.String("error", err.Error()))
+				continue
+			}
+
+			handler.Store(r)
+			log.Info("Routes reloaded", slog.Int("routes", len(newCfg.Routes)))
+
+		case sig := <-stop:
+			log.Info("Received signal:", slog.String("signal", sig.String()))
+			log.Info("Shutting down gracefully...")
+
+			checker.Shutdown()
+			log.Info("Waiting for load balancers to drain", slog.Duration("delay", cfg.Health.DrainDelay))
+			time.Sleep(cfg.Health.DrainDelay)
+
+			tc, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+			defer cancel()
 
-	case sig := <-stop:
-		log.Info("Received signal:", slog.String("signal", sig.String()))
-		log.Info("Shutting down gracefully...")
+			if err := s.Shutdown(tc); err != nil {
+				log.Error("Graceful shutdown failed", slog.String("error", err.Error()))
+			} else {
+				log.Info("Server stopped.")
+			}
 
-		tc, cancel := context.WithTimeout(context.Background(), 30*time.Second)
-		defer cancel()
+			if err := admin.Shutdown(tc); err != nil {
+				log.Error("Admin server shutdown failed", slog.String("error", err.Error()))
+			}
 
-		if err := s.Shutdown(tc); err != nil {
-			log.Error("Graceful shutdown failed", slog.String("error", err.Error()))
-		} else {
-			log.Info("Server stopped.")
+			return
 		}
 	}
 }
@@ -0,0 +1,34 @@
+package app
+
+import (
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/SkySock/lode/libs/utils/jwt"
+)
+
+func requireAuth(log *slog.Logger, verifier *jwt.Verifier) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			raw, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+			if !ok || raw == "" {
+				unauthorized(w)
+				return
+			}
+
+			if _, err := verifier.Verify(raw); err != nil {
+				log.Debug("access token rejected", slog.String("error", err.Error()))
+				unauthorized(w)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+func unauthorized(w http.ResponseWriter) {
+	w.Header().Set("WWW-Authenticate", `Bearer realm="lode"`)
+	http.Error(w, "Unauthorized", http.StatusUnauthorized)
+}
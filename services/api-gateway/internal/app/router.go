@@ -0,0 +1,122 @@
+package app
+
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+	"slices"
+	"sync/atomic"
+
+	"github.com/SkySock/lode/libs/utils/http/health"
+	"github.com/SkySock/lode/libs/utils/http/middleware"
+	"github.com/SkySock/lode/libs/utils/jwt"
+	"github.com/SkySock/lode/services/api-gateway/internal/cache"
+	"github.com/SkySock/lode/services/api-gateway/internal/config"
+	"github.com/SkySock/lode/services/api-gateway/internal/proxy"
+	"github.com/gorilla/mux"
+)
+
+type router struct {
+	*mux.Router
+	handler http.Handler
+	proxies []*proxy.Proxy
+}
+
+// ServeHTTP runs the gateway-wide middlewares before mux, so that they also
+// cover preflight requests and unmatched paths.
+func (r *router) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	r.handler.ServeHTTP(w, req)
+}
+
+// Close stops background work of the route proxies, such as health checks.
+func (r *router) Close() {
+	for _, p := range r.proxies {
+		p.Close()
+	}
+}
+
+// reloadableHandler lets the router be swapped atomically on SIGHUP without
+// restarting the HTTP server.
+type reloadableHandler struct {
+	router atomic.Pointer[router]
+}
+
+func (h *reloadableHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.router.Load().ServeHTTP(w, r)
+}
+
+// Store installs the new router and closes the previous one.
+func (h *reloadableHandler) Store(r *router) {
+	if old := h.router.Swap(r); old != nil {
+		old.Close()
+	}
+}
+
+func newRouter(log *slog.Logger, cfg *config.Config, checker *health.Checker, metrics mux.MiddlewareFunc, responseCache *cache.Cache) (*router, error) {
+	r := &router{Router: mux.NewRouter()}
+
+	r.Handle("/healthz", checker.LivenessHandler()).Methods("GET")
+	r.Handle("/readyz", checker.ReadinessHandler()).Methods("GET")
+
+	// mux matches routes in registration order, so the most specific prefix goes first.
+	routes := slices.Clone(cfg.Routes)
+	slices.SortStableFunc(routes, func(a, b config.RouteConfig) int {
+		return len(b.PathPrefix) - len(a.PathPrefix)
+	})
+
+	for _, route := range routes {
+		p, err := proxy.New(log, route)
+		if err != nil {
+			r.Close()
+			return nil, fmt.Errorf("route %q: %w", route.Name, err)
+		}
+		r.proxies = append(r.proxies, p)
+
+		mr := r.PathPrefix(route.PathPrefix).Handler(newRouteHandler(log, route, cfg.Auth, responseCache, p)).Name(route.Name)
+		if len(route.Methods) > 0 {
+			mr.Methods(route.Methods...)
+		}
+	}
+
+	r.Use(metrics)
+	r.Use(middleware.Logging(log))
+
+	r.handler = r.Router
+	if len(cfg.CORS.AllowedOrigins) > 0 {
+		r.handler = middleware.CORS(middleware.CORSOptions{
+			AllowedOrigins:   cfg.CORS.AllowedOrigins,
+			AllowedMethods:   cfg.CORS.AllowedMethods,
+			AllowedHeaders:   cfg.CORS.AllowedHeaders,
+			ExposedHeaders:   cfg.CORS.ExposedHeaders,
+			AllowCredentials: cfg.CORS.AllowCredentials,
+			MaxAge:           cfg.CORS.MaxAge,
+		})(r.handler)
+	}
+	r.handler = middleware.SecurityHeaders(middleware.SecurityHeadersOptions{
+		HSTSMaxAge:            cfg.SecurityHeaders.HSTS.MaxAge,
+		HSTSIncludeSubdomains: cfg.SecurityHeaders.HSTS.IncludeSubdomains,
+		HSTSPreload:           cfg.SecurityHeaders.HSTS.Preload,
+		ContentSecurityPolicy: cfg.SecurityHeaders.ContentSecurityPolicy,
+		FrameOptions:          cfg.SecurityHeaders.FrameOptions,
+		ReferrerPolicy:        cfg.SecurityHeaders.ReferrerPolicy,
+	})(r.handler)
+
+	return r, nil
+}
+
+func newRouteHandler(log *slog.Logger, route config.RouteConfig, authCfg config.AuthConfig, responseCache *cache.Cache, handler http.Handler) http.Handler {
+	handler = middleware.Deadline(route.Timeout)(handler)
+	handler = responseCache.Middleware(route.Cache)(handler)
+
+	if route.Auth == config.AuthRequired {
+		verifier := jwt.NewVerifier([]byte(authCfg.AccessSecretKey),
+			jwt.WithAlgorithms(authCfg.Algorithms...),
+			jwt.WithIssuer(authCfg.Issuer),
+			jwt.WithAudience(authCfg.Audience),
+			jwt.WithLeeway(authCfg.ClockSkew),
+		)
+		handler = requireAuth(log, verifier)(handler)
+	}
+
+	return handler
+}
@@ -0,0 +1,290 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/SkySock/lode/libs/utils/http/response"
+	"github.com/SkySock/lode/services/api-gateway/internal/config"
+	"github.com/gorilla/mux"
+)
+
+// CacheStatusHeader tells clients how a response was served: HIT, MISS or
+// REVALIDATED.
+const CacheStatusHeader = "X-Cache"
+
+// storeTimeout bounds store calls so that a slow backend degrades to a miss
+// instead of delaying the request.
+const storeTimeout = 500 * time.Millisecond
+
+// Cache is a shared HTTP cache for GET and HEAD responses. It honors
+// Cache-Control, Expires, Vary and conditional requests with ETag or
+// Last-Modified validators.
+type Cache struct {
+	log   *slog.Logger
+	store Store
+
+	now func() time.Time
+}
+
+func New(log *slog.Logger, store Store) *Cache {
+	return &Cache{log: log, store: store, now: time.Now}
+}
+
+func (c *Cache) Middleware(route config.RouteCacheConfig) mux.MiddlewareFunc {
+	return func(next http.Handler) http.Handler {
+		if !route.Enabled {
+			return next
+		}
+
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			c.serveHTTP(w, r, next, route)
+		})
+	}
+}
+
+func (c *Cache) serveHTTP(w http.ResponseWriter, r *http.Request, next http.Handler, route config.RouteCacheConfig) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		next.ServeHTTP(w, r)
+		return
+	}
+
+	reqCC := parseCacheControl(r.Header.Values("Cache-Control"))
+	if reqCC.has("no-store") {
+		next.ServeHTTP(w, r)
+		return
+	}
+
+	key := Key(r.URL)
+	rec := c.load(r.Context(), key)
+
+	var cached *entry
+	if rec != nil {
+		cached = rec.lookup(r)
+	}
+
+	now := c.now()
+	maxAge, hasMaxAge := reqCC.seconds("max-age")
+	forceRevalidate := reqCC.has("no-cache") || r.Header.Get("Pragma") == "no-cache"
+
+	if cached != nil && cached.fresh(now) && !forceRevalidate && (!hasMaxAge || cached.age(now) <= maxAge) {
+		c.serveEntry(w, r, cached, now, "HIT")
+		return
+	}
+
+	if r.Method == http.MethodHead {
+		next.ServeHTTP(w, r)
+		return
+	}
+
+	// Conditionals of the client are answered by the cache itself, so the
+	// upstream is asked either for the full response or to validate our copy.
+	out := r.Clone(r.Context())
+	out.Header.Del("If-None-Match")
+	out.Header.Del("If-Modified-Since")
+
+	revalidating := cached != nil && cached.hasValidator()
+	if revalidating {
+		if etag := cached.Header.Get("ETag"); etag != "" {
+			out.Header.Set("If-None-Match", etag)
+		}
+		if lastModified := cached.Header.Get("Last-Modified"); lastModified != "" {
+			out.Header.Set("If-Modified-Since", lastModified)
+		}
+	}
+
+	cw := newCaptureWriter(w, route.MaxBodyBytes, revalidating)
+	next.ServeHTTP(cw, out)
+
+	now = c.now()
+
+	if cw.notModified {
+		refreshed := cached.refresh(cw.header, route.DefaultTTL, now)
+		rec.put(refreshed, rec.Vary, now)
+		c.save(r.Context(), key, rec, now)
+
+		c.serveEntry(w, r, refreshed, now, "REVALIDATED")
+		return
+	}
+
+	// An upstream failure in the middle of the body aborts the handler with a
+	// panic, so a response that got here was copied completely.
+	if cw.status == 0 || cw.overflow {
+		return
+	}
+
+	cc := parseCacheControl(cw.header.Values("Cache-Control"))
+	if !storable(r, cw.status, cw.header, cc) {
+		return
+	}
+
+	e := newEntry(r, cw.status, cw.header, cw.body.Bytes(), cc, route.DefaultTTL, now)
+	if e.Lifetime <= 0 && !e.hasValidator() {
+		return
+	}
+
+	if rec == nil {
+		rec = &record{}
+	}
+	rec.put(e, parseVary(cw.header), now)
+	c.save(r.Context(), key, rec, now)
+}
+
+func (c *Cache) load(ctx context.Context, key string) *record {
+	ctx, cancel := context.WithTimeout(ctx, storeTimeout)
+	defer cancel()
+
+	data, err := c.store.Get(ctx, key)
+	if err != nil {
+		if !errors.Is(err, ErrNotFound) {
+			c.log.Warn("Cache lookup failed", slog.String("key", key), slog.String("error", err.Error()))
+		}
+		return nil
+	}
+
+	rec, err := decodeRecord(data)
+	if err != nil {
+		c.log.Warn("Dropping undecodable cache record", slog.String("key", key), slog.String("error", err.Error()))
+		return nil
+	}
+
+	return rec
+}
+
+func (c *Cache) save(ctx context.Context, key string, rec *record, now time.Time) {
+	ttl := rec.ttl(now)
+	if ttl <= 0 {
+		return
+	}
+
+	data, err := json.Marshal(rec)
+	if err != nil {
+		c.log.Error("Cache record encoding failed", slog.String("key", key), slog.String("error", err.Error()))
+		return
+	}
+
+	// The response is already written, a client disconnect must not abort the store.
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), storeTimeout)
+	defer cancel()
+
+	if err := c.store.Set(ctx, key, data, ttl); err != nil {
+		c.log.Warn("Cache store failed", slog.String("key", key), slog.String("error", err.Error()))
+	}
+}
+
+func (c *Cache) serveEntry(w http.ResponseWriter, r *http.Request, e *entry, now time.Time, status string) {
+	h := w.Header()
+	for k, vv := range e.Header {
+		for _, v := range vv {
+			h.Add(k, v)
+		}
+	}
+	h.Set("Age", strconv.FormatInt(int64(e.age(now).Seconds()), 10))
+	h.Set(CacheStatusHeader, status)
+
+	if e.Status == http.StatusOK && notModified(r, e) {
+		h.Del("Content-Length")
+		h.Del("Content-Type")
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	h.Set("Content-Length", strconv.Itoa(len(e.Body)))
+	w.WriteHeader(e.Status)
+
+	if r.Method != http.MethodHead {
+		w.Write(e.Body)
+	}
+}
+
+func notModified(r *http.Request, e *entry) bool {
+	if inm := r.Header.Get("If-None-Match"); inm != "" {
+		return etagMatches(inm, e.Header.Get("ETag"))
+	}
+
+	ims, err := http.ParseTime(r.Header.Get("If-Modified-Since"))
+	if err != nil {
+		return false
+	}
+	lastModified, err := http.ParseTime(e.Header.Get("Last-Modified"))
+	if err != nil {
+		return false
+	}
+
+	return !lastModified.After(ims)
+}
+
+func newEntry(r *http.Request, status int, h http.Header, body []byte, cc directives, defaultTTL time.Duration, now time.Time) *entry {
+	header := h.Clone()
+	header.Del("Content-Length")
+	header.Del(CacheStatusHeader)
+
+	e := &entry{
+		Status:     status,
+		Header:     header,
+		Body:       body,
+		VaryValues: varyValues(r, parseVary(h)),
+		Lifetime:   freshnessLifetime(h, cc, defaultTTL),
+	}
+	e.setStoredAt(now)
+
+	return e
+}
+
+// refresh applies the headers of a 304 response to a copy of the entry.
+func (e *entry) refresh(h http.Header, defaultTTL time.Duration, now time.Time) *entry {
+	refreshed := *e
+	refreshed.Header = e.Header.Clone()
+	for k, v := range h {
+		if k == "Content-Length" || k == CacheStatusHeader {
+			continue
+		}
+		refreshed.Header[k] = slices.Clone(v)
+	}
+
+	cc := parseCacheControl(refreshed.Header.Values("Cache-Control"))
+	refreshed.Lifetime = freshnessLifetime(refreshed.Header, cc, defaultTTL)
+	refreshed.setStoredAt(now)
+
+	return &refreshed
+}
+
+// setStoredAt accounts for the time the response already spent in upstream
+// caches, as reported by the Age header.
+func (e *entry) setStoredAt(now time.Time) {
+	e.StoredAt = now
+	if age, err := strconv.ParseInt(e.Header.Get("Age"), 10, 64); err == nil && age > 0 {
+		e.StoredAt = now.Add(-time.Duration(age) * time.Second)
+	}
+	e.Header.Del("Age")
+}
+
+// PurgeHandler removes all variants of the URL given in the "key" query
+// parameter, e.g. DELETE /cache?key=/api/v1/profiles/alice.
+func (c *Cache) PurgeHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		raw := r.URL.Query().Get("key")
+		u, err := url.Parse(raw)
+		if raw == "" || err != nil {
+			response.WriteProblem(w, http.StatusBadRequest, "key must be a URL path")
+			return
+		}
+
+		key := Key(u)
+		if err := c.store.Delete(r.Context(), key); err != nil {
+			c.log.Error("Cache purge failed", slog.String("key", key), slog.String("error", err.Error()))
+			response.WriteProblem(w, http.StatusInternalServerError, "cache purge failed")
+			return
+		}
+
+		c.log.Info("Cache purged", slog.String("key", key))
+		w.WriteHeader(http.StatusNoContent)
+	})
+}
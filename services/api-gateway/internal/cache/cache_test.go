@@ -0,0 +1,167 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/SkySock/lode/services/api-gateway/internal/config"
+)
+
+type testUpstream struct {
+	calls   int
+	header  http.Header
+	body    string
+	lastReq *http.Request
+}
+
+func (u *testUpstream) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	u.calls++
+	u.lastReq = r
+
+	for k, v := range u.header {
+		w.Header()[k] = v
+	}
+
+	if etag := u.header.Get("ETag"); etag != "" && r.Header.Get("If-None-Match") == etag {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	io.WriteString(w, u.body+" "+r.Header.Get("Accept-Language"))
+}
+
+func newTestCache(t *testing.T, upstream http.Handler) (http.Handler, *time.Time) {
+	t.Helper()
+
+	now := time.Now()
+	c := New(slog.New(slog.NewTextHandler(io.Discard, nil)), NewMemoryStore(10))
+	c.now = func() time.Time { return now }
+
+	handler := c.Middleware(config.RouteCacheConfig{Enabled: true, MaxBodyBytes: 1 << 10})(upstream)
+
+	return handler, &now
+}
+
+func get(handler http.Handler, headers map[string]string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/profiles/alice?b=2&a=1", nil)
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	return rec
+}
+
+func TestCache(t *testing.T) {
+	t.Run("Miss then hit", func(t *testing.T) {
+		upstream := &testUpstream{header: http.Header{"Cache-Control": {"max-age=60"}}, body: "alice"}
+		handler, _ := newTestCache(t, upstream)
+
+		if rec := get(handler, nil); rec.Header().Get(CacheStatusHeader) != "MISS" {
+			t.Errorf("Expected MISS, got %q", rec.Header().Get(CacheStatusHeader))
+		}
+
+		rec := get(handler, nil)
+		if rec.Header().Get(CacheStatusHeader) != "HIT" {
+			t.Errorf("Expected HIT, got %q", rec.Header().Get(CacheStatusHeader))
+		}
+		if rec.Body.String() != "alice " {
+			t.Errorf("Wrong cached body: %q", rec.Body.String())
+		}
+		if upstream.calls != 1 {
+			t.Errorf("Upstream called %d times, want 1", upstream.calls)
+		}
+	})
+	t.Run("Not storable", func(t *testing.T) {
+		for _, cc := range []string{"no-store", "private, max-age=60", ""} {
+			upstream := &testUpstream{header: http.Header{"Cache-Control": {cc}}}
+			handler, _ := newTestCache(t, upstream)
+
+			get(handler, nil)
+			get(handler, nil)
+			if upstream.calls != 2 {
+				t.Errorf("Cache-Control %q: upstream called %d times, want 2", cc, upstream.calls)
+			}
+		}
+	})
+	t.Run("Conditional request answered from cache", func(t *testing.T) {
+		upstream := &testUpstream{header: http.Header{"Cache-Control": {"max-age=60"}, "Etag": {`"v1"`}}}
+		handler, _ := newTestCache(t, upstream)
+
+		get(handler, nil)
+		rec := get(handler, map[string]string{"If-None-Match": `W/"v1"`})
+		if rec.Code != http.StatusNotModified {
+			t.Errorf("Wrong status code: got %d, want %d", rec.Code, http.StatusNotModified)
+		}
+		if upstream.calls != 1 {
+			t.Errorf("Upstream called %d times, want 1", upstream.calls)
+		}
+	})
+	t.Run("Stale entry is revalidated", func(t *testing.T) {
+		upstream := &testUpstream{header: http.Header{"Cache-Control": {"max-age=60"}, "Etag": {`"v1"`}}, body: "alice"}
+		handler, now := newTestCache(t, upstream)
+
+		get(handler, nil)
+		*now = now.Add(2 * time.Minute)
+
+		rec := get(handler, nil)
+		if rec.Header().Get(CacheStatusHeader) != "REVALIDATED" {
+			t.Errorf("Expected REVALIDATED, got %q", rec.Header().Get(CacheStatusHeader))
+		}
+		if rec.Code != http.StatusOK || rec.Body.String() != "alice " {
+			t.Errorf("Wrong revalidated response: %d %q", rec.Code, rec.Body.String())
+		}
+		if upstream.lastReq.Header.Get("If-None-Match") != `"v1"` {
+			t.Error("Revalidation should send the cached ETag")
+		}
+
+		if rec := get(handler, nil); rec.Header().Get(CacheStatusHeader) != "HIT" {
+			t.Errorf("Revalidated entry should be fresh again, got %q", rec.Header().Get(CacheStatusHeader))
+		}
+	})
+	t.Run("Variants", func(t *testing.T) {
+		upstream := &testUpstream{header: http.Header{"Cache-Control": {"max-age=60"}, "Vary": {"Accept-Language"}}, body: "alice"}
+		handler, _ := newTestCache(t, upstream)
+
+		get(handler, map[string]string{"Accept-Language": "en"})
+		get(handler, map[string]string{"Accept-Language": "ru"})
+
+		rec := get(handler, map[string]string{"Accept-Language": "ru"})
+		if rec.Header().Get(CacheStatusHeader) != "HIT" || rec.Body.String() != "alice ru" {
+			t.Errorf("Wrong variant served: %q %q", rec.Header().Get(CacheStatusHeader), rec.Body.String())
+		}
+		if upstream.calls != 2 {
+			t.Errorf("Upstream called %d times, want 2", upstream.calls)
+		}
+	})
+}
+
+func TestMemoryStoreEviction(t *testing.T) {
+	ctx := context.Background()
+	s := NewMemoryStore(2)
+
+	s.Set(ctx, "a", []byte("a"), time.Minute)
+	s.Set(ctx, "b", []byte("b"), time.Minute)
+	s.Get(ctx, "a")
+	s.Set(ctx, "c", []byte("c"), time.Minute)
+
+	if _, err := s.Get(ctx, "b"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Least recently used key should be evicted, got %v", err)
+	}
+	if _, err := s.Get(ctx, "a"); err != nil {
+		t.Errorf("Recently used key should stay: %v", err)
+	}
+
+	s.now = func() time.Time { return time.Now().Add(2 * time.Minute) }
+	if _, err := s.Get(ctx, "c"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expired key should be dropped, got %v", err)
+	}
+}
@@ -0,0 +1,254 @@
+package cache
+
+import (
+	"encoding/json"
+	"maps"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// maxVariants bounds the number of Vary variants kept per URL.
+	maxVariants = 8
+	// staleRetention is how long an expired entry with a validator is kept
+	// around so that it can be revalidated instead of refetched.
+	staleRetention = 10 * time.Minute
+)
+
+// Statuses a shared cache may store (RFC 9110, section 15.1).
+var cacheableStatuses = []int{
+	http.StatusOK, http.StatusNonAuthoritativeInfo, http.StatusNoContent,
+	http.StatusMultipleChoices, http.StatusMovedPermanently, http.StatusNotFound,
+	http.StatusMethodNotAllowed, http.StatusGone, http.StatusRequestURITooLong,
+	http.StatusNotImplemented,
+}
+
+type entry struct {
+	Status     int               `json:"status"`
+	Header     http.Header       `json:"header"`
+	Body       []byte            `json:"body"`
+	VaryValues map[string]string `json:"vary_values,omitempty"`
+	StoredAt   time.Time         `json:"stored_at"`
+	Lifetime   time.Duration     `json:"lifetime"`
+}
+
+func (e *entry) age(now time.Time) time.Duration {
+	return max(now.Sub(e.StoredAt), 0)
+}
+
+func (e *entry) fresh(now time.Time) bool {
+	return e.age(now) < e.Lifetime
+}
+
+func (e *entry) hasValidator() bool {
+	return e.Header.Get("ETag") != "" || e.Header.Get("Last-Modified") != ""
+}
+
+func (e *entry) expiresAt() time.Time {
+	expiresAt := e.StoredAt.Add(e.Lifetime)
+	if e.hasValidator() {
+		expiresAt = expiresAt.Add(staleRetention)
+	}
+
+	return expiresAt
+}
+
+// record holds all variants of a URL. Every variant was selected by the same
+// Vary header names.
+type record struct {
+	Vary     []string `json:"vary"`
+	Variants []*entry `json:"variants"`
+}
+
+func (rec *record) lookup(r *http.Request) *entry {
+	values := varyValues(r, rec.Vary)
+	for _, e := range rec.Variants {
+		if maps.Equal(e.VaryValues, values) {
+			return e
+		}
+	}
+
+	return nil
+}
+
+// put stores e, replacing the variant with the same Vary values. All variants
+// are dropped when the upstream changed the set of Vary headers.
+func (rec *record) put(e *entry, vary []string, now time.Time) {
+	if !slices.Equal(rec.Vary, vary) {
+		rec.Vary = vary
+		rec.Variants = nil
+	}
+
+	rec.Variants = slices.DeleteFunc(rec.Variants, func(v *entry) bool {
+		return maps.Equal(v.VaryValues, e.VaryValues) || !now.Before(v.expiresAt())
+	})
+	rec.Variants = append(rec.Variants, e)
+
+	if len(rec.Variants) > maxVariants {
+		rec.Variants = rec.Variants[len(rec.Variants)-maxVariants:]
+	}
+}
+
+func (rec *record) ttl(now time.Time) time.Duration {
+	var expiresAt time.Time
+	for _, e := range rec.Variants {
+		if t := e.expiresAt(); t.After(expiresAt) {
+			expiresAt = t
+		}
+	}
+
+	return expiresAt.Sub(now)
+}
+
+func decodeRecord(data []byte) (*record, error) {
+	var rec record
+	if err := json.Unmarshal(data, &rec); err != nil {
+		return nil, err
+	}
+
+	return &rec, nil
+}
+
+// Key builds the cache key of a URL: the path with query parameters sorted,
+// so that equivalent URLs share an entry.
+func Key(u *url.URL) string {
+	key := u.EscapedPath()
+	if q := u.Query(); len(q) > 0 {
+		key += "?" + q.Encode()
+	}
+
+	return key
+}
+
+type directives map[string]string
+
+func parseCacheControl(values []string) directives {
+	d := make(directives)
+	for _, value := range values {
+		for _, part := range strings.Split(value, ",") {
+			name, arg, _ := strings.Cut(strings.TrimSpace(part), "=")
+			if name == "" {
+				continue
+			}
+			d[strings.ToLower(name)] = strings.Trim(arg, `"`)
+		}
+	}
+
+	return d
+}
+
+func (d directives) has(name string) bool {
+	_, ok := d[name]
+	return ok
+}
+
+func (d directives) seconds(name string) (time.Duration, bool) {
+	arg, ok := d[name]
+	if !ok {
+		return 0, false
+	}
+
+	n, err := strconv.ParseInt(arg, 10, 64)
+	if err != nil || n < 0 {
+		return 0, true
+	}
+
+	return time.Duration(n) * time.Second, true
+}
+
+// freshnessLifetime computes how long a response stays fresh in a shared
+// cache. defaultTTL only applies when the response has no explicit freshness.
+func freshnessLifetime(h http.Header, cc directives, defaultTTL time.Duration) time.Duration {
+	if cc.has("no-cache") {
+		return 0
+	}
+	if d, ok := cc.seconds("s-maxage"); ok {
+		return d
+	}
+	if d, ok := cc.seconds("max-age"); ok {
+		return d
+	}
+
+	if expires := h.Get("Expires"); expires != "" {
+		exp, err := http.ParseTime(expires)
+		if err != nil {
+			return 0
+		}
+		date, err := http.ParseTime(h.Get("Date"))
+		if err != nil {
+			date = time.Now()
+		}
+		return max(exp.Sub(date), 0)
+	}
+
+	return defaultTTL
+}
+
+// storable reports whether a response to r may be stored by a shared cache.
+func storable(r *http.Request, status int, h http.Header, cc directives) bool {
+	if !slices.Contains(cacheableStatuses, status) {
+		return false
+	}
+	if cc.has("no-store") || cc.has("private") {
+		return false
+	}
+	if h.Get("Set-Cookie") != "" || h.Get("Vary") == "*" {
+		return false
+	}
+
+	if r.Header.Get("Authorization") != "" &&
+		!cc.has("public") && !cc.has("s-maxage") && !cc.has("must-revalidate") {
+		return false
+	}
+
+	return true
+}
+
+func parseVary(h http.Header) []string {
+	var names []string
+	for _, value := range h.Values("Vary") {
+		for _, name := range strings.Split(value, ",") {
+			if name = strings.TrimSpace(name); name != "" {
+				names = append(names, http.CanonicalHeaderKey(name))
+			}
+		}
+	}
+
+	slices.Sort(names)
+
+	return slices.Compact(names)
+}
+
+func varyValues(r *http.Request, vary []string) map[string]string {
+	if len(vary) == 0 {
+		return nil
+	}
+
+	values := make(map[string]string, len(vary))
+	for _, name := range vary {
+		values[name] = strings.Join(r.Header.Values(name), ",")
+	}
+
+	return values
+}
+
+// etagMatches implements the weak comparison used for If-None-Match.
+func etagMatches(ifNoneMatch, etag string) bool {
+	if etag == "" {
+		return false
+	}
+
+	etag = strings.TrimPrefix(etag, "W/")
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == etag {
+			return true
+		}
+	}
+
+	return false
+}
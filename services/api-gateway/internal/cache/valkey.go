@@ -0,0 +1,54 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/valkey-io/valkey-go"
+)
+
+// ValkeyStore shares cached records between gateway replicas.
+type ValkeyStore struct {
+	client valkey.Client
+	prefix string
+}
+
+func NewValkeyStore(client valkey.Client, prefix string) *ValkeyStore {
+	return &ValkeyStore{client: client, prefix: prefix}
+}
+
+var _ Store = (*ValkeyStore)(nil)
+
+func (s *ValkeyStore) Get(ctx context.Context, key string) ([]byte, error) {
+	value, err := s.client.Do(ctx, s.client.B().Get().Key(s.key(key)).Build()).AsBytes()
+	if err != nil {
+		if valkey.IsValkeyNil(err) {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("cache: get: %w", err)
+	}
+
+	return value, nil
+}
+
+func (s *ValkeyStore) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	cmd := s.client.B().Set().Key(s.key(key)).Value(valkey.BinaryString(value)).Px(ttl).Build()
+	if err := s.client.Do(ctx, cmd).Error(); err != nil {
+		return fmt.Errorf("cache: set: %w", err)
+	}
+
+	return nil
+}
+
+func (s *ValkeyStore) Delete(ctx context.Context, key string) error {
+	if err := s.client.Do(ctx, s.client.B().Del().Key(s.key(key)).Build()).Error(); err != nil {
+		return fmt.Errorf("cache: delete: %w", err)
+	}
+
+	return nil
+}
+
+func (s *ValkeyStore) key(key string) string {
+	return fmt.Sprintf("%s:%s", s.prefix, key)
+}
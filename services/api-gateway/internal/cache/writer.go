@@ -0,0 +1,76 @@
+package cache
+
+import (
+	"bytes"
+	"net/http"
+)
+
+// captureWriter streams the upstream response to the client while keeping a
+// copy of it for the cache. When the cache revalidates its own copy, a 304
+// from the upstream is swallowed so that the cached response can be served
+// instead.
+type captureWriter struct {
+	w      http.ResponseWriter
+	header http.Header
+
+	status      int
+	body        bytes.Buffer
+	limit       int64
+	overflow    bool
+	intercept   bool
+	notModified bool
+}
+
+func newCaptureWriter(w http.ResponseWriter, limit int64, intercept304 bool) *captureWriter {
+	return &captureWriter{w: w, header: make(http.Header), limit: limit, intercept: intercept304}
+}
+
+func (cw *captureWriter) Header() http.Header {
+	return cw.header
+}
+
+func (cw *captureWriter) WriteHeader(status int) {
+	if cw.status != 0 {
+		return
+	}
+	cw.status = status
+
+	if status == http.StatusNotModified && cw.intercept {
+		cw.notModified = true
+		return
+	}
+
+	h := cw.w.Header()
+	for k, vv := range cw.header {
+		for _, v := range vv {
+			h.Add(k, v)
+		}
+	}
+	h.Set(CacheStatusHeader, "MISS")
+
+	cw.w.WriteHeader(status)
+}
+
+func (cw *captureWriter) Write(b []byte) (int, error) {
+	if cw.status == 0 {
+		cw.WriteHeader(http.StatusOK)
+	}
+	if cw.notModified {
+		return len(b), nil
+	}
+
+	if !cw.overflow {
+		if int64(cw.body.Len()+len(b)) > cw.limit {
+			cw.overflow = true
+			cw.body = bytes.Buffer{}
+		} else {
+			cw.body.Write(b)
+		}
+	}
+
+	return cw.w.Write(b)
+}
+
+func (cw *captureWriter) Unwrap() http.ResponseWriter {
+	return cw.w
+}
@@ -0,0 +1,526 @@
+package config
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/ilyakaznacheev/cleanenv"
+)
+
+const (
+	AuthPublic   = "public"
+	AuthRequired = "required"
+
+	BalanceRoundRobin       = "round_robin"
+	BalanceLeastConnections = "least_connections"
+
+	CacheBackendMemory = "memory"
+	CacheBackendValkey = "valkey"
+)
+
+type Config struct {
+	Env    string       `yaml:"env" env-default:"local"`
+	HTTP   HTTPConfig   `yaml:"http"`
+	Admin  AdminConfig  `yaml:"admin"`
+	Health HealthConfig `yaml:"health"`
+	Auth   AuthConfig   `yaml:"auth"`
+	CORS   CORSConfig   `yaml:"cors"`
+	Cache  CacheConfig  `yaml:"cache"`
+
+	SecurityHeaders SecurityHeadersConfig `yaml:"security_headers"`
+	Routes          []RouteConfig         `yaml:"routes"`
+
+	path string
+}
+
+type HTTPConfig struct {
+	Host         string        `yaml:"host" env-default:"127.0.0.1"`
+	Port         uint16        `yaml:"port" env-default:"8000"`
+	ReadTimeout  time.Duration `yaml:"read_timeout" env-default:"15s"`
+	WriteTimeout time.Duration `yaml:"write_timeout" env-default:"15s"`
+	IdleTimeout  time.Duration `yaml:"idle_timeout" env-default:"60s"`
+}
+
+type AdminConfig struct {
+	Host string `yaml:"host" env-default:"127.0.0.1"`
+	Port uint16 `yaml:"port" env-default:"9090"`
+}
+
+type HealthConfig struct {
+	CheckTimeout time.Duration `yaml:"check_timeout" env-default:"2s"`
+	DrainDelay   time.Duration `yaml:"drain_delay" env-default:"5s"`
+}
+
+// AuthConfig verifies access tokens issued by the user-service. An empty
+// Issuer or Audience is not checked. Algorithms lists the accepted signing
+// algorithms and defaults to HS256.
+type AuthConfig struct {
+	AccessSecretKey string        `yaml:"access_secret"`
+	Algorithms      []string      `yaml:"algorithms"`
+	Issuer          string        `yaml:"issuer" env-default:"user-service"`
+	Audience        string        `yaml:"audience"`
+	ClockSkew       time.Duration `yaml:"clock_skew" env-default:"30s"`
+}
+
+// CORSConfig configures cross-origin access for browser clients. CORS is
+// disabled when AllowedOrigins is empty.
+type CORSConfig struct {
+	AllowedOrigins   []string      `yaml:"allowed_origins"`
+	AllowedMethods   []string      `yaml:"allowed_methods"`
+	AllowedHeaders   []string      `yaml:"allowed_headers"`
+	ExposedHeaders   []string      `yaml:"exposed_headers"`
+	AllowCredentials bool          `yaml:"allow_credentials"`
+	MaxAge           time.Duration `yaml:"max_age"`
+}
+
+type SecurityHeadersConfig struct {
+	HSTS                  HSTSConfig `yaml:"hsts"`
+	ContentSecurityPolicy string     `yaml:"content_security_policy"`
+	FrameOptions          string     `yaml:"frame_options"`
+	ReferrerPolicy        string     `yaml:"referrer_policy"`
+}
+
+// HSTSConfig configures Strict-Transport-Security. The header is not sent
+// when MaxAge is zero.
+type HSTSConfig struct {
+	MaxAge            time.Duration `yaml:"max_age"`
+	IncludeSubdomains bool          `yaml:"include_subdomains"`
+	Preload           bool          `yaml:"preload"`
+}
+
+// CacheConfig selects the response cache backend shared by all routes. It is
+// read once at startup and not changed by reloads.
+type CacheConfig struct {
+	Backend    string       `yaml:"backend" env-default:"memory"`
+	MaxEntries int          `yaml:"max_entries" env-default:"10000"`
+	Valkey     ValkeyConfig `yaml:"valkey"`
+	KeyPrefix  string       `yaml:"key_prefix" env-default:"gateway_cache"`
+}
+
+type ValkeyConfig struct {
+	Addr string `yaml:"addr"`
+}
+
+type RouteConfig struct {
+	Name          string        `yaml:"name"`
+	PathPrefix    string        `yaml:"path_prefix"`
+	Methods       []string      `yaml:"methods"`
+	Upstreams     []string      `yaml:"upstreams"`
+	StripPrefix   bool          `yaml:"strip_prefix"`
+	RewritePrefix string        `yaml:"rewrite_prefix"`
+	Auth          string        `yaml:"auth"`
+	Timeout       time.Duration `yaml:"timeout"`
+
+	LoadBalancing    string                 `yaml:"load_balancing"`
+	HealthCheck      HealthCheckConfig      `yaml:"health_check"`
+	OutlierDetection OutlierDetectionConfig `yaml:"outlier_detection"`
+	CircuitBreaker   CircuitBreakerConfig   `yaml:"circuit_breaker"`
+	Retry            RetryConfig            `yaml:"retry"`
+	Hedge            HedgeConfig            `yaml:"hedge"`
+	Cache            RouteCacheConfig       `yaml:"cache"`
+}
+
+// HealthCheckConfig configures active probing of upstreams. Probing is
+// disabled when Path is empty.
+type HealthCheckConfig struct {
+	Path               string        `yaml:"path"`
+	Interval           time.Duration `yaml:"interval"`
+	Timeout            time.Duration `yaml:"timeout"`
+	HealthyThreshold   int           `yaml:"healthy_threshold"`
+	UnhealthyThreshold int           `yaml:"unhealthy_threshold"`
+}
+
+// OutlierDetectionConfig configures passive ejection of upstreams that keep
+// failing real traffic. Detection is disabled when ConsecutiveFailures is zero.
+type OutlierDetectionConfig struct {
+	ConsecutiveFailures int           `yaml:"consecutive_failures"`
+	EjectionTime        time.Duration `yaml:"ejection_time"`
+	MaxEjectionPercent  int           `yaml:"max_ejection_percent"`
+}
+
+// CircuitBreakerConfig configures the per-route breaker. The breaker is
+// disabled when FailureThreshold is zero.
+type CircuitBreakerConfig struct {
+	FailureThreshold int           `yaml:"failure_threshold"`
+	OpenTimeout      time.Duration `yaml:"open_timeout"`
+	HalfOpenRequests int           `yaml:"half_open_requests"`
+}
+
+// RetryConfig configures upstream retries. Idempotent requests are retried on
+// errors, per-try timeouts and 502/503/504; other requests only when the
+// connection could not be established. Retries are disabled when Attempts is zero.
+type RetryConfig struct {
+	Attempts      int           `yaml:"attempts"`
+	PerTryTimeout time.Duration `yaml:"per_try_timeout"`
+	Backoff       time.Duration `yaml:"backoff"`
+	BudgetPercent int           `yaml:"budget_percent"`
+	MinRetries    int           `yaml:"min_retries"`
+	MaxBodyBytes  int64         `yaml:"max_body_bytes"`
+}
+
+// HedgeConfig configures hedged GET and HEAD requests: when the first attempt
+// has not answered within Delay, a second one is sent to another upstream.
+// Hedging is disabled when Delay is zero.
+type HedgeConfig struct {
+	Delay time.Duration `yaml:"delay"`
+}
+
+// RouteCacheConfig enables response caching for GET and HEAD requests of a
+// route. Freshness comes from the upstream's Cache-Control or Expires;
+// DefaultTTL only applies to responses that carry neither.
+type RouteCacheConfig struct {
+	Enabled      bool          `yaml:"enabled"`
+	DefaultTTL   time.Duration `yaml:"default_ttl"`
+	MaxBodyBytes int64         `yaml:"max_body_bytes"`
+}
+
+func MustLoad() *Config {
+	path := fetchConfigPath()
+	if path == "" {
+		panic("config path is empty")
+	}
+
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		panic("config file does not exist: " + path)
+	}
+
+	cfg, err := Load(path)
+	if err != nil {
+		panic("failed to load config: " + err.Error())
+	}
+
+	return cfg
+}
+
+func Load(path string) (*Config, error) {
+	var cfg Config
+
+	if err := cleanenv.ReadConfig(path, &cfg); err != nil {
+		return nil, fmt.Errorf("failed to read config: %w", err)
+	}
+
+	cfg.Auth.setDefaults()
+	cfg.CORS.setDefaults()
+	for i := range cfg.Routes {
+		cfg.Routes[i].setDefaults()
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid config: %w", err)
+	}
+	cfg.path = path
+
+	return &cfg, nil
+}
+
+// Path returns the file the config was loaded from.
+func (c *Config) Path() string {
+	return c.path
+}
+
+func (c *Config) Validate() error {
+	if len(c.Routes) == 0 {
+		return errors.New("no routes configured")
+	}
+
+	if err := c.CORS.Validate(); err != nil {
+		return fmt.Errorf("cors: %w", err)
+	}
+	if err := c.SecurityHeaders.Validate(); err != nil {
+		return fmt.Errorf("security_headers: %w", err)
+	}
+	if err := c.Cache.Validate(); err != nil {
+		return fmt.Errorf("cache: %w", err)
+	}
+	if err := c.Auth.Validate(); err != nil {
+		return fmt.Errorf("auth: %w", err)
+	}
+
+	names := make(map[string]struct{}, len(c.Routes))
+	for i, route := range c.Routes {
+		if route.Name == "" {
+			return fmt.Errorf("route #%d: name is required", i)
+		}
+		if _, ok := names[route.Name]; ok {
+			return fmt.Errorf("route %q: duplicate name", route.Name)
+		}
+		names[route.Name] = struct{}{}
+
+		if err := route.Validate(); err != nil {
+			return fmt.Errorf("route %q: %w", route.Name, err)
+		}
+
+		if route.Auth == AuthRequired && c.Auth.AccessSecretKey == "" {
+			return fmt.Errorf("route %q: auth is required but access_secret is not set", route.Name)
+		}
+
+		if c.HTTP.WriteTimeout > 0 && route.Timeout > c.HTTP.WriteTimeout {
+			return fmt.Errorf("route %q: timeout %s exceeds http.write_timeout %s", route.Name, route.Timeout, c.HTTP.WriteTimeout)
+		}
+	}
+
+	return nil
+}
+
+// Validate only accepts HMAC algorithms, as tokens are verified with a shared
+// secret.
+func (c *AuthConfig) Validate() error {
+	for _, alg := range c.Algorithms {
+		if !slices.Contains([]string{"HS256", "HS384", "HS512"}, alg) {
+			return fmt.Errorf("unsupported algorithm %q", alg)
+		}
+	}
+	return nil
+}
+
+func (c *CORSConfig) Validate() error {
+	for _, origin := range c.AllowedOrigins {
+		if origin == "*" {
+			// Reflecting any origin together with cookies would let every
+			// site act on behalf of the user.
+			if c.AllowCredentials {
+				return errors.New("allowed_origins \"*\" cannot be combined with allow_credentials")
+			}
+			continue
+		}
+		if strings.Count(origin, "*") > 1 {
+			return fmt.Errorf("origin pattern %q: at most one wildcard is allowed", origin)
+		}
+		if !strings.HasPrefix(origin, "http://") && !strings.HasPrefix(origin, "https://") {
+			return fmt.Errorf("origin %q: scheme is required", origin)
+		}
+	}
+
+	if c.MaxAge < 0 {
+		return fmt.Errorf("max_age must not be negative: %s", c.MaxAge)
+	}
+
+	return nil
+}
+
+func (c *SecurityHeadersConfig) Validate() error {
+	if c.HSTS.MaxAge < 0 {
+		return fmt.Errorf("hsts.max_age must not be negative: %s", c.HSTS.MaxAge)
+	}
+
+	switch strings.ToUpper(c.FrameOptions) {
+	case "", "DENY", "SAMEORIGIN":
+	default:
+		return fmt.Errorf("frame_options must be DENY or SAMEORIGIN, got %q", c.FrameOptions)
+	}
+
+	return nil
+}
+
+func (c *CacheConfig) Validate() error {
+	switch c.Backend {
+	case CacheBackendMemory:
+		if c.MaxEntries < 1 {
+			return fmt.Errorf("max_entries must be positive, got %d", c.MaxEntries)
+		}
+	case CacheBackendValkey:
+		if c.Valkey.Addr == "" {
+			return errors.New("valkey.addr is required for the valkey backend")
+		}
+	default:
+		return fmt.Errorf("backend must be %q or %q, got %q", CacheBackendMemory, CacheBackendValkey, c.Backend)
+	}
+
+	return nil
+}
+
+func (r *RouteConfig) Validate() error {
+	if !strings.HasPrefix(r.PathPrefix, "/") {
+		return fmt.Errorf("path_prefix must start with '/': %q", r.PathPrefix)
+	}
+
+	for _, method := range r.Methods {
+		switch method {
+		case http.MethodGet, http.MethodHead, http.MethodPost, http.MethodPut,
+			http.MethodPatch, http.MethodDelete, http.MethodOptions:
+		default:
+			return fmt.Errorf("unsupported method: %q", method)
+		}
+	}
+
+	if len(r.Upstreams) == 0 {
+		return errors.New("at least one upstream is required")
+	}
+	for _, upstream := range r.Upstreams {
+		u, err := url.Parse(upstream)
+		if err != nil {
+			return fmt.Errorf("invalid upstream %q: %w", upstream, err)
+		}
+		if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("invalid upstream %q: absolute http(s) URL expected", upstream)
+		}
+	}
+
+	if r.StripPrefix && r.RewritePrefix != "" {
+		return errors.New("strip_prefix and rewrite_prefix are mutually exclusive")
+	}
+	if r.RewritePrefix != "" && !strings.HasPrefix(r.RewritePrefix, "/") {
+		return fmt.Errorf("rewrite_prefix must start with '/': %q", r.RewritePrefix)
+	}
+
+	switch r.Auth {
+	case AuthPublic, AuthRequired:
+	default:
+		return fmt.Errorf("auth must be %q or %q, got %q", AuthPublic, AuthRequired, r.Auth)
+	}
+
+	if r.Timeout < 0 {
+		return fmt.Errorf("timeout must not be negative: %s", r.Timeout)
+	}
+
+	switch r.LoadBalancing {
+	case BalanceRoundRobin, BalanceLeastConnections:
+	default:
+		return fmt.Errorf("load_balancing must be %q or %q, got %q", BalanceRoundRobin, BalanceLeastConnections, r.LoadBalancing)
+	}
+
+	if r.HealthCheck.Path != "" {
+		if !strings.HasPrefix(r.HealthCheck.Path, "/") {
+			return fmt.Errorf("health_check.path must start with '/': %q", r.HealthCheck.Path)
+		}
+		if r.HealthCheck.Interval <= 0 || r.HealthCheck.Timeout <= 0 {
+			return errors.New("health_check interval and timeout must be positive")
+		}
+		if r.HealthCheck.HealthyThreshold < 1 || r.HealthCheck.UnhealthyThreshold < 1 {
+			return errors.New("health_check thresholds must be at least 1")
+		}
+	}
+
+	if r.OutlierDetection.ConsecutiveFailures < 0 {
+		return errors.New("outlier_detection.consecutive_failures must not be negative")
+	}
+	if p := r.OutlierDetection.MaxEjectionPercent; p < 0 || p > 100 {
+		return fmt.Errorf("outlier_detection.max_ejection_percent must be within [0, 100], got %d", p)
+	}
+
+	if r.CircuitBreaker.FailureThreshold < 0 {
+		return errors.New("circuit_breaker.failure_threshold must not be negative")
+	}
+
+	if r.Retry.Attempts < 0 || r.Retry.PerTryTimeout < 0 || r.Retry.Backoff < 0 || r.Retry.MaxBodyBytes < 0 {
+		return errors.New("retry settings must not be negative")
+	}
+	if p := r.Retry.BudgetPercent; p < 0 || p > 100 {
+		return fmt.Errorf("retry.budget_percent must be within [0, 100], got %d", p)
+	}
+	if r.Timeout > 0 && r.Retry.PerTryTimeout > r.Timeout {
+		return fmt.Errorf("retry.per_try_timeout %s exceeds route timeout %s", r.Retry.PerTryTimeout, r.Timeout)
+	}
+
+	if r.Hedge.Delay < 0 {
+		return errors.New("hedge.delay must not be negative")
+	}
+
+	if r.Cache.Enabled {
+		if len(r.Methods) > 0 && !slices.Contains(r.Methods, http.MethodGet) {
+			return errors.New("cache requires the route to serve GET")
+		}
+		if r.Cache.DefaultTTL < 0 || r.Cache.MaxBodyBytes < 0 {
+			return errors.New("cache settings must not be negative")
+		}
+	}
+
+	return nil
+}
+
+func (c *AuthConfig) setDefaults() {
+	if len(c.Algorithms) == 0 {
+		c.Algorithms = []string{"HS256"}
+	}
+}
+
+func (c *CORSConfig) setDefaults() {
+	if len(c.AllowedOrigins) == 0 {
+		return
+	}
+
+	if len(c.AllowedMethods) == 0 {
+		c.AllowedMethods = []string{http.MethodGet, http.MethodHead, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete}
+	}
+	if len(c.AllowedHeaders) == 0 {
+		c.AllowedHeaders = []string{"Authorization", "Content-Type"}
+	}
+}
+
+func (r *RouteConfig) setDefaults() {
+	if r.LoadBalancing == "" {
+		r.LoadBalancing = BalanceRoundRobin
+	}
+
+	if r.HealthCheck.Path != "" {
+		if r.HealthCheck.Interval == 0 {
+			r.HealthCheck.Interval = 10 * time.Second
+		}
+		if r.HealthCheck.Timeout == 0 {
+			r.HealthCheck.Timeout = 2 * time.Second
+		}
+		if r.HealthCheck.HealthyThreshold == 0 {
+			r.HealthCheck.HealthyThreshold = 2
+		}
+		if r.HealthCheck.UnhealthyThreshold == 0 {
+			r.HealthCheck.UnhealthyThreshold = 3
+		}
+	}
+
+	if r.OutlierDetection.ConsecutiveFailures > 0 {
+		if r.OutlierDetection.EjectionTime == 0 {
+			r.OutlierDetection.EjectionTime = 30 * time.Second
+		}
+		if r.OutlierDetection.MaxEjectionPercent == 0 {
+			r.OutlierDetection.MaxEjectionPercent = 50
+		}
+	}
+
+	if r.Retry.Attempts > 0 || r.Hedge.Delay > 0 {
+		if r.Retry.Backoff == 0 {
+			r.Retry.Backoff = 25 * time.Millisecond
+		}
+		if r.Retry.BudgetPercent == 0 {
+			r.Retry.BudgetPercent = 20
+		}
+		if r.Retry.MinRetries == 0 {
+			r.Retry.MinRetries = 3
+		}
+		if r.Retry.MaxBodyBytes == 0 {
+			r.Retry.MaxBodyBytes = 64 << 10
+		}
+	}
+
+	if r.Cache.Enabled && r.Cache.MaxBodyBytes == 0 {
+		r.Cache.MaxBodyBytes = 1 << 20
+	}
+
+	if r.CircuitBreaker.FailureThreshold > 0 {
+		if r.CircuitBreaker.OpenTimeout == 0 {
+			r.CircuitBreaker.OpenTimeout = 30 * time.Second
+		}
+		if r.CircuitBreaker.HalfOpenRequests == 0 {
+			r.CircuitBreaker.HalfOpenRequests = 1
+		}
+	}
+}
+
+func fetchConfigPath() string {
+	var res string
+
+	flag.StringVar(&res, "config", "", "path to config file")
+	flag.Parse()
+
+	if res == "" {
+		res = os.Getenv("CONFIG_PATH")
+	}
+
+	return res
+}
@@ -0,0 +1,145 @@
+package config
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRouteValidate(t *testing.T) {
+	valid := RouteConfig{
+		Name:       "auth",
+		PathPrefix: "/api/v1/auth",
+		Methods:    []string{"POST"},
+		Upstreams:  []string{"http://user-service:8080"},
+		Auth:       AuthPublic,
+	}
+	valid.setDefaults()
+
+	if err := valid.Validate(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	testCases := []struct {
+		name      string
+		modify    func(r *RouteConfig)
+		expectErr string
+	}{
+		{
+			"Relative path prefix",
+			func(r *RouteConfig) { r.PathPrefix = "api" },
+			"path_prefix must start with '/'",
+		},
+		{
+			"Unknown method",
+			func(r *RouteConfig) { r.Methods = []string{"FETCH"} },
+			"unsupported method",
+		},
+		{
+			"No upstreams",
+			func(r *RouteConfig) { r.Upstreams = nil },
+			"at least one upstream is required",
+		},
+		{
+			"Upstream without scheme",
+			func(r *RouteConfig) { r.Upstreams = []string{"user-service:8080"} },
+			"invalid upstream",
+		},
+		{
+			"Strip and rewrite",
+			func(r *RouteConfig) { r.StripPrefix = true; r.RewritePrefix = "/v1" },
+			"mutually exclusive",
+		},
+		{
+			"Unknown auth mode",
+			func(r *RouteConfig) { r.Auth = "" },
+			"auth must be",
+		},
+		{
+			"Unknown balancing strategy",
+			func(r *RouteConfig) { r.LoadBalancing = "random" },
+			"load_balancing must be",
+		},
+		{
+			"Relative health check path",
+			func(r *RouteConfig) { r.HealthCheck.Path = "healthz" },
+			"health_check.path must start with '/'",
+		},
+		{
+			"Per-try timeout above route timeout",
+			func(r *RouteConfig) { r.Timeout = time.Second; r.Retry.PerTryTimeout = 2 * time.Second },
+			"per_try_timeout",
+		},
+		{
+			"Cache on a route without GET",
+			func(r *RouteConfig) { r.Cache.Enabled = true },
+			"cache requires the route to serve GET",
+		},
+		{
+			"Ejection percent out of range",
+			func(r *RouteConfig) { r.OutlierDetection.MaxEjectionPercent = 150 },
+			"max_ejection_percent",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			route := valid
+			tc.modify(&route)
+
+			err := route.Validate()
+			if err == nil || !strings.Contains(err.Error(), tc.expectErr) {
+				t.Errorf("Expected error '%s', got '%v'", tc.expectErr, err)
+			}
+		})
+	}
+}
+
+func TestConfigValidate(t *testing.T) {
+	route := RouteConfig{
+		Name:       "auth",
+		PathPrefix: "/api/v1/auth",
+		Upstreams:  []string{"http://user-service:8080"},
+		Auth:       AuthRequired,
+	}
+	route.setDefaults()
+	cache := CacheConfig{Backend: CacheBackendMemory, MaxEntries: 100}
+
+	t.Run("Duplicate names", func(t *testing.T) {
+		cfg := Config{Auth: AuthConfig{AccessSecretKey: "secret"}, Cache: cache, Routes: []RouteConfig{route, route}}
+		if err := cfg.Validate(); err == nil || !strings.Contains(err.Error(), "duplicate name") {
+			t.Errorf("Expected duplicate name error, got '%v'", err)
+		}
+	})
+	t.Run("Timeout above write timeout", func(t *testing.T) {
+		slow := route
+		slow.Timeout = time.Minute
+		cfg := Config{HTTP: HTTPConfig{WriteTimeout: 15 * time.Second}, Auth: AuthConfig{AccessSecretKey: "secret"}, Cache: cache, Routes: []RouteConfig{slow}}
+		if err := cfg.Validate(); err == nil || !strings.Contains(err.Error(), "write_timeout") {
+			t.Errorf("Expected write timeout error, got '%v'", err)
+		}
+	})
+	t.Run("Auth without secret", func(t *testing.T) {
+		cfg := Config{Cache: cache, Routes: []RouteConfig{route}}
+		if err := cfg.Validate(); err == nil || !strings.Contains(err.Error(), "access_secret") {
+			t.Errorf("Expected missing secret error, got '%v'", err)
+		}
+	})
+	t.Run("Asymmetric algorithm with a shared secret", func(t *testing.T) {
+		cfg := Config{Auth: AuthConfig{AccessSecretKey: "secret", Algorithms: []string{"RS256"}}, Cache: cache, Routes: []RouteConfig{route}}
+		if err := cfg.Validate(); err == nil || !strings.Contains(err.Error(), "unsupported algorithm") {
+			t.Errorf("Expected algorithm error, got '%v'", err)
+		}
+	})
+	t.Run("Wildcard origin with credentials", func(t *testing.T) {
+		cfg := Config{
+			Auth:   AuthConfig{AccessSecretKey: "secret"},
+			CORS:   CORSConfig{AllowedOrigins: []string{"*"}, AllowCredentials: true},
+			Cache:  cache,
+			Routes: []RouteConfig{route},
+		}
+		if err := cfg.Validate(); err == nil || !strings.Contains(err.Error(), "allow_credentials") {
+			t.Errorf("Expected credentials error, got '%v'", err)
+		}
+	})
+}
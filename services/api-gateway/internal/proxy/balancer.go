@@ -0,0 +1,47 @@
+package proxy
+
+import (
+	"sync/atomic"
+
+	"github.com/SkySock/lode/services/api-gateway/internal/config"
+)
+
+type balancer interface {
+	pick(candidates []*Upstream) *Upstream
+}
+
+func newBalancer(strategy string) balancer {
+	if strategy == config.BalanceLeastConnections {
+		return &leastConnections{}
+	}
+	return &roundRobin{}
+}
+
+type roundRobin struct {
+	next atomic.Uint64
+}
+
+func (b *roundRobin) pick(candidates []*Upstream) *Upstream {
+	n := b.next.Add(1) - 1
+	return candidates[n%uint64(len(candidates))]
+}
+
+// leastConnections picks the upstream with the fewest in-flight requests.
+// The scan starts at a rotating offset so ties are spread evenly.
+type leastConnections struct {
+	next atomic.Uint64
+}
+
+func (b *leastConnections) pick(candidates []*Upstream) *Upstream {
+	offset := int((b.next.Add(1) - 1) % uint64(len(candidates)))
+
+	var best *Upstream
+	for i := range candidates {
+		u := candidates[(offset+i)%len(candidates)]
+		if best == nil || u.Active() < best.Active() {
+			best = u
+		}
+	}
+
+	return best
+}
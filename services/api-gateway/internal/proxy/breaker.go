@@ -0,0 +1,150 @@
+package proxy
+
+import (
+	"sync"
+	"time"
+)
+
+type BreakerState int
+
+const (
+	StateClosed BreakerState = iota
+	StateOpen
+	StateHalfOpen
+)
+
+func (s BreakerState) String() string {
+	switch s {
+	case StateClosed:
+		return "closed"
+	case StateOpen:
+		return "open"
+	case StateHalfOpen:
+		return "half-open"
+	default:
+		return "unknown"
+	}
+}
+
+// Breaker is a consecutive-failure circuit breaker. After failureThreshold
+// failures in a row it opens and rejects requests for openTimeout, then lets
+// up to halfOpenRequests probes through; a successful probe closes it again.
+//
+// A nil *Breaker allows everything, which is how a disabled breaker is represented.
+type Breaker struct {
+	mu sync.Mutex
+
+	failureThreshold int
+	openTimeout      time.Duration
+	halfOpenRequests int
+
+	state            BreakerState
+	failures         int
+	openedAt         time.Time
+	halfOpenInFlight int
+
+	now func() time.Time
+}
+
+func NewBreaker(failureThreshold int, openTimeout time.Duration, halfOpenRequests int) *Breaker {
+	return &Breaker{
+		failureThreshold: failureThreshold,
+		openTimeout:      openTimeout,
+		halfOpenRequests: halfOpenRequests,
+		now:              time.Now,
+	}
+}
+
+// Allow reports whether a request may proceed. Every allowed request must be
+// followed by exactly one call to Success, Failure or Release.
+func (b *Breaker) Allow() bool {
+	if b == nil {
+		return true
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state == StateOpen {
+		if b.now().Sub(b.openedAt) < b.openTimeout {
+			return false
+		}
+		b.state = StateHalfOpen
+		b.halfOpenInFlight = 0
+	}
+
+	if b.state == StateHalfOpen {
+		if b.halfOpenInFlight >= b.halfOpenRequests {
+			return false
+		}
+		b.halfOpenInFlight++
+	}
+
+	return true
+}
+
+func (b *Breaker) Success() {
+	if b == nil {
+		return
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.failures = 0
+	if b.state == StateHalfOpen {
+		b.state = StateClosed
+		b.halfOpenInFlight = 0
+	}
+}
+
+func (b *Breaker) Failure() {
+	if b == nil {
+		return
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case StateHalfOpen:
+		b.trip()
+	case StateClosed:
+		b.failures++
+		if b.failures >= b.failureThreshold {
+			b.trip()
+		}
+	}
+}
+
+// Release gives back an allowed request slot without recording an outcome.
+func (b *Breaker) Release() {
+	if b == nil {
+		return
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state == StateHalfOpen && b.halfOpenInFlight > 0 {
+		b.halfOpenInFlight--
+	}
+}
+
+func (b *Breaker) State() BreakerState {
+	if b == nil {
+		return StateClosed
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.state
+}
+
+func (b *Breaker) trip() {
+	b.state = StateOpen
+	b.openedAt = b.now()
+	b.failures = 0
+	b.halfOpenInFlight = 0
+}
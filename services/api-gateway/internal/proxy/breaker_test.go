@@ -0,0 +1,82 @@
+package proxy
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBreaker(t *testing.T) {
+	now := time.Now()
+	newTestBreaker := func() *Breaker {
+		b := NewBreaker(3, time.Minute, 1)
+		b.now = func() time.Time { return now }
+		return b
+	}
+
+	t.Run("Opens after threshold", func(t *testing.T) {
+		b := newTestBreaker()
+		for range 3 {
+			if !b.Allow() {
+				t.Fatal("Closed breaker should allow requests")
+			}
+			b.Failure()
+		}
+
+		if b.State() != StateOpen {
+			t.Fatalf("Wrong state: got %s, want %s", b.State(), StateOpen)
+		}
+		if b.Allow() {
+			t.Error("Open breaker should reject requests")
+		}
+	})
+	t.Run("Success resets failures", func(t *testing.T) {
+		b := newTestBreaker()
+		b.Failure()
+		b.Failure()
+		b.Success()
+		b.Failure()
+
+		if b.State() != StateClosed {
+			t.Errorf("Wrong state: got %s, want %s", b.State(), StateClosed)
+		}
+	})
+	t.Run("Half-open probe closes", func(t *testing.T) {
+		b := newTestBreaker()
+		for range 3 {
+			b.Failure()
+		}
+		now = now.Add(2 * time.Minute)
+
+		if !b.Allow() {
+			t.Fatal("Breaker should let a probe through after open timeout")
+		}
+		if b.Allow() {
+			t.Error("Only one half-open probe should be allowed")
+		}
+
+		b.Success()
+		if b.State() != StateClosed {
+			t.Errorf("Wrong state: got %s, want %s", b.State(), StateClosed)
+		}
+	})
+	t.Run("Half-open probe failure reopens", func(t *testing.T) {
+		b := newTestBreaker()
+		for range 3 {
+			b.Failure()
+		}
+		now = now.Add(2 * time.Minute)
+
+		b.Allow()
+		b.Failure()
+		if b.State() != StateOpen {
+			t.Errorf("Wrong state: got %s, want %s", b.State(), StateOpen)
+		}
+	})
+	t.Run("Nil breaker", func(t *testing.T) {
+		var b *Breaker
+		if !b.Allow() {
+			t.Error("Disabled breaker should allow requests")
+		}
+		b.Failure()
+	})
+}
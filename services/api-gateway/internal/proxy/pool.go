@@ -0,0 +1,237 @@
+package proxy
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/SkySock/lode/services/api-gateway/internal/config"
+)
+
+// Pool is the set of upstreams serving a route. Upstreams leave rotation when
+// active health checks mark them unhealthy or when passive outlier detection
+// ejects them after consecutive failures.
+type Pool struct {
+	log       *slog.Logger
+	upstreams []*Upstream
+	balancer  balancer
+
+	healthCheck config.HealthCheckConfig
+	outlier     config.OutlierDetectionConfig
+	client      *http.Client
+
+	// ejectMu serializes ejections, so that concurrent failures can not
+	// together eject more upstreams than max_ejection_percent allows.
+	ejectMu sync.Mutex
+
+	stop     chan struct{}
+	stopOnce sync.Once
+	wg       sync.WaitGroup
+
+	now func() time.Time
+}
+
+func NewPool(log *slog.Logger, route config.RouteConfig) (*Pool, error) {
+	upstreams := make([]*Upstream, 0, len(route.Upstreams))
+	for _, raw := range route.Upstreams {
+		u, err := url.Parse(raw)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse upstream %q: %w", raw, err)
+		}
+		upstreams = append(upstreams, newUpstream(u))
+	}
+
+	return &Pool{
+		log:         log.With(slog.String("route", route.Name)),
+		upstreams:   upstreams,
+		balancer:    newBalancer(route.LoadBalancing),
+		healthCheck: route.HealthCheck,
+		outlier:     route.OutlierDetection,
+		client:      &http.Client{Timeout: route.HealthCheck.Timeout},
+		stop:        make(chan struct{}),
+		now:         time.Now,
+	}, nil
+}
+
+func (p *Pool) Upstreams() []*Upstream {
+	return p.upstreams
+}
+
+// Pick selects an upstream among the available ones, preferring upstreams not
+// listed in exclude, e.g. ones that a retried request already failed on.
+func (p *Pool) Pick(exclude ...*Upstream) (*Upstream, error) {
+	now := p.now()
+
+	candidates := make([]*Upstream, 0, len(p.upstreams))
+	for _, u := range p.upstreams {
+		if u.available(now) {
+			candidates = append(candidates, u)
+		}
+	}
+
+	if len(candidates) == 0 {
+		return nil, ErrNoHealthyUpstream
+	}
+
+	if fresh := slices.DeleteFunc(slices.Clone(candidates), func(u *Upstream) bool {
+		return slices.Contains(exclude, u)
+	}); len(fresh) > 0 {
+		candidates = fresh
+	}
+
+	return p.balancer.pick(candidates), nil
+}
+
+func (p *Pool) ReportSuccess(u *Upstream) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	u.consecutiveFailures = 0
+}
+
+func (p *Pool) ReportFailure(u *Upstream) {
+	if p.outlier.ConsecutiveFailures == 0 {
+		return
+	}
+
+	now := p.now()
+
+	u.mu.Lock()
+	u.consecutiveFailures++
+	shouldEject := u.consecutiveFailures >= p.outlier.ConsecutiveFailures && !now.Before(u.ejectedUntil)
+	u.mu.Unlock()
+
+	if !shouldEject {
+		return
+	}
+
+	p.ejectMu.Lock()
+	defer p.ejectMu.Unlock()
+
+	// Another failure may have ejected the upstream since it was checked.
+	if u.ejected(now) || !p.canEject(now) {
+		return
+	}
+
+	u.mu.Lock()
+	u.ejectedUntil = now.Add(p.outlier.EjectionTime)
+	u.consecutiveFailures = 0
+	u.mu.Unlock()
+
+	p.log.Warn("upstream ejected",
+		slog.String("upstream", u.URL.String()),
+		slog.Duration("ejection_time", p.outlier.EjectionTime),
+	)
+}
+
+// canEject enforces max_ejection_percent. At least one upstream may always be
+// ejected so that a single bad host in a small pool is still taken out. It
+// must be called with ejectMu held.
+func (p *Pool) canEject(now time.Time) bool {
+	ejected := 0
+	for _, u := range p.upstreams {
+		if u.ejected(now) {
+			ejected++
+		}
+	}
+
+	limit := max(1, len(p.upstreams)*p.outlier.MaxEjectionPercent/100)
+
+	return ejected < limit
+}
+
+// Start launches active health checking if it is configured.
+func (p *Pool) Start() {
+	if p.healthCheck.Path == "" {
+		return
+	}
+
+	p.wg.Add(1)
+	go func() {
+		defer p.wg.Done()
+
+		ticker := time.NewTicker(p.healthCheck.Interval)
+		defer ticker.Stop()
+
+		p.probeAll()
+		for {
+			select {
+			case <-p.stop:
+				return
+			case <-ticker.C:
+				p.probeAll()
+			}
+		}
+	}()
+}
+
+func (p *Pool) Close() {
+	p.stopOnce.Do(func() {
+		close(p.stop)
+	})
+	p.wg.Wait()
+}
+
+func (p *Pool) probeAll() {
+	var wg sync.WaitGroup
+	for _, u := range p.upstreams {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			p.recordProbe(u, p.probe(u))
+		}()
+	}
+	wg.Wait()
+}
+
+func (p *Pool) probe(u *Upstream) error {
+	ctx, cancel := context.WithTimeout(context.Background(), p.healthCheck.Timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.URL.JoinPath(p.healthCheck.Path).String(), nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
+func (p *Pool) recordProbe(u *Upstream, err error) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	if err == nil {
+		u.probeFailures = 0
+		u.probeSuccesses++
+		if !u.Healthy() && u.probeSuccesses >= p.healthCheck.HealthyThreshold {
+			u.healthy.Store(true)
+			p.log.Info("upstream is healthy", slog.String("upstream", u.URL.String()))
+		}
+		return
+	}
+
+	u.probeSuccesses = 0
+	u.probeFailures++
+	if u.Healthy() && u.probeFailures >= p.healthCheck.UnhealthyThreshold {
+		u.healthy.Store(false)
+		p.log.Warn("upstream is unhealthy",
+			slog.String("upstream", u.URL.String()),
+			slog.String("error", err.Error()),
+		)
+	}
+}
@@ -0,0 +1,52 @@
+package proxy
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/SkySock/lode/services/api-gateway/internal/config"
+)
+
+func TestPoolMaxEjectionPercent(t *testing.T) {
+	route := newTestRoute()
+	for i := range 4 {
+		route.Upstreams = append(route.Upstreams, fmt.Sprintf("http://upstream-%d:8080", i))
+	}
+	route.OutlierDetection = config.OutlierDetectionConfig{
+		ConsecutiveFailures: 1,
+		EjectionTime:        time.Minute,
+		MaxEjectionPercent:  50,
+	}
+
+	for range 100 {
+		p, err := NewPool(slog.New(slog.NewTextHandler(io.Discard, nil)), route)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		// All upstreams fail at once.
+		var wg sync.WaitGroup
+		for _, u := range p.Upstreams() {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				p.ReportFailure(u)
+			}()
+		}
+		wg.Wait()
+
+		ejected := 0
+		for _, u := range p.Upstreams() {
+			if u.ejected(p.now()) {
+				ejected++
+			}
+		}
+		if ejected != 2 {
+			t.Fatalf("Wrong number of ejected upstreams: got %d, want 2", ejected)
+		}
+	}
+}
@@ -0,0 +1,379 @@
+package proxy
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/http/httputil"
+	"strings"
+	"time"
+
+	"github.com/SkySock/lode/libs/utils/http/middleware"
+	"github.com/SkySock/lode/libs/utils/http/response"
+	"github.com/SkySock/lode/services/api-gateway/internal/config"
+)
+
+var (
+	ErrNoHealthyUpstream = errors.New("no healthy upstream")
+	ErrCircuitOpen       = errors.New("circuit breaker is open")
+)
+
+// Proxy forwards requests of a single route to its upstream pool. Upstream
+// selection happens in RoundTrip, so every outgoing attempt, including retries
+// and hedges, is load balanced and its outcome feeds outlier detection and the
+// circuit breaker.
+type Proxy struct {
+	log       *slog.Logger
+	route     config.RouteConfig
+	pool      *Pool
+	breaker   *Breaker
+	budget    *retryBudget
+	transport http.RoundTripper
+	rp        *httputil.ReverseProxy
+}
+
+func New(log *slog.Logger, route config.RouteConfig) (*Proxy, error) {
+	pool, err := NewPool(log, route)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &Proxy{
+		log:       log.With(slog.String("route", route.Name)),
+		route:     route,
+		pool:      pool,
+		transport: http.DefaultTransport,
+	}
+
+	if route.Retry.Attempts > 0 || route.Hedge.Delay > 0 {
+		p.budget = newRetryBudget(route.Retry.BudgetPercent, route.Retry.MinRetries)
+	}
+
+	if cb := route.CircuitBreaker; cb.FailureThreshold > 0 {
+		p.breaker = NewBreaker(cb.FailureThreshold, cb.OpenTimeout, cb.HalfOpenRequests)
+	}
+
+	p.rp = &httputil.ReverseProxy{
+		Rewrite: func(pr *httputil.ProxyRequest) {
+			pr.Out.URL.Path = rewritePath(route, pr.In.URL.Path)
+			pr.Out.URL.RawPath = ""
+			pr.SetXForwarded()
+		},
+		Transport:    p,
+		ErrorHandler: p.handleError,
+	}
+
+	pool.Start()
+
+	return p, nil
+}
+
+func (p *Proxy) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	p.rp.ServeHTTP(w, r)
+}
+
+// Close stops background health checking.
+func (p *Proxy) Close() {
+	p.pool.Close()
+}
+
+func (p *Proxy) RoundTrip(req *http.Request) (*http.Response, error) {
+	if p.budget != nil {
+		p.budget.deposit()
+	}
+
+	if p.route.Hedge.Delay > 0 && isHedgeable(req) {
+		return p.hedge(req)
+	}
+
+	retryable := p.route.Retry.Attempts > 0
+	if retryable {
+		ok, err := bufferBody(req, p.route.Retry.MaxBodyBytes)
+		if err != nil {
+			return nil, err
+		}
+		retryable = ok
+	}
+
+	var tried []*Upstream
+	backoff := p.route.Retry.Backoff
+
+	for attempt := 0; ; attempt++ {
+		u, err := p.pool.Pick(tried...)
+		if err != nil {
+			return nil, err
+		}
+		tried = append(tried, u)
+
+		ctx, cancel := context.WithCancel(req.Context())
+		resp, err := p.send(ctx, cancel, req, u)
+
+		if !retryable || attempt >= p.route.Retry.Attempts || !shouldRetry(req, resp, err) || !p.budget.withdraw() {
+			return resp, err
+		}
+		if resp != nil {
+			drain(resp)
+		}
+
+		p.log.Debug("retrying upstream request",
+			slog.String("method", req.Method),
+			slog.String("upstream", u.URL.String()),
+			slog.Int("attempt", attempt+1),
+		)
+
+		select {
+		case <-time.After(backoff):
+			backoff *= 2
+		case <-req.Context().Done():
+			return nil, req.Context().Err()
+		}
+	}
+}
+
+type hedgeResult struct {
+	resp *http.Response
+	err  error
+}
+
+// hedge sends the request to one upstream and, if it has not answered within
+// the hedge delay (or failed quickly), to a second one. The first good answer
+// wins and the other attempt is cancelled.
+func (p *Proxy) hedge(req *http.Request) (*http.Response, error) {
+	results := make(chan hedgeResult, 2)
+	var cancels []context.CancelFunc
+
+	launch := func(u *Upstream) {
+		ctx, cancel := context.WithCancel(req.Context())
+		cancels = append(cancels, cancel)
+
+		go func() {
+			resp, err := p.send(ctx, cancel, req, u)
+			results <- hedgeResult{resp, err}
+		}()
+	}
+
+	first, err := p.pool.Pick()
+	if err != nil {
+		return nil, err
+	}
+	launch(first)
+	pending := 1
+
+	timer := time.NewTimer(p.route.Hedge.Delay)
+	defer timer.Stop()
+
+	tryHedge := func() {
+		if len(cancels) > 1 || !p.budget.withdraw() {
+			return
+		}
+		if second, err := p.pool.Pick(first); err == nil {
+			launch(second)
+			pending++
+		}
+	}
+
+	var last hedgeResult
+	for pending > 0 {
+		select {
+		case <-timer.C:
+			tryHedge()
+			continue
+		case last = <-results:
+			pending--
+		}
+
+		if !shouldRetry(req, last.resp, last.err) {
+			break
+		}
+		if pending == 0 {
+			tryHedge()
+		}
+		if pending > 0 && last.resp != nil {
+			drain(last.resp)
+		}
+	}
+
+	// Cancel and clean up whatever attempt lost the race.
+	if pending > 0 {
+		for _, cancel := range cancels {
+			cancel()
+		}
+		go func() {
+			for range pending {
+				if r := <-results; r.resp != nil {
+					_ = r.resp.Body.Close()
+				}
+			}
+		}()
+	}
+
+	return last.resp, last.err
+}
+
+// send performs a single attempt against u. cancel is released when the
+// response body is closed or the attempt fails.
+func (p *Proxy) send(ctx context.Context, cancel context.CancelFunc, req *http.Request, u *Upstream) (*http.Response, error) {
+	if !p.breaker.Allow() {
+		cancel()
+		return nil, ErrCircuitOpen
+	}
+
+	if t := p.route.Retry.PerTryTimeout; t > 0 {
+		ctx, cancel = withTimeout(ctx, cancel, t)
+	}
+
+	out := req.Clone(ctx)
+	if req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			cancel()
+			p.breaker.Release()
+			return nil, err
+		}
+		out.Body = body
+	}
+	out.URL.Scheme = u.URL.Scheme
+	out.URL.Host = u.URL.Host
+	out.URL.Path = joinPath(u.URL.Path, req.URL.Path)
+	out.Host = ""
+
+	if deadline, ok := ctx.Deadline(); ok {
+		out.Header.Set(middleware.RequestTimeoutHeader, middleware.FormatRequestTimeout(deadline))
+	} else {
+		out.Header.Del(middleware.RequestTimeoutHeader)
+	}
+
+	u.active.Add(1)
+
+	resp, err := p.transport.RoundTrip(out)
+
+	p.report(ctx, u, resp, err)
+
+	if err != nil {
+		u.active.Add(-1)
+		cancel()
+		return nil, err
+	}
+
+	resp.Body = &trackedBody{ReadCloser: resp.Body, upstream: u, cancel: cancel}
+
+	return resp, nil
+}
+
+func (p *Proxy) report(ctx context.Context, u *Upstream, resp *http.Response, err error) {
+	// A client that went away or a cancelled hedge says nothing about the upstream.
+	if errors.Is(ctx.Err(), context.Canceled) {
+		p.breaker.Release()
+		return
+	}
+
+	if err != nil || isUpstreamFailure(resp.StatusCode) {
+		p.pool.ReportFailure(u)
+		p.breaker.Failure()
+		return
+	}
+
+	p.pool.ReportSuccess(u)
+	p.breaker.Success()
+}
+
+func (p *Proxy) handleError(w http.ResponseWriter, r *http.Request, err error) {
+	status, detail := http.StatusBadGateway, "upstream request failed"
+
+	switch {
+	case errors.Is(err, ErrNoHealthyUpstream):
+		status, detail = http.StatusServiceUnavailable, "no healthy upstream available"
+	case errors.Is(err, ErrCircuitOpen):
+		status, detail = http.StatusServiceUnavailable, "upstream circuit breaker is open"
+	case isTimeout(err):
+		status, detail = http.StatusGatewayTimeout, "upstream request timed out"
+	}
+
+	p.log.Warn("proxy error",
+		slog.String("method", r.Method),
+		slog.String("path", r.URL.Path),
+		slog.Int("status_code", status),
+		slog.String("error", err.Error()),
+	)
+
+	if err := response.WriteProblem(w, status, detail); err != nil {
+		p.log.Error("failed to write problem response", slog.String("error", err.Error()))
+	}
+}
+
+func isUpstreamFailure(statusCode int) bool {
+	switch statusCode {
+	case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	default:
+		return false
+	}
+}
+
+func isTimeout(err error) bool {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+
+	var netErr net.Error
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
+
+// trackedBody keeps the upstream's in-flight counter raised until the
+// response has been fully streamed to the client.
+type trackedBody struct {
+	io.ReadCloser
+	upstream *Upstream
+	cancel   context.CancelFunc
+	closed   bool
+}
+
+func (b *trackedBody) Close() error {
+	err := b.ReadCloser.Close()
+	if !b.closed {
+		b.closed = true
+		b.upstream.active.Add(-1)
+		b.cancel()
+	}
+	return err
+}
+
+func withTimeout(parent context.Context, parentCancel context.CancelFunc, timeout time.Duration) (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithTimeout(parent, timeout)
+	return ctx, func() {
+		cancel()
+		parentCancel()
+	}
+}
+
+func isHedgeable(req *http.Request) bool {
+	return (req.Method == http.MethodGet || req.Method == http.MethodHead) &&
+		(req.Body == nil || req.Body == http.NoBody)
+}
+
+func rewritePath(route config.RouteConfig, path string) string {
+	switch {
+	case route.StripPrefix:
+		path = strings.TrimPrefix(path, route.PathPrefix)
+	case route.RewritePrefix != "":
+		path = route.RewritePrefix + strings.TrimPrefix(path, route.PathPrefix)
+	default:
+		return path
+	}
+
+	if !strings.HasPrefix(path, "/") {
+		path = "/" + path
+	}
+
+	return path
+}
+
+func joinPath(base, path string) string {
+	if base == "" || base == "/" {
+		return path
+	}
+	return strings.TrimSuffix(base, "/") + "/" + strings.TrimPrefix(path, "/")
+}
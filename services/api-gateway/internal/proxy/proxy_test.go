@@ -0,0 +1,298 @@
+package proxy
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/SkySock/lode/libs/utils/http/middleware"
+	"github.com/SkySock/lode/libs/utils/http/response"
+	"github.com/SkySock/lode/services/api-gateway/internal/config"
+)
+
+func newTestRoute(upstreams ...string) config.RouteConfig {
+	return config.RouteConfig{
+		Name:          "test",
+		PathPrefix:    "/api",
+		Upstreams:     upstreams,
+		Auth:          config.AuthPublic,
+		LoadBalancing: config.BalanceRoundRobin,
+	}
+}
+
+func newTestProxy(t *testing.T, route config.RouteConfig) *Proxy {
+	t.Helper()
+
+	p, err := New(slog.New(slog.NewTextHandler(io.Discard, nil)), route)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	t.Cleanup(p.Close)
+
+	return p
+}
+
+func TestProxyRoundRobin(t *testing.T) {
+	hits := map[string]int{}
+	newUpstream := func(name string) *httptest.Server {
+		return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			hits[name]++
+		}))
+	}
+	a, b := newUpstream("a"), newUpstream("b")
+	defer a.Close()
+	defer b.Close()
+
+	p := newTestProxy(t, newTestRoute(a.URL, b.URL))
+
+	for range 4 {
+		rec := httptest.NewRecorder()
+		p.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/x", nil))
+	}
+
+	if hits["a"] != 2 || hits["b"] != 2 {
+		t.Errorf("Requests should be spread evenly, got %v", hits)
+	}
+}
+
+func TestProxyOutlierEjection(t *testing.T) {
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer failing.Close()
+
+	route := newTestRoute(failing.URL)
+	route.OutlierDetection = config.OutlierDetectionConfig{
+		ConsecutiveFailures: 2,
+		EjectionTime:        time.Minute,
+		MaxEjectionPercent:  100,
+	}
+	p := newTestProxy(t, route)
+
+	for range 2 {
+		p.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/api/x", nil))
+	}
+
+	rec := httptest.NewRecorder()
+	p.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/x", nil))
+
+	assertProblem(t, rec, http.StatusServiceUnavailable)
+}
+
+func TestProxyErrors(t *testing.T) {
+	t.Run("Unreachable upstream", func(t *testing.T) {
+		closed := httptest.NewServer(http.NotFoundHandler())
+		closed.Close()
+
+		p := newTestProxy(t, newTestRoute(closed.URL))
+
+		rec := httptest.NewRecorder()
+		p.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/x", nil))
+
+		assertProblem(t, rec, http.StatusBadGateway)
+	})
+	t.Run("Circuit open", func(t *testing.T) {
+		closed := httptest.NewServer(http.NotFoundHandler())
+		closed.Close()
+
+		route := newTestRoute(closed.URL)
+		route.CircuitBreaker = config.CircuitBreakerConfig{FailureThreshold: 1, OpenTimeout: time.Minute, HalfOpenRequests: 1}
+		p := newTestProxy(t, route)
+
+		p.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/api/x", nil))
+
+		rec := httptest.NewRecorder()
+		p.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/x", nil))
+
+		assertProblem(t, rec, http.StatusServiceUnavailable)
+	})
+	t.Run("Upstream timeout", func(t *testing.T) {
+		slow := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			<-r.Context().Done()
+		}))
+		defer slow.Close()
+
+		p := newTestProxy(t, newTestRoute(slow.URL))
+
+		req := httptest.NewRequest(http.MethodGet, "/api/x", nil)
+		ctx, cancel := context.WithTimeout(req.Context(), 20*time.Millisecond)
+		defer cancel()
+
+		rec := httptest.NewRecorder()
+		p.ServeHTTP(rec, req.WithContext(ctx))
+
+		assertProblem(t, rec, http.StatusGatewayTimeout)
+	})
+}
+
+func TestRewritePath(t *testing.T) {
+	testCases := []struct {
+		name  string
+		route config.RouteConfig
+		path  string
+		want  string
+	}{
+		{"Passthrough", config.RouteConfig{PathPrefix: "/api/v1/auth"}, "/api/v1/auth/sign-in", "/api/v1/auth/sign-in"},
+		{"Strip", config.RouteConfig{PathPrefix: "/api/v1/auth", StripPrefix: true}, "/api/v1/auth/sign-in", "/sign-in"},
+		{"Strip to root", config.RouteConfig{PathPrefix: "/api/v1/auth", StripPrefix: true}, "/api/v1/auth", "/"},
+		{"Rewrite", config.RouteConfig{PathPrefix: "/api/v1/auth", RewritePrefix: "/internal/auth"}, "/api/v1/auth/sign-in", "/internal/auth/sign-in"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := rewritePath(tc.route, tc.path); got != tc.want {
+				t.Errorf("Wrong path: got %s, want %s", got, tc.want)
+			}
+		})
+	}
+}
+
+func assertProblem(t *testing.T, rec *httptest.ResponseRecorder, status int) {
+	t.Helper()
+
+	if rec.Code != status {
+		t.Fatalf("Wrong status code: got %d, want %d", rec.Code, status)
+	}
+	if ct := rec.Header().Get("Content-Type"); ct != response.ProblemContentType {
+		t.Errorf("Wrong content type: %s", ct)
+	}
+
+	var problem response.Problem
+	if err := json.NewDecoder(rec.Body).Decode(&problem); err != nil {
+		t.Fatalf("Decode failed: %v", err)
+	}
+	if problem.Status != status {
+		t.Errorf("Wrong problem status: got %d, want %d", problem.Status, status)
+	}
+}
+
+func TestProxyRetries(t *testing.T) {
+	newRetryRoute := func(upstreams ...string) config.RouteConfig {
+		route := newTestRoute(upstreams...)
+		route.Retry = config.RetryConfig{
+			Attempts:      1,
+			Backoff:       time.Millisecond,
+			BudgetPercent: 20,
+			MinRetries:    10,
+			MaxBodyBytes:  1024,
+		}
+		return route
+	}
+
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer failing.Close()
+
+	var body string
+	healthy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := io.ReadAll(r.Body)
+		body = string(b)
+	}))
+	defer healthy.Close()
+
+	closed := httptest.NewServer(http.NotFoundHandler())
+	closed.Close()
+
+	t.Run("Idempotent request retried on 503", func(t *testing.T) {
+		p := newTestProxy(t, newRetryRoute(failing.URL, healthy.URL))
+
+		rec := httptest.NewRecorder()
+		p.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/x", nil))
+
+		if rec.Code != http.StatusOK {
+			t.Errorf("Wrong status code: got %d, want %d", rec.Code, http.StatusOK)
+		}
+	})
+	t.Run("Non-idempotent request not retried on 503", func(t *testing.T) {
+		p := newTestProxy(t, newRetryRoute(failing.URL, healthy.URL))
+
+		rec := httptest.NewRecorder()
+		p.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/x", strings.NewReader("payload")))
+
+		if rec.Code != http.StatusServiceUnavailable {
+			t.Errorf("Wrong status code: got %d, want %d", rec.Code, http.StatusServiceUnavailable)
+		}
+	})
+	t.Run("Non-idempotent request retried on connect failure", func(t *testing.T) {
+		p := newTestProxy(t, newRetryRoute(closed.URL, healthy.URL))
+
+		rec := httptest.NewRecorder()
+		p.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/x", strings.NewReader("payload")))
+
+		if rec.Code != http.StatusOK {
+			t.Errorf("Wrong status code: got %d, want %d", rec.Code, http.StatusOK)
+		}
+		if body != "payload" {
+			t.Errorf("Body should be replayed, got %q", body)
+		}
+	})
+	t.Run("Exhausted budget", func(t *testing.T) {
+		route := newRetryRoute(failing.URL, healthy.URL)
+		route.Retry.MinRetries = 0
+		route.Retry.BudgetPercent = 1
+		p := newTestProxy(t, route)
+
+		rec := httptest.NewRecorder()
+		p.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/x", nil))
+
+		if rec.Code != http.StatusServiceUnavailable {
+			t.Errorf("Retry should be denied by budget: got %d", rec.Code)
+		}
+	})
+}
+
+func TestProxyHedge(t *testing.T) {
+	slow := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(time.Second):
+			w.Write([]byte("slow"))
+		}
+	}))
+	defer slow.Close()
+
+	fast := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("fast"))
+	}))
+	defer fast.Close()
+
+	route := newTestRoute(slow.URL, fast.URL)
+	route.Hedge = config.HedgeConfig{Delay: 10 * time.Millisecond}
+	route.Retry = config.RetryConfig{BudgetPercent: 20, MinRetries: 10}
+	p := newTestProxy(t, route)
+
+	rec := httptest.NewRecorder()
+	p.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/x", nil))
+
+	if rec.Body.String() != "fast" {
+		t.Errorf("Hedged request should be answered by the fast upstream, got %q", rec.Body.String())
+	}
+}
+
+func TestProxyDeadlinePropagation(t *testing.T) {
+	var header string
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header = r.Header.Get(middleware.RequestTimeoutHeader)
+	}))
+	defer upstream.Close()
+
+	p := newTestProxy(t, newTestRoute(upstream.URL))
+
+	req := httptest.NewRequest(http.MethodGet, "/api/x", nil)
+	ctx, cancel := context.WithTimeout(req.Context(), 5*time.Second)
+	defer cancel()
+
+	p.ServeHTTP(httptest.NewRecorder(), req.WithContext(ctx))
+
+	timeout, ok := middleware.ParseRequestTimeout(header)
+	if !ok || timeout > 5*time.Second || timeout < 4*time.Second {
+		t.Errorf("Wrong propagated timeout: %q", header)
+	}
+}
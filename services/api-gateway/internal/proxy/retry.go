@@ -0,0 +1,124 @@
+package proxy
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"sync"
+)
+
+const idempotencyKeyHeader = "Idempotency-Key"
+
+// retryBudget caps retries to a share of the regular traffic. Every request
+// deposits ratio tokens and every retry withdraws one, so a failing upstream
+// cannot be hammered with retry storms. minRetries tokens are available up
+// front so that low-traffic routes can still retry.
+type retryBudget struct {
+	mu      sync.Mutex
+	ratio   float64
+	balance float64
+	limit   float64
+}
+
+func newRetryBudget(percent, minRetries int) *retryBudget {
+	ratio := float64(percent) / 100
+
+	return &retryBudget{
+		ratio:   ratio,
+		balance: float64(minRetries),
+		// Savings are capped at roughly what a hundred requests would earn.
+		limit: float64(minRetries) + ratio*100,
+	}
+}
+
+func (b *retryBudget) deposit() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.balance = min(b.balance+b.ratio, b.limit)
+}
+
+func (b *retryBudget) withdraw() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.balance < 1 {
+		return false
+	}
+	b.balance--
+
+	return true
+}
+
+func isIdempotent(req *http.Request) bool {
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete, http.MethodTrace:
+		return true
+	default:
+		return req.Header.Get(idempotencyKeyHeader) != ""
+	}
+}
+
+// isConnectFailure reports whether the request never reached the upstream,
+// which makes it safe to retry regardless of the method.
+func isConnectFailure(err error) bool {
+	var opErr *net.OpError
+	return errors.As(err, &opErr) && opErr.Op == "dial"
+}
+
+func shouldRetry(req *http.Request, resp *http.Response, err error) bool {
+	if errors.Is(req.Context().Err(), context.Canceled) || errors.Is(req.Context().Err(), context.DeadlineExceeded) {
+		return false
+	}
+
+	if err != nil {
+		if errors.Is(err, ErrCircuitOpen) || errors.Is(err, ErrNoHealthyUpstream) {
+			return false
+		}
+		return isConnectFailure(err) || isIdempotent(req)
+	}
+
+	return isIdempotent(req) && isUpstreamFailure(resp.StatusCode)
+}
+
+// bufferBody makes the request body replayable through GetBody when it fits
+// in limit bytes. It reports false when the body is too large to be resent.
+func bufferBody(req *http.Request, limit int64) (bool, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return true, nil
+	}
+	if req.ContentLength > limit {
+		return false, nil
+	}
+
+	buf, err := io.ReadAll(io.LimitReader(req.Body, limit+1))
+	if err != nil {
+		return false, err
+	}
+
+	if int64(len(buf)) > limit {
+		req.Body = readCloser{io.MultiReader(bytes.NewReader(buf), req.Body), req.Body}
+		return false, nil
+	}
+
+	_ = req.Body.Close()
+	req.GetBody = func() (io.ReadCloser, error) {
+		return io.NopCloser(bytes.NewReader(buf)), nil
+	}
+	req.Body, _ = req.GetBody()
+
+	return true, nil
+}
+
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
+
+func drain(resp *http.Response) {
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 4<<10))
+	_ = resp.Body.Close()
+}
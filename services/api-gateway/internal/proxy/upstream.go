@@ -0,0 +1,49 @@
+package proxy
+
+import (
+	"net/url"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+type Upstream struct {
+	URL *url.URL
+
+	active  atomic.Int64
+	healthy atomic.Bool
+
+	mu                  sync.Mutex
+	consecutiveFailures int
+	ejectedUntil        time.Time
+
+	// Consecutive active health check results, used for thresholds.
+	probeSuccesses int
+	probeFailures  int
+}
+
+func newUpstream(u *url.URL) *Upstream {
+	upstream := &Upstream{URL: u}
+	upstream.healthy.Store(true)
+	return upstream
+}
+
+// Active returns the number of in-flight requests to the upstream.
+func (u *Upstream) Active() int64 {
+	return u.active.Load()
+}
+
+func (u *Upstream) Healthy() bool {
+	return u.healthy.Load()
+}
+
+func (u *Upstream) ejected(now time.Time) bool {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	return now.Before(u.ejectedUntil)
+}
+
+func (u *Upstream) available(now time.Time) bool {
+	return u.Healthy() && !u.ejected(now)
+}
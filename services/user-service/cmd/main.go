@@ -6,6 +6,10 @@ import (
 	"github.com/SkySock/lode/services/user-service/internal/db"
 )
 
+// @securityDefinitions.apikey BearerAuth
+// @in                         header
+// @name                       Authorization
+// @description                Access токен в формате "Bearer <token>"
 func main() {
 	cfg := config.MustLoad()
 
@@ -15,9 +15,14 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/auth/sign-in": {
-            "post": {
-                "description": "Вход в аккаунт пользователя",
+        "/account": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Помечает аккаунт удаленным и завершает все его сессии. До окончательного удаления аккаунт можно восстановить через /auth/restore-account",
                 "consumes": [
                     "application/json"
                 ],
@@ -25,131 +30,3609 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Auth"
+                    "Account"
                 ],
-                "summary": "Вход в аккаунт",
+                "summary": "Удаление аккаунта",
                 "parameters": [
                     {
-                        "description": "Данные регистрации",
+                        "description": "Подтверждение паролем",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/github_com_SkySock_lode_libs_shared-dto_user_http_v1.SignInRequest"
+                            "$ref": "#/definitions/github_com_SkySock_lode_libs_shared-dto_user_http_v1.DeleteAccountRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "202": {
+                        "description": "Accepted",
                         "schema": {
-                            "$ref": "#/definitions/github_com_SkySock_lode_libs_shared-dto_user_http_v1.SignInResponse"
+                            "$ref": "#/definitions/github_com_SkySock_lode_libs_shared-dto_user_http_v1.AccountDeletionResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_SkySock_lode_libs_utils_http_response.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_SkySock_lode_libs_utils_http_response.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Incorrect password",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_SkySock_lode_libs_utils_http_response.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_SkySock_lode_libs_utils_http_response.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_SkySock_lode_libs_utils_http_response.Problem"
                         }
                     }
                 }
             }
         },
-        "/auth/sign-out": {
-            "post": {
-                "description": "Делает недействительным refresh токен и удаляет файл cookie refreshToken",
+        "/account/email": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Отправляет на новый адрес ссылку для подтверждения. Email меняется после подтверждения через /auth/confirm-email, старый адрес получает уведомление",
                 "consumes": [
                     "application/json"
                 ],
+                "tags": [
+                    "Account"
+                ],
+                "summary": "Смена email",
+                "parameters": [
+                    {
+                        "description": "Новый email и пароль",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_SkySock_lode_libs_shared-dto_user_http_v1.ChangeEmailRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Accepted"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_SkySock_lode_libs_utils_http_response.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_SkySock_lode_libs_utils_http_response.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Incorrect password",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_SkySock_lode_libs_utils_http_response.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_SkySock_lode_libs_utils_http_response.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "Email is taken",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_SkySock_lode_libs_utils_http_response.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_SkySock_lode_libs_utils_http_response.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/account/export": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "JSON архив с аккаунтом, профилями, сессиями и событиями аутентификации",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Auth"
+                    "Account"
                 ],
-                "summary": "Выход из системы",
+                "summary": "Выгрузка данных",
                 "responses": {
                     "200": {
-                        "description": "Successfully logged out",
+                        "description": "OK",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/github_com_SkySock_lode_libs_shared-dto_user_http_v1.AccountExportResponse"
                         }
                     },
-                    "400": {
-                        "description": "Missing or empty refreshToken cookie",
+                    "401": {
+                        "description": "Unauthorized",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/github_com_SkySock_lode_libs_utils_http_response.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_SkySock_lode_libs_utils_http_response.Problem"
                         }
                     },
                     "500": {
-                        "description": "Failed to logout",
+                        "description": "Internal Server Error",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/github_com_SkySock_lode_libs_utils_http_response.Problem"
                         }
                     }
                 }
             }
         },
-        "/auth/sign-up": {
-            "post": {
-                "description": "Создание нового аккаунта",
-                "consumes": [
-                    "application/json"
+        "/account/security-events": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
                 ],
+                "description": "Последние входы, выходы и смены пароля аккаунта, начиная с новых",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Auth"
+                    "Account"
                 ],
-                "summary": "Регистрация пользователя",
+                "summary": "Активность безопасности",
                 "parameters": [
                     {
-                        "description": "Данные регистрации",
+                        "type": "string",
+                        "description": "Курсор следующей страницы",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "maximum": 50,
+                        "minimum": 1,
+                        "type": "integer",
+                        "default": 20,
+                        "description": "Размер страницы",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_SkySock_lode_libs_shared-dto_user_http_v1.AuthEventListResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_SkySock_lode_libs_utils_http_response.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_SkySock_lode_libs_utils_http_response.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_SkySock_lode_libs_utils_http_response.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/account/username": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Меняет имя пользователя. Менять его можно не чаще раза в период ожидания, старое имя какое-то время остается закрепленным за аккаунтом",
+                "consumes": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Account"
+                ],
+                "summary": "Смена имени пользователя",
+                "parameters": [
+                    {
+                        "description": "Новое имя и пароль",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/github_com_SkySock_lode_libs_shared-dto_user_http_v1.SignUpRequest"
+                            "$ref": "#/definitions/github_com_SkySock_lode_libs_shared-dto_user_http_v1.ChangeUsernameRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/github_com_SkySock_lode_libs_shared-dto_user_http_v1.SignUpResponse"
+                            "$ref": "#/definitions/github_com_SkySock_lode_libs_utils_http_response.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_SkySock_lode_libs_utils_http_response.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Incorrect password",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_SkySock_lode_libs_utils_http_response.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_SkySock_lode_libs_utils_http_response.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "Username is taken",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_SkySock_lode_libs_utils_http_response.Problem"
+                        }
+                    },
+                    "429": {
+                        "description": "Username changed too recently",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_SkySock_lode_libs_utils_http_response.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_SkySock_lode_libs_utils_http_response.Problem"
                         }
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "github_com_SkySock_lode_libs_shared-dto_user_http_v1.SignInRequest": {
-            "type": "object",
-            "required": [
-                "login",
-                "password"
-            ],
-            "properties": {
-                "login": {
-                    "type": "string",
-                    "example": "ozon671games"
-                },
-                "password": {
-                    "type": "string",
-                    "example": "Da1dfshgn$"
+        },
+        "/admin/accounts": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Ищет аккаунты по началу имени пользователя или email. Требуется разрешение accounts:read",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Поиск аккаунтов",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Начало имени пользователя или email",
+                        "name": "q",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Курсор следующей страницы",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "maximum": 100,
+                        "minimum": 1,
+                        "type": "integer",
+                        "default": 20,
+                        "description": "Размер страницы",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_SkySock_lode_libs_shared-dto_user_http_v1.AccountListResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_SkySock_lode_libs_utils_http_response.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_SkySock_lode_libs_utils_http_response.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_SkySock_lode_libs_utils_http_response.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_SkySock_lode_libs_utils_http_response.Problem"
+                        }
+                    }
                 }
             }
         },
-        "github_com_SkySock_lode_libs_shared-dto_user_http_v1.SignInResponse": {
-            "type": "object",
-            "properties": {
-                "accessToken": {
-                    "type": "string"
+        "/admin/accounts/{accountId}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Аккаунт с его профилями и активными сессиями. Требуется разрешение accounts:read",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Аккаунт",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID аккаунта",
+                        "name": "accountId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_SkySock_lode_libs_shared-dto_user_http_v1.AccountDetailsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_SkySock_lode_libs_utils_http_response.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_SkySock_lode_libs_utils_http_response.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_SkySock_lode_libs_utils_http_response.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_SkySock_lode_libs_utils_http_response.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_SkySock_lode_libs_utils_http_response.Problem"
+                        }
+                    }
                 }
             }
         },
-        "github_com_SkySock_lode_libs_shared-dto_user_http_v1.SignUpRequest": {
-            "type": "object",
-            "required": [
-                "email",
-                "password",
+        "/admin/accounts/{accountId}/lock": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Запрещает вход в аккаунт и завершает все его сессии. Требуется разрешение accounts:write",
+                "consumes": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Блокировка аккаунта",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID аккаунта",
+                        "name": "accountId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Причина блокировки",
+                        "name": "request",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_SkySock_lode_libs_shared-dto_user_http_v1.LockAccountRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_SkySock_lode_libs_utils_http_response.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_SkySock_lode_libs_utils_http_response.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_SkySock_lode_libs_utils_http_response.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_SkySock_lode_libs_utils_http_response.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_SkySock_lode_libs_utils_http_response.Problem"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Снимает блокировку с аккаунта. Требуется разрешение accounts:write",
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Разблокировка аккаунта",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID аккаунта",
+                        "name": "accountId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_SkySock_lode_libs_utils_http_response.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_SkySock_lode_libs_utils_http_response.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_SkySock_lode_libs_utils_http_response.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_SkySock_lode_libs_utils_http_response.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_SkySock_lode_libs_utils_http_response.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/accounts/{accountId}/logout": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Удаляет все сессии аккаунта и делает недействительными его access токены. Требуется разрешение accounts:write",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Завершение всех сессий",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID аккаунта",
+                        "name": "accountId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_SkySock_lode_libs_shared-dto_user_http_v1.ForceLogoutResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_SkySock_lode_libs_utils_http_response.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_SkySock_lode_libs_utils_http_response.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_SkySock_lode_libs_utils_http_response.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_SkySock_lode_libs_utils_http_response.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_SkySock_lode_libs_utils_http_response.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/accounts/{accountId}/password-reset": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Завершает все сессии аккаунта и не дает войти, пока пароль не будет сменен. Требуется разрешение accounts:write",
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Принудительный сброс пароля",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID аккаунта",
+                        "name": "accountId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_SkySock_lode_libs_utils_http_response.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_SkySock_lode_libs_utils_http_response.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_SkySock_lode_libs_utils_http_response.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_SkySock_lode_libs_utils_http_response.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_SkySock_lode_libs_utils_http_response.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/accounts/{accountId}/roles": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Выданные аккаунту роли. Требуется разрешение roles:read",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Роли аккаунта",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID аккаунта",
+                        "name": "accountId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/github_com_SkySock_lode_libs_shared-dto_user_http_v1.AccountRoleResponse"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_SkySock_lode_libs_utils_http_response.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_SkySock_lode_libs_utils_http_response.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_SkySock_lode_libs_utils_http_response.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_SkySock_lode_libs_utils_http_response.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_SkySock_lode_libs_utils_http_response.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/accounts/{accountId}/roles/{role}": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Выдает роль аккаунту. Роль попадает в access токен при следующем обновлении. Требуется разрешение roles:write",
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Выдача роли",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID аккаунта",
+                        "name": "accountId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Название роли",
+                        "name": "role",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_SkySock_lode_libs_utils_http_response.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_SkySock_lode_libs_utils_http_response.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_SkySock_lode_libs_utils_http_response.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Account or role not found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_SkySock_lode_libs_utils_http_response.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_SkySock_lode_libs_utils_http_response.Problem"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Отзывает роль у аккаунта и делает недействительными его access токены. Требуется разрешение roles:write",
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Отзыв роли",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID аккаунта",
+                        "name": "accountId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Название роли",
+                        "name": "role",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_SkySock_lode_libs_utils_http_response.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_SkySock_lode_libs_utils_http_response.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_SkySock_lode_libs_utils_http_response.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Role not granted",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_SkySock_lode_libs_utils_http_response.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "Last admin",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_SkySock_lode_libs_utils_http_response.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_SkySock_lode_libs_utils_http_response.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/auth-events": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "События аутентификации всех аккаунтов с фильтрами, начиная с новых. Требуется разрешение accounts:read",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "События аутентификации",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID аккаунта",
+                        "name": "accountId",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "sign_up",
+                            "sign_in",
+                            "sign_out",
+                            "password_change",
+                            "password_reset_forced",
+                            "sessions_terminated"
+                        ],
+                        "type": "string",
+                        "description": "Тип события",
+                        "name": "type",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "success",
+                            "failure"
+                        ],
+                        "type": "string",
+                        "description": "Результат",
+                        "name": "outcome",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "IP адрес клиента",
+                        "name": "ip",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Не раньше, RFC 3339",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Раньше, RFC 3339",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Курсор следующей страницы",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "maximum": 100,
+                        "minimum": 1,
+                        "type": "integer",
+                        "default": 20,
+                        "description": "Размер страницы",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_SkySock_lode_libs_shared-dto_user_http_v1.AuthEventListResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_SkySock_lode_libs_utils_http_response.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_SkySock_lode_libs_utils_http_response.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_SkySock_lode_libs_utils_http_response.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_SkySock_lode_libs_utils_http_response.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/profiles/{profileId}": {
+            "patch": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Меняет переданные поля профиля. Требуется разрешение accounts:write",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Редактирование профиля",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID профиля",
+                        "name": "profileId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Новые значения полей",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_SkySock_lode_libs_shared-dto_user_http_v1.UpdateProfileRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_SkySock_lode_libs_shared-dto_user_http_v1.ProfileResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_SkySock_lode_libs_utils_http_response.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_SkySock_lode_libs_utils_http_response.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_SkySock_lode_libs_utils_http_response.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_SkySock_lode_libs_utils_http_response.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_SkySock_lode_libs_utils_http_response.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/roles": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Роли и входящие в них разрешения. Требуется разрешение roles:read",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Список ролей",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/github_com_SkySock_lode_libs_shared-dto_user_http_v1.RoleResponse"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_SkySock_lode_libs_utils_http_response.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_SkySock_lode_libs_utils_http_response.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_SkySock_lode_libs_utils_http_response.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/webhooks": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Возвращает все подписки на события без секретов. Требуется разрешение webhooks:manage",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Список вебхуков",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_SkySock_lode_libs_shared-dto_user_http_v1.WebhookListResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_SkySock_lode_libs_utils_http_response.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_SkySock_lode_libs_utils_http_response.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_SkySock_lode_libs_utils_http_response.Problem"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Подписывает URL партнера на события. Доставки подписываются HMAC-SHA256 секретом, который возвращается только в этом ответе. Требуется разрешение webhooks:manage",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Создание вебхука",
+                "parameters": [
+                    {
+                        "description": "Подписка",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_SkySock_lode_libs_shared-dto_user_http_v1.CreateWebhookRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_SkySock_lode_libs_shared-dto_user_http_v1.WebhookResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_SkySock_lode_libs_utils_http_response.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_SkySock_lode_libs_utils_http_response.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_SkySock_lode_libs_utils_http_response.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_SkySock_lode_libs_utils_http_response.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/webhooks/{webhookId}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Удаляет подписку вместе с ее доставками. Требуется разрешение webhooks:manage",
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Удаление вебхука",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID вебхука",
+                        "name": "webhookId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_SkySock_lode_libs_utils_http_response.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_SkySock_lode_libs_utils_http_response.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_SkySock_lode_libs_utils_http_response.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_SkySock_lode_libs_utils_http_response.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_SkySock_lode_libs_utils_http_response.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/webhooks/{webhookId}/deliveries": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Доставки событий подписке, начиная с новых. Со статусом dead это список доставок, исчерпавших попытки. Требуется разрешение webhooks:manage",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Доставки вебхука",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID вебхука",
+                        "name": "webhookId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "pending",
+                            "delivered",
+                            "dead"
+                        ],
+                        "type": "string",
+                        "description": "Статус доставки",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Курсор следующей страницы",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "maximum": 100,
+                        "minimum": 1,
+                        "type": "integer",
+                        "default": 20,
+                        "description": "Размер страницы",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_SkySock_lode_libs_shared-dto_user_http_v1.WebhookDeliveryListResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_SkySock_lode_libs_utils_http_response.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_SkySock_lode_libs_utils_http_response.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_SkySock_lode_libs_utils_http_response.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_SkySock_lode_libs_utils_http_response.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_SkySock_lode_libs_utils_http_response.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/webhooks/{webhookId}/deliveries/{deliveryId}/replay": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Ставит доставку в очередь заново с полным набором попыток, в том числе доставленную или исчерпавшую попытки. Требуется разрешение webhooks:manage",
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Повтор доставки вебхука",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID вебхука",
+                        "name": "webhookId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID доставки",
+                        "name": "deliveryId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Accepted"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_SkySock_lode_libs_utils_http_response.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_SkySock_lode_libs_utils_http_response.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_SkySock_lode_libs_utils_http_response.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_SkySock_lode_libs_utils_http_response.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_SkySock_lode_libs_utils_http_response.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/change-password": {
+            "post": {
+                "description": "Меняет пароль и завершает все сессии аккаунта. Так же завершается сброс пароля, назначенный администратором",
+                "consumes": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "Смена пароля",
+                "parameters": [
+                    {
+                        "description": "Текущие данные входа и новый пароль",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_SkySock_lode_libs_shared-dto_user_http_v1.ChangePasswordRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_SkySock_lode_libs_utils_http_response.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_SkySock_lode_libs_utils_http_response.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Account locked or deleted",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_SkySock_lode_libs_utils_http_response.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_SkySock_lode_libs_utils_http_response.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/confirm-email": {
+            "post": {
+                "description": "Применяет смену email по токену из письма. Токен одноразовый",
+                "consumes": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "Подтверждение нового email",
+                "parameters": [
+                    {
+                        "description": "Токен из письма",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_SkySock_lode_libs_shared-dto_user_http_v1.ConfirmEmailRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Invalid or expired token",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_SkySock_lode_libs_utils_http_response.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "Email is taken",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_SkySock_lode_libs_utils_http_response.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_SkySock_lode_libs_utils_http_response.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/refresh": {
+            "post": {
+                "description": "Выдает новый access токен и заменяет refresh токен в файле cookie refreshToken",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "Обновление токенов",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_SkySock_lode_libs_shared-dto_user_http_v1.RefreshResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_SkySock_lode_libs_utils_http_response.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Cross-origin request rejected",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_SkySock_lode_libs_utils_http_response.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_SkySock_lode_libs_utils_http_response.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/restore-account": {
+            "post": {
+                "description": "Отменяет удаление аккаунта, пока оно не стало окончательным",
+                "consumes": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "Восстановление аккаунта",
+                "parameters": [
+                    {
+                        "description": "Данные входа",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_SkySock_lode_libs_shared-dto_user_http_v1.SignInRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_SkySock_lode_libs_utils_http_response.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_SkySock_lode_libs_utils_http_response.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Account locked",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_SkySock_lode_libs_utils_http_response.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "Account is not deleted",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_SkySock_lode_libs_utils_http_response.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_SkySock_lode_libs_utils_http_response.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/sign-in": {
+            "post": {
+                "description": "Вход в аккаунт пользователя",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "Вход в аккаунт",
+                "parameters": [
+                    {
+                        "description": "Данные регистрации",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_SkySock_lode_libs_shared-dto_user_http_v1.SignInRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_SkySock_lode_libs_shared-dto_user_http_v1.SignInResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_SkySock_lode_libs_utils_http_response.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_SkySock_lode_libs_utils_http_response.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Account locked, deleted or password reset required",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_SkySock_lode_libs_utils_http_response.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_SkySock_lode_libs_utils_http_response.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/sign-out": {
+            "post": {
+                "description": "Делает недействительным refresh токен и удаляет файл cookie refreshToken",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "Выход из системы",
+                "responses": {
+                    "200": {
+                        "description": "Successfully logged out",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Missing or empty refreshToken cookie",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_SkySock_lode_libs_utils_http_response.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Cross-origin request rejected",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_SkySock_lode_libs_utils_http_response.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to logout",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_SkySock_lode_libs_utils_http_response.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/sign-up": {
+            "post": {
+                "description": "Создание нового аккаунта",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "Регистрация пользователя",
+                "parameters": [
+                    {
+                        "description": "Данные регистрации",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_SkySock_lode_libs_shared-dto_user_http_v1.SignUpRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_SkySock_lode_libs_shared-dto_user_http_v1.SignUpResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_SkySock_lode_libs_utils_http_response.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_SkySock_lode_libs_utils_http_response.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_SkySock_lode_libs_utils_http_response.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/profiles": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Ищет профили по началу имени или отображаемого имени, а также по похожим именам. Без запроса возвращает все профили. Профили упорядочены по времени создания. Поиск выполняется от имени профиля из параметра as, а без него — от имени профиля, с которым выполнен вход: в результатах нет профилей, которые он заблокировал, и профилей, заблокировавших его",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Profile"
+                ],
+                "summary": "Поиск профилей",
+                "parameters": [
+                    {
+                        "maxLength": 64,
+                        "type": "string",
+                        "description": "Имя профиля или отображаемое имя",
+                        "name": "q",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID профиля аккаунта, от имени которого выполняется поиск",
+                        "name": "as",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Курсор следующей страницы",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "maximum": 100,
+                        "minimum": 1,
+                        "type": "integer",
+                        "default": 20,
+                        "description": "Размер страницы",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_SkySock_lode_libs_shared-dto_pagination.Page-github_com_SkySock_lode_libs_shared-dto_user_http_v1_ProfileResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_SkySock_lode_libs_utils_http_response.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "Параметр as указан без токена доступа",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_SkySock_lode_libs_utils_http_response.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Профиль из параметра as принадлежит другому аккаунту",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_SkySock_lode_libs_utils_http_response.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Профиль из параметра as не найден",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_SkySock_lode_libs_utils_http_response.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_SkySock_lode_libs_utils_http_response.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/profiles/{profileId}/avatar": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Заменяет аватар профиля аккаунта. Принимаются JPEG, PNG, GIF и WebP; формат определяется по содержимому файла. Изображение обрезается до квадрата, уменьшается до нескольких размеров и сохраняется в JPEG без метаданных",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Profile"
+                ],
+                "summary": "Загрузка аватара",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID профиля",
+                        "name": "profileId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "file",
+                        "description": "Изображение",
+                        "name": "avatar",
+                        "in": "formData",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_SkySock_lode_libs_shared-dto_user_http_v1.ProfileResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_SkySock_lode_libs_utils_http_response.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_SkySock_lode_libs_utils_http_response.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Профиль принадлежит другому аккаунту",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_SkySock_lode_libs_utils_http_response.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_SkySock_lode_libs_utils_http_response.Problem"
+                        }
+                    },
+                    "413": {
+                        "description": "Request Entity Too Large",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_SkySock_lode_libs_utils_http_response.Problem"
+                        }
+                    },
+                    "415": {
+                        "description": "Unsupported Media Type",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_SkySock_lode_libs_utils_http_response.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_SkySock_lode_libs_utils_http_response.Problem"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Удаляет аватар профиля аккаунта вместе с его уменьшенными копиями",
+                "tags": [
+                    "Profile"
+                ],
+                "summary": "Удаление аватара",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID профиля",
+                        "name": "profileId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_SkySock_lode_libs_utils_http_response.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_SkySock_lode_libs_utils_http_response.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Профиль принадлежит другому аккаунту",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_SkySock_lode_libs_utils_http_response.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_SkySock_lode_libs_utils_http_response.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_SkySock_lode_libs_utils_http_response.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/profiles/{profileId}/blocks": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Список профилей, заблокированных профилем аккаунта, новые первыми",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Relation"
+                ],
+                "summary": "Заблокированные профили",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID профиля аккаунта",
+                        "name": "profileId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Курсор следующей страницы",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "maximum": 100,
+                        "minimum": 1,
+                        "type": "integer",
+                        "default": 20,
+                        "description": "Размер страницы",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_SkySock_lode_libs_shared-dto_pagination.Page-github_com_SkySock_lode_libs_shared-dto_user_http_v1_RelationResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_SkySock_lode_libs_utils_http_response.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_SkySock_lode_libs_utils_http_response.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Профиль принадлежит другому аккаунту",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_SkySock_lode_libs_utils_http_response.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_SkySock_lode_libs_utils_http_response.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_SkySock_lode_libs_utils_http_response.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/profiles/{profileId}/blocks/{targetId}": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Блокирует профиль: удаляет подписки профилей друг на друга и запрещает новые, а также скрывает профили друг от друга в списках подписок",
+                "tags": [
+                    "Relation"
+                ],
+                "summary": "Блокировка профиля",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID профиля аккаунта",
+                        "name": "profileId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID блокируемого профиля",
+                        "name": "targetId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_SkySock_lode_libs_utils_http_response.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_SkySock_lode_libs_utils_http_response.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Профиль принадлежит другому аккаунту",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_SkySock_lode_libs_utils_http_response.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_SkySock_lode_libs_utils_http_response.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_SkySock_lode_libs_utils_http_response.Problem"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Снимает блокировку профиля. Удалённые блокировкой подписки не восстанавливаются",
+                "tags": [
+                    "Relation"
+                ],
+                "summary": "Разблокировка профиля",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID профиля аккаунта",
+                        "name": "profileId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID разблокируемого профиля",
+                        "name": "targetId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_SkySock_lode_libs_utils_http_response.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_SkySock_lode_libs_utils_http_response.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Профиль принадлежит другому аккаунту",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_SkySock_lode_libs_utils_http_response.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_SkySock_lode_libs_utils_http_response.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_SkySock_lode_libs_utils_http_response.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/profiles/{profileId}/follow-requests": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Список профилей, ожидающих подтверждения подписки на профиль аккаунта, новые первыми",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Relation"
+                ],
+                "summary": "Запросы на подписку",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID профиля аккаунта",
+                        "name": "profileId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Курсор следующей страницы",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "maximum": 100,
+                        "minimum": 1,
+                        "type": "integer",
+                        "default": 20,
+                        "description": "Размер страницы",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_SkySock_lode_libs_shared-dto_pagination.Page-github_com_SkySock_lode_libs_shared-dto_user_http_v1_RelationResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_SkySock_lode_libs_utils_http_response.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_SkySock_lode_libs_utils_http_response.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Профиль принадлежит другому аккаунту",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_SkySock_lode_libs_utils_http_response.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_SkySock_lode_libs_utils_http_response.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_SkySock_lode_libs_utils_http_response.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/profiles/{profileId}/follow-requests/{targetId}": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Подписывает отправивший запрос профиль на профиль аккаунта",
+                "tags": [
+                    "Relation"
+                ],
+                "summary": "Подтверждение запроса на подписку",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID профиля аккаунта",
+                        "name": "profileId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID профиля, отправившего запрос",
+                        "name": "targetId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_SkySock_lode_libs_utils_http_response.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_SkySock_lode_libs_utils_http_response.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Профиль принадлежит другому аккаунту или профили заблокировали друг друга",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_SkySock_lode_libs_utils_http_response.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Профиль или запрос на подписку не найден",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_SkySock_lode_libs_utils_http_response.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_SkySock_lode_libs_utils_http_response.Problem"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Удаляет запрос на подписку на профиль аккаунта. Отклонение отсутствующего запроса не является ошибкой",
+                "tags": [
+                    "Relation"
+                ],
+                "summary": "Отклонение запроса на подписку",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID профиля аккаунта",
+                        "name": "profileId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID профиля, отправившего запрос",
+                        "name": "targetId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_SkySock_lode_libs_utils_http_response.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_SkySock_lode_libs_utils_http_response.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Профиль принадлежит другому аккаунту",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_SkySock_lode_libs_utils_http_response.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_SkySock_lode_libs_utils_http_response.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_SkySock_lode_libs_utils_http_response.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/profiles/{profileId}/followers": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Список подписчиков профиля, новые первыми. Список просматривается от имени профиля из параметра as, а без него — от имени профиля, с которым выполнен вход: в нём нет профилей, которые он заблокировал или скрыл, и профилей, заблокировавших его. Списки закрытого профиля видны только его подписчикам и владельцу",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Relation"
+                ],
+                "summary": "Подписчики профиля",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID профиля",
+                        "name": "profileId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID профиля аккаунта, от имени которого просматривается список",
+                        "name": "as",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Курсор следующей страницы",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "maximum": 100,
+                        "minimum": 1,
+                        "type": "integer",
+                        "default": 20,
+                        "description": "Размер страницы",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_SkySock_lode_libs_shared-dto_pagination.Page-github_com_SkySock_lode_libs_shared-dto_user_http_v1_RelationResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_SkySock_lode_libs_utils_http_response.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "Параметр as указан без токена доступа",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_SkySock_lode_libs_utils_http_response.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Профиль закрыт или профиль из параметра as принадлежит другому аккаунту",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_SkySock_lode_libs_utils_http_response.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_SkySock_lode_libs_utils_http_response.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_SkySock_lode_libs_utils_http_response.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/profiles/{profileId}/following": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Список профилей, на которые подписан профиль, новые первыми. Список просматривается от имени профиля из параметра as, а без него — от имени профиля, с которым выполнен вход: в нём нет профилей, которые он заблокировал или скрыл, и профилей, заблокировавших его. Списки закрытого профиля видны только его подписчикам и владельцу",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Relation"
+                ],
+                "summary": "Подписки профиля",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID профиля",
+                        "name": "profileId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID профиля аккаунта, от имени которого просматривается список",
+                        "name": "as",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Курсор следующей страницы",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "maximum": 100,
+                        "minimum": 1,
+                        "type": "integer",
+                        "default": 20,
+                        "description": "Размер страницы",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_SkySock_lode_libs_shared-dto_pagination.Page-github_com_SkySock_lode_libs_shared-dto_user_http_v1_RelationResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_SkySock_lode_libs_utils_http_response.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "Параметр as указан без токена доступа",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_SkySock_lode_libs_utils_http_response.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Профиль закрыт или профиль из параметра as принадлежит другому аккаунту",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_SkySock_lode_libs_utils_http_response.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_SkySock_lode_libs_utils_http_response.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_SkySock_lode_libs_utils_http_response.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/profiles/{profileId}/following/{targetId}": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Подписывает профиль аккаунта на другой профиль. На закрытый профиль отправляется запрос на подписку, который подтверждает его владелец. Повторная подписка не является ошибкой. Подписаться нельзя, если один из профилей заблокировал другой",
+                "tags": [
+                    "Relation"
+                ],
+                "summary": "Подписка на профиль",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID профиля аккаунта",
+                        "name": "profileId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID профиля, на который подписываются",
+                        "name": "targetId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Отправлен запрос на подписку"
+                    },
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_SkySock_lode_libs_utils_http_response.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_SkySock_lode_libs_utils_http_response.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Профиль принадлежит другому аккаунту или профили заблокировали друг друга",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_SkySock_lode_libs_utils_http_response.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_SkySock_lode_libs_utils_http_response.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_SkySock_lode_libs_utils_http_response.Problem"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Отписывает профиль аккаунта от другого профиля и отзывает запрос на подписку",
+                "tags": [
+                    "Relation"
+                ],
+                "summary": "Отписка от профиля",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID профиля аккаунта",
+                        "name": "profileId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID профиля, от которого отписываются",
+                        "name": "targetId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_SkySock_lode_libs_utils_http_response.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_SkySock_lode_libs_utils_http_response.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Профиль принадлежит другому аккаунту",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_SkySock_lode_libs_utils_http_response.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_SkySock_lode_libs_utils_http_response.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_SkySock_lode_libs_utils_http_response.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/profiles/{profileId}/mutes": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Список профилей, скрытых профилем аккаунта, новые первыми",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Relation"
+                ],
+                "summary": "Скрытые профили",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID профиля аккаунта",
+                        "name": "profileId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Курсор следующей страницы",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "maximum": 100,
+                        "minimum": 1,
+                        "type": "integer",
+                        "default": 20,
+                        "description": "Размер страницы",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_SkySock_lode_libs_shared-dto_pagination.Page-github_com_SkySock_lode_libs_shared-dto_user_http_v1_RelationResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_SkySock_lode_libs_utils_http_response.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_SkySock_lode_libs_utils_http_response.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Профиль принадлежит другому аккаунту",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_SkySock_lode_libs_utils_http_response.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_SkySock_lode_libs_utils_http_response.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_SkySock_lode_libs_utils_http_response.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/profiles/{profileId}/mutes/{targetId}": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Скрывает профиль из списков подписок, просматриваемых от имени профиля аккаунта. Подписки не затрагиваются",
+                "tags": [
+                    "Relation"
+                ],
+                "summary": "Скрытие профиля",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID профиля аккаунта",
+                        "name": "profileId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID скрываемого профиля",
+                        "name": "targetId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_SkySock_lode_libs_utils_http_response.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_SkySock_lode_libs_utils_http_response.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Профиль принадлежит другому аккаунту",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_SkySock_lode_libs_utils_http_response.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_SkySock_lode_libs_utils_http_response.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_SkySock_lode_libs_utils_http_response.Problem"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Возвращает скрытый профиль в списки подписок",
+                "tags": [
+                    "Relation"
+                ],
+                "summary": "Отмена скрытия профиля",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID профиля аккаунта",
+                        "name": "profileId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID скрытого профиля",
+                        "name": "targetId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_SkySock_lode_libs_utils_http_response.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_SkySock_lode_libs_utils_http_response.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Профиль принадлежит другому аккаунту",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_SkySock_lode_libs_utils_http_response.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_SkySock_lode_libs_utils_http_response.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_SkySock_lode_libs_utils_http_response.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/profiles/{profileId}/privacy": {
+            "patch": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Меняет переданные настройки приватности профиля аккаунта. Био и аватар закрытого профиля видны только его подписчикам, как и его подписки и подписчики. Профиль, не доступный для поиска, не попадает в результаты поиска",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Profile"
+                ],
+                "summary": "Настройки приватности профиля",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID профиля",
+                        "name": "profileId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Новые значения настроек",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_SkySock_lode_libs_shared-dto_user_http_v1.UpdatePrivacyRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_SkySock_lode_libs_shared-dto_user_http_v1.ProfileResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_SkySock_lode_libs_utils_http_response.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_SkySock_lode_libs_utils_http_response.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Профиль принадлежит другому аккаунту",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_SkySock_lode_libs_utils_http_response.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_SkySock_lode_libs_utils_http_response.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_SkySock_lode_libs_utils_http_response.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/profiles/{profileName}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Данные профиля по его имени. Био и аватар видны в соответствии с настройками приватности профиля: профиль просматривается от имени профиля из параметра as, а без него — от имени профиля, с которым выполнен вход. Владельцу профиля видны все поля и настройки приватности",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Profile"
+                ],
+                "summary": "Получение профиля",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Имя профиля",
+                        "name": "profileName",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID профиля аккаунта, от имени которого просматривается профиль",
+                        "name": "as",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_SkySock_lode_libs_shared-dto_user_http_v1.ProfileResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_SkySock_lode_libs_utils_http_response.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "Параметр as указан без токена доступа",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_SkySock_lode_libs_utils_http_response.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Профиль из параметра as принадлежит другому аккаунту",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_SkySock_lode_libs_utils_http_response.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_SkySock_lode_libs_utils_http_response.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_SkySock_lode_libs_utils_http_response.Problem"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "github_com_SkySock_lode_libs_shared-dto_pagination.Page-github_com_SkySock_lode_libs_shared-dto_user_http_v1_ProfileResponse": {
+            "type": "object",
+            "properties": {
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_SkySock_lode_libs_shared-dto_user_http_v1.ProfileResponse"
+                    }
+                },
+                "nextCursor": {
+                    "description": "NextCursor is omitted on the last page.",
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_SkySock_lode_libs_shared-dto_pagination.Page-github_com_SkySock_lode_libs_shared-dto_user_http_v1_RelationResponse": {
+            "type": "object",
+            "properties": {
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_SkySock_lode_libs_shared-dto_user_http_v1.RelationResponse"
+                    }
+                },
+                "nextCursor": {
+                    "description": "NextCursor is omitted on the last page.",
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_SkySock_lode_libs_shared-dto_user_http_v1.AccountDeletionResponse": {
+            "type": "object",
+            "properties": {
+                "purgeAfter": {
+                    "description": "PurgeAfter is when the account and its data are removed for good. Until\nthen the account can be restored.",
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_SkySock_lode_libs_shared-dto_user_http_v1.AccountDetailsResponse": {
+            "type": "object",
+            "properties": {
+                "account": {
+                    "$ref": "#/definitions/github_com_SkySock_lode_libs_shared-dto_user_http_v1.AccountResponse"
+                },
+                "profiles": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_SkySock_lode_libs_shared-dto_user_http_v1.ProfileResponse"
+                    }
+                },
+                "sessions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_SkySock_lode_libs_shared-dto_user_http_v1.SessionResponse"
+                    }
+                }
+            }
+        },
+        "github_com_SkySock_lode_libs_shared-dto_user_http_v1.AccountExportResponse": {
+            "type": "object",
+            "properties": {
+                "account": {
+                    "$ref": "#/definitions/github_com_SkySock_lode_libs_shared-dto_user_http_v1.AccountResponse"
+                },
+                "authEvents": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_SkySock_lode_libs_shared-dto_user_http_v1.AuthEventResponse"
+                    }
+                },
+                "exportedAt": {
+                    "type": "string"
+                },
+                "identityChanges": {
+                    "description": "IdentityChanges are the past usernames and emails of the account.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_SkySock_lode_libs_shared-dto_user_http_v1.IdentityChangeResponse"
+                    }
+                },
+                "profiles": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_SkySock_lode_libs_shared-dto_user_http_v1.ProfileResponse"
+                    }
+                },
+                "sessions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_SkySock_lode_libs_shared-dto_user_http_v1.SessionResponse"
+                    }
+                }
+            }
+        },
+        "github_com_SkySock_lode_libs_shared-dto_user_http_v1.AccountListResponse": {
+            "type": "object",
+            "properties": {
+                "accounts": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_SkySock_lode_libs_shared-dto_user_http_v1.AccountResponse"
+                    }
+                },
+                "nextCursor": {
+                    "description": "NextCursor is omitted on the last page.",
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_SkySock_lode_libs_shared-dto_user_http_v1.AccountResponse": {
+            "type": "object",
+            "properties": {
+                "createdAt": {
+                    "type": "string"
+                },
+                "deletedAt": {
+                    "type": "string"
+                },
+                "email": {
+                    "type": "string",
+                    "example": "example@example.com"
+                },
+                "id": {
+                    "type": "string",
+                    "example": "01976451-00b3-7e32-9340-4f999c6c5edd"
+                },
+                "lockReason": {
+                    "type": "string"
+                },
+                "lockedAt": {
+                    "type": "string"
+                },
+                "passwordResetRequired": {
+                    "type": "boolean"
+                },
+                "username": {
+                    "type": "string",
+                    "example": "ozon671games"
+                },
+                "usernameChangedAt": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_SkySock_lode_libs_shared-dto_user_http_v1.AccountRoleResponse": {
+            "type": "object",
+            "properties": {
+                "grantedAt": {
+                    "type": "string"
+                },
+                "grantedBy": {
+                    "type": "string",
+                    "example": "01976451-00b3-7e32-9340-4f999c6c5edd"
+                },
+                "role": {
+                    "type": "string",
+                    "example": "admin"
+                }
+            }
+        },
+        "github_com_SkySock_lode_libs_shared-dto_user_http_v1.AuthEventListResponse": {
+            "type": "object",
+            "properties": {
+                "events": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_SkySock_lode_libs_shared-dto_user_http_v1.AuthEventResponse"
+                    }
+                },
+                "nextCursor": {
+                    "description": "NextCursor is omitted on the last page.",
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_SkySock_lode_libs_shared-dto_user_http_v1.AuthEventResponse": {
+            "type": "object",
+            "properties": {
+                "accountId": {
+                    "description": "AccountID is only set in the admin API.",
+                    "type": "string"
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "ip": {
+                    "type": "string",
+                    "example": "203.0.113.7"
+                },
+                "outcome": {
+                    "type": "string",
+                    "example": "failure"
+                },
+                "reason": {
+                    "type": "string",
+                    "example": "incorrect_password"
+                },
+                "type": {
+                    "type": "string",
+                    "example": "sign_in"
+                },
+                "userAgent": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_SkySock_lode_libs_shared-dto_user_http_v1.ChangeEmailRequest": {
+            "type": "object",
+            "required": [
+                "email",
+                "password"
+            ],
+            "properties": {
+                "email": {
+                    "type": "string",
+                    "maxLength": 254,
+                    "example": "example@example.com"
+                },
+                "password": {
+                    "type": "string",
+                    "example": "Da1dfshgn$"
+                }
+            }
+        },
+        "github_com_SkySock_lode_libs_shared-dto_user_http_v1.ChangePasswordRequest": {
+            "type": "object",
+            "required": [
+                "login",
+                "newPassword",
+                "password"
+            ],
+            "properties": {
+                "login": {
+                    "type": "string",
+                    "example": "ozon671games"
+                },
+                "newPassword": {
+                    "type": "string",
+                    "example": "Nw2dfshgn$"
+                },
+                "password": {
+                    "type": "string",
+                    "example": "Da1dfshgn$"
+                }
+            }
+        },
+        "github_com_SkySock_lode_libs_shared-dto_user_http_v1.ChangeUsernameRequest": {
+            "type": "object",
+            "required": [
+                "password",
+                "username"
+            ],
+            "properties": {
+                "password": {
+                    "type": "string",
+                    "example": "Da1dfshgn$"
+                },
+                "username": {
+                    "type": "string",
+                    "maxLength": 50,
+                    "minLength": 1,
+                    "example": "ozon671games"
+                }
+            }
+        },
+        "github_com_SkySock_lode_libs_shared-dto_user_http_v1.ConfirmEmailRequest": {
+            "type": "object",
+            "required": [
+                "token"
+            ],
+            "properties": {
+                "token": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_SkySock_lode_libs_shared-dto_user_http_v1.CreateWebhookRequest": {
+            "type": "object",
+            "required": [
+                "eventTypes",
+                "url"
+            ],
+            "properties": {
+                "description": {
+                    "type": "string",
+                    "maxLength": 200,
+                    "example": "CRM sync"
+                },
+                "eventTypes": {
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "user.registered",
+                        "profile.updated"
+                    ]
+                },
+                "url": {
+                    "type": "string",
+                    "maxLength": 2048,
+                    "example": "https://partner.example.com/hooks/lode"
+                }
+            }
+        },
+        "github_com_SkySock_lode_libs_shared-dto_user_http_v1.DeleteAccountRequest": {
+            "type": "object",
+            "required": [
+                "password"
+            ],
+            "properties": {
+                "password": {
+                    "type": "string",
+                    "example": "Da1dfshgn$"
+                }
+            }
+        },
+        "github_com_SkySock_lode_libs_shared-dto_user_http_v1.ForceLogoutResponse": {
+            "type": "object",
+            "properties": {
+                "terminatedSessions": {
+                    "type": "integer",
+                    "example": 2
+                }
+            }
+        },
+        "github_com_SkySock_lode_libs_shared-dto_user_http_v1.IdentityChangeResponse": {
+            "type": "object",
+            "properties": {
+                "changedAt": {
+                    "type": "string"
+                },
+                "kind": {
+                    "type": "string",
+                    "example": "username"
+                },
+                "newValue": {
+                    "type": "string",
+                    "example": "ozon672games"
+                },
+                "oldValue": {
+                    "type": "string",
+                    "example": "ozon671games"
+                }
+            }
+        },
+        "github_com_SkySock_lode_libs_shared-dto_user_http_v1.LockAccountRequest": {
+            "type": "object",
+            "properties": {
+                "reason": {
+                    "type": "string",
+                    "maxLength": 500,
+                    "example": "Spam"
+                }
+            }
+        },
+        "github_com_SkySock_lode_libs_shared-dto_user_http_v1.ProfilePrivacyResponse": {
+            "type": "object",
+            "properties": {
+                "avatarVisibility": {
+                    "type": "string",
+                    "example": "everyone"
+                },
+                "bioVisibility": {
+                    "type": "string",
+                    "example": "followers"
+                },
+                "discoverable": {
+                    "type": "boolean"
+                },
+                "isPrivate": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "github_com_SkySock_lode_libs_shared-dto_user_http_v1.ProfileResponse": {
+            "type": "object",
+            "properties": {
+                "avatar": {
+                    "type": "string"
+                },
+                "avatarThumbnails": {
+                    "description": "AvatarThumbnails maps square sizes of an uploaded avatar, in pixels, to\ntheir URLs. Avatar is the URL of the largest one.",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "bio": {
+                    "type": "string"
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "displayName": {
+                    "type": "string"
+                },
+                "followersCount": {
+                    "type": "integer"
+                },
+                "followingCount": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "string",
+                    "example": "01976451-00b3-7e32-9340-4f999c6c5edd"
+                },
+                "isPrivate": {
+                    "type": "boolean"
+                },
+                "privacy": {
+                    "description": "Privacy is only shown to the owner of the profile.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/github_com_SkySock_lode_libs_shared-dto_user_http_v1.ProfilePrivacyResponse"
+                        }
+                    ]
+                },
+                "profileName": {
+                    "type": "string",
+                    "example": "ozon671games"
+                },
+                "userId": {
+                    "type": "string",
+                    "example": "01976451-00b3-7e32-9340-4f999c6c5edd"
+                }
+            }
+        },
+        "github_com_SkySock_lode_libs_shared-dto_user_http_v1.RefreshResponse": {
+            "type": "object",
+            "properties": {
+                "accessToken": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_SkySock_lode_libs_shared-dto_user_http_v1.RelationResponse": {
+            "type": "object",
+            "properties": {
+                "createdAt": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string",
+                    "example": "01976451-00b3-7e32-9340-4f999c6c5edd"
+                },
+                "profile": {
+                    "$ref": "#/definitions/github_com_SkySock_lode_libs_shared-dto_user_http_v1.ProfileResponse"
+                }
+            }
+        },
+        "github_com_SkySock_lode_libs_shared-dto_user_http_v1.RoleResponse": {
+            "type": "object",
+            "properties": {
+                "description": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "admin"
+                },
+                "permissions": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "roles:read",
+                        "roles:write"
+                    ]
+                }
+            }
+        },
+        "github_com_SkySock_lode_libs_shared-dto_user_http_v1.SessionResponse": {
+            "type": "object",
+            "properties": {
+                "expiresAt": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "profileId": {
+                    "type": "string",
+                    "example": "01976451-00b3-7e32-9340-4f999c6c5edd"
+                }
+            }
+        },
+        "github_com_SkySock_lode_libs_shared-dto_user_http_v1.SignInRequest": {
+            "type": "object",
+            "required": [
+                "login",
+                "password"
+            ],
+            "properties": {
+                "login": {
+                    "type": "string",
+                    "example": "ozon671games"
+                },
+                "password": {
+                    "type": "string",
+                    "example": "Da1dfshgn$"
+                }
+            }
+        },
+        "github_com_SkySock_lode_libs_shared-dto_user_http_v1.SignInResponse": {
+            "type": "object",
+            "properties": {
+                "accessToken": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_SkySock_lode_libs_shared-dto_user_http_v1.SignUpRequest": {
+            "type": "object",
+            "required": [
+                "email",
+                "password",
                 "username"
             ],
             "properties": {
@@ -177,6 +3660,177 @@ const docTemplate = `{
                     "example": "01976451-00b3-7e32-9340-4f999c6c5edd"
                 }
             }
+        },
+        "github_com_SkySock_lode_libs_shared-dto_user_http_v1.UpdatePrivacyRequest": {
+            "type": "object",
+            "properties": {
+                "avatarVisibility": {
+                    "type": "string",
+                    "enum": [
+                        "everyone",
+                        "followers",
+                        "nobody"
+                    ],
+                    "example": "everyone"
+                },
+                "bioVisibility": {
+                    "type": "string",
+                    "enum": [
+                        "everyone",
+                        "followers",
+                        "nobody"
+                    ],
+                    "example": "followers"
+                },
+                "discoverable": {
+                    "type": "boolean"
+                },
+                "isPrivate": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "github_com_SkySock_lode_libs_shared-dto_user_http_v1.UpdateProfileRequest": {
+            "type": "object",
+            "properties": {
+                "avatar": {
+                    "type": "string",
+                    "maxLength": 500
+                },
+                "bio": {
+                    "type": "string",
+                    "maxLength": 1000
+                },
+                "displayName": {
+                    "type": "string",
+                    "maxLength": 100
+                }
+            }
+        },
+        "github_com_SkySock_lode_libs_shared-dto_user_http_v1.WebhookDeliveryListResponse": {
+            "type": "object",
+            "properties": {
+                "deliveries": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_SkySock_lode_libs_shared-dto_user_http_v1.WebhookDeliveryResponse"
+                    }
+                },
+                "nextCursor": {
+                    "description": "NextCursor is omitted on the last page.",
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_SkySock_lode_libs_shared-dto_user_http_v1.WebhookDeliveryResponse": {
+            "type": "object",
+            "properties": {
+                "attempts": {
+                    "type": "integer",
+                    "example": 10
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "deliveredAt": {
+                    "type": "string"
+                },
+                "eventId": {
+                    "type": "string"
+                },
+                "eventType": {
+                    "type": "string",
+                    "example": "user.registered"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "lastError": {
+                    "type": "string"
+                },
+                "lastStatusCode": {
+                    "type": "integer",
+                    "example": 502
+                },
+                "nextAttemptAt": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string",
+                    "example": "dead"
+                }
+            }
+        },
+        "github_com_SkySock_lode_libs_shared-dto_user_http_v1.WebhookListResponse": {
+            "type": "object",
+            "properties": {
+                "webhooks": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_SkySock_lode_libs_shared-dto_user_http_v1.WebhookResponse"
+                    }
+                }
+            }
+        },
+        "github_com_SkySock_lode_libs_shared-dto_user_http_v1.WebhookResponse": {
+            "type": "object",
+            "properties": {
+                "createdAt": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "eventTypes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "user.registered",
+                        "profile.updated"
+                    ]
+                },
+                "id": {
+                    "type": "string"
+                },
+                "secret": {
+                    "description": "Secret signs the deliveries. It is only returned when the webhook is\ncreated.",
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string",
+                    "example": "https://partner.example.com/hooks/lode"
+                }
+            }
+        },
+        "github_com_SkySock_lode_libs_utils_http_response.Problem": {
+            "type": "object",
+            "properties": {
+                "detail": {
+                    "type": "string"
+                },
+                "instance": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "integer"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string"
+                }
+            }
+        }
+    },
+    "securityDefinitions": {
+        "BearerAuth": {
+            "description": "Access токен в формате \"Bearer \u003ctoken\u003e\"",
+            "type": "apiKey",
+            "name": "Authorization",
+            "in": "header"
         }
     }
 }`
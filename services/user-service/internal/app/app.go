@@ -5,17 +5,34 @@ import (
 	"errors"
 	"fmt"
 	"log/slog"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
 	"syscall"
 	"time"
 
+	events "github.com/SkySock/lode/libs/shared-dto/user/events/v1"
+	"github.com/SkySock/lode/libs/utils/http/health"
+	"github.com/SkySock/lode/libs/utils/http/middleware"
+	"github.com/SkySock/lode/services/user-service/internal/avatar"
+	"github.com/SkySock/lode/services/user-service/internal/broker"
 	"github.com/SkySock/lode/services/user-service/internal/config"
 	"github.com/SkySock/lode/services/user-service/internal/db"
+	grpcUser "github.com/SkySock/lode/services/user-service/internal/handler/grpc/v1/user"
+	"github.com/SkySock/lode/services/user-service/internal/handler/http/oauth"
+	v1Account "github.com/SkySock/lode/services/user-service/internal/handler/http/v1/account"
+	v1Admin "github.com/SkySock/lode/services/user-service/internal/handler/http/v1/admin"
 	v1Auth "github.com/SkySock/lode/services/user-service/internal/handler/http/v1/auth"
+	v1Profile "github.com/SkySock/lode/services/user-service/internal/handler/http/v1/profile"
+	"github.com/SkySock/lode/services/user-service/internal/mailer"
+	"github.com/SkySock/lode/services/user-service/internal/metrics"
 	"github.com/SkySock/lode/services/user-service/internal/repository"
+	"github.com/SkySock/lode/services/user-service/internal/storage"
 	"github.com/SkySock/lode/services/user-service/internal/usecase"
+	"github.com/SkySock/lode/services/user-service/internal/webhook"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
 	"github.com/valkey-io/valkey-go"
 )
 
@@ -30,29 +47,151 @@ func Run(cfg *config.Config) {
 
 	log.Info("starting application", slog.String("env", cfg.Env))
 
+	reg := prometheus.NewRegistry()
+	reg.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+	)
+
 	ctx := context.Background()
 	pool := db.InitPool(ctx, cfg.DB, log)
 	defer db.ClosePool(pool)
+	reg.MustRegister(metrics.NewPoolCollector(pool))
 
-	client, err := valkey.NewClient(valkey.ClientOption{InitAddress: []string{cfg.Valkey.Addr}})
+	valkeyClient, err := valkey.NewClient(valkey.ClientOption{InitAddress: []string{cfg.Valkey.Addr}})
 	if err != nil {
 		panic(err)
 	}
-	defer client.Close()
+	defer valkeyClient.Close()
+	client := metrics.InstrumentValkey(valkeyClient, reg)
+
+	checker := health.NewChecker(cfg.Health.CheckTimeout)
+	checker.Register("postgres", pool.Ping)
+	checker.Register("valkey", func(ctx context.Context) error {
+		return client.Do(ctx, client.B().Ping().Build()).Error()
+	})
 
 	sessionRepo := repository.NewSessionRepository(client)
 	accountRepo := repository.NewAccountRepository()
 	profileRepo := repository.NewProfileRepository()
+	roleRepo := repository.NewRoleRepository()
+	denylist := repository.NewTokenDenylist(client)
+	auditRepo := repository.NewAuditRepository()
+	eventRepo := repository.NewAuthEventRepository()
+	identityRepo := repository.NewIdentityRepository()
+	emailChangeRepo := repository.NewEmailChangeRepository(client)
+	outboxRepo := repository.NewOutboxRepository()
+	webhookRepo := repository.NewWebhookRepository()
+	relationRepo := repository.NewRelationRepository()
+
+	avatarStore, err := storage.New(cfg.Storage)
+	if err != nil {
+		panic(err)
+	}
+	avatars := avatar.NewProcessor(cfg.Avatar.MaxPixels, cfg.Avatar.Sizes, cfg.Avatar.Quality)
+
+	authUsecase := usecase.NewAuthUsecase(pool, accountRepo, sessionRepo, profileRepo, roleRepo, denylist, eventRepo, identityRepo, outboxRepo, cfg.Auth, metrics.NewAuthMetrics(reg))
+	profileUsecase := usecase.NewProfileUsecase(pool, profileRepo, relationRepo, outboxRepo, avatarStore, avatars)
+	relationUsecase := usecase.NewRelationUsecase(pool, relationRepo, profileRepo, outboxRepo)
+	roleUsecase := usecase.NewRoleUsecase(pool, roleRepo, accountRepo, auditRepo, authUsecase)
+	adminUsecase := usecase.NewAdminUsecase(pool, accountRepo, profileRepo, auditRepo, eventRepo, outboxRepo, avatarStore, authUsecase)
+	authEventUsecase := usecase.NewAuthEventUsecase(pool, eventRepo)
+	accountUsecase := usecase.NewAccountUsecase(pool, accountRepo, profileRepo, eventRepo, auditRepo, identityRepo, relationRepo, outboxRepo, webhookRepo, avatarStore, authUsecase, cfg.Deletion)
+	webhookUsecase := usecase.NewWebhookUsecase(pool, webhookRepo, auditRepo, webhook.NewSender(cfg.Webhook.Timeout), cfg.Webhook)
+	publisher := broker.Fanout(broker.NewStreamPublisher(client, events.Stream, cfg.Outbox.StreamMaxLen), webhookUsecase)
+	outboxRelay := usecase.NewOutboxRelay(pool, outboxRepo, publisher, cfg.Outbox.BatchSize)
+	identityUsecase := usecase.NewIdentityUsecase(pool, accountRepo, identityRepo, emailChangeRepo, eventRepo, mailer.New(cfg.Mail, log), cfg.Identity)
 
-	authUsecase := usecase.NewAuthUsecase(pool, accountRepo, sessionRepo, profileRepo, cfg.Auth)
+	if cfg.RBAC.BootstrapAdmin != "" {
+		if err := roleUsecase.BootstrapAdmin(ctx, cfg.RBAC.BootstrapAdmin); err != nil {
+			log.Warn("Failed to bootstrap admin",
+				slog.String("username", cfg.RBAC.BootstrapAdmin),
+				slog.String("error", err.Error()),
+			)
+		}
+	}
+
+	cookie := v1Auth.CookieOptions{
+		Domain:   cfg.Auth.Cookie.Domain,
+		Path:     cfg.Auth.Cookie.Path,
+		SameSite: cfg.Auth.Cookie.SameSiteMode(),
+	}
+
+	oauthClients := make([]oauth.Client, 0, len(cfg.Auth.OAuth.Clients))
+	for _, c := range cfg.Auth.OAuth.Clients {
+		oauthClients = append(oauthClients, oauth.Client{ID: c.ID, Secret: c.Secret, Audience: c.Audience})
+	}
 
 	controllers := controllers{
 		SignUp:  v1Auth.NewSignUp(log, authUsecase),
-		SignIn:  v1Auth.NewSignIn(log, authUsecase),
-		SignOut: v1Auth.NewSignOut(log, authUsecase),
+		SignIn:  v1Auth.NewSignIn(log, authUsecase, cookie),
+		SignOut: v1Auth.NewSignOut(log, authUsecase, cookie),
+		Refresh: v1Auth.NewRefresh(log, authUsecase, cookie),
+
+		ChangePassword: v1Auth.NewChangePassword(log, authUsecase),
+		RestoreAccount: v1Auth.NewRestoreAccount(log, authUsecase),
+		ConfirmEmail:   v1Auth.NewConfirmEmail(log, identityUsecase),
+
+		GetProfile:     v1Profile.NewGetProfile(log, profileUsecase),
+		SearchProfiles: v1Profile.NewSearchProfiles(log, profileUsecase),
+		UploadAvatar:   v1Profile.NewUploadAvatar(log, profileUsecase, cfg.Avatar.MaxBytes),
+		DeleteAvatar:   v1Profile.NewDeleteAvatar(log, profileUsecase),
+		UpdatePrivacy:  v1Profile.NewUpdatePrivacy(log, profileUsecase),
+
+		Follow:        v1Profile.NewFollow(log, relationUsecase),
+		Unfollow:      v1Profile.NewUnfollow(log, relationUsecase),
+		Block:         v1Profile.NewBlock(log, relationUsecase),
+		Unblock:       v1Profile.NewUnblock(log, relationUsecase),
+		Mute:          v1Profile.NewMute(log, relationUsecase),
+		Unmute:        v1Profile.NewUnmute(log, relationUsecase),
+		ListFollowers: v1Profile.NewListFollowers(log, relationUsecase),
+		ListFollowing: v1Profile.NewListFollowing(log, relationUsecase),
+		ListBlocked:   v1Profile.NewListBlocked(log, relationUsecase),
+		ListMuted:     v1Profile.NewListMuted(log, relationUsecase),
+
+		ListFollowRequests:   v1Profile.NewListFollowRequests(log, relationUsecase),
+		ApproveFollowRequest: v1Profile.NewApproveFollowRequest(log, relationUsecase),
+		DeclineFollowRequest: v1Profile.NewDeclineFollowRequest(log, relationUsecase),
+
+		ListSecurityEvents: v1Account.NewListSecurityEvents(log, authEventUsecase),
+		DeleteAccount:      v1Account.NewDeleteAccount(log, accountUsecase),
+		ExportData:         v1Account.NewExportData(log, accountUsecase),
+		ChangeUsername:     v1Account.NewChangeUsername(log, identityUsecase),
+		ChangeEmail:        v1Account.NewChangeEmail(log, identityUsecase),
+
+		Introspect: oauth.NewIntrospect(log, authUsecase, oauthClients),
+		Revoke:     oauth.NewRevoke(log, authUsecase),
+
+		ListRoles:       v1Admin.NewListRoles(log, roleUsecase),
+		GetAccountRoles: v1Admin.NewGetAccountRoles(log, roleUsecase),
+		GrantRole:       v1Admin.NewGrantRole(log, roleUsecase),
+		RevokeRole:      v1Admin.NewRevokeRole(log, roleUsecase),
+
+		SearchAccounts:     v1Admin.NewSearchAccounts(log, adminUsecase),
+		GetAccount:         v1Admin.NewGetAccount(log, adminUsecase),
+		LockAccount:        v1Admin.NewLockAccount(log, adminUsecase),
+		UnlockAccount:      v1Admin.NewUnlockAccount(log, adminUsecase),
+		ForcePasswordReset: v1Admin.NewForcePasswordReset(log, adminUsecase),
+		ForceLogout:        v1Admin.NewForceLogout(log, adminUsecase),
+		UpdateProfile:      v1Admin.NewUpdateProfile(log, adminUsecase),
+		ListAuthEvents:     v1Admin.NewListAuthEvents(log, authEventUsecase),
+
+		CreateWebhook:         v1Admin.NewCreateWebhook(log, webhookUsecase),
+		ListWebhooks:          v1Admin.NewListWebhooks(log, webhookUsecase),
+		DeleteWebhook:         v1Admin.NewDeleteWebhook(log, webhookUsecase),
+		ListWebhookDeliveries: v1Admin.NewListWebhookDeliveries(log, webhookUsecase),
+		ReplayWebhookDelivery: v1Admin.NewReplayWebhookDelivery(log, webhookUsecase),
 	}
 
-	r := newRouter(log, controllers)
+	trustedProxies, err := middleware.ParseTrustedProxies(cfg.HTTP.TrustedProxies)
+	if err != nil {
+		panic(err)
+	}
+
+	// The local store serves its files itself; others are served by the store.
+	controllers.Media, _ = avatarStore.(*storage.Local)
+
+	r := newRouter(log, reg, checker, cfg.Auth.CSRF, trustedProxies, authUsecase.VerifyAccessToken, controllers)
 
 	s := &http.Server{
 		Addr:         fmt.Sprintf("%s:%d", cfg.HTTP.Host, cfg.HTTP.Port),
@@ -62,10 +201,21 @@ func Run(cfg *config.Config) {
 		IdleTimeout:  time.Second * 60,
 	}
 
+	admin := &http.Server{
+		Addr:         fmt.Sprintf("%s:%d", cfg.Admin.Host, cfg.Admin.Port),
+		Handler:      newAdminRouter(reg),
+		WriteTimeout: time.Second * 15,
+		ReadTimeout:  time.Second * 15,
+		IdleTimeout:  time.Second * 60,
+	}
+
+	grpcServer, grpcHealth := newGRPCServer(log, grpcUser.NewServer(log, accountUsecase, profileUsecase, authUsecase))
+	grpcAddr := fmt.Sprintf("%s:%d", cfg.GRPC.Host, cfg.GRPC.Port)
+
 	stop := make(chan os.Signal, 1)
 	signal.Notify(stop, os.Interrupt, syscall.SIGTERM)
 
-	serverErrors := make(chan error, 1)
+	serverErrors := make(chan error, 3)
 
 	go func() {
 		log.Info("Starting HTTP server", slog.String("addr", s.Addr))
@@ -75,6 +225,38 @@ func Run(cfg *config.Config) {
 		}
 	}()
 
+	go func() {
+		log.Info("Starting gRPC server", slog.String("addr", grpcAddr))
+		lis, err := net.Listen("tcp", grpcAddr)
+		if err != nil {
+			serverErrors <- err
+			return
+		}
+		if err := grpcServer.Serve(lis); err != nil {
+			serverErrors <- err
+		}
+	}()
+
+	jobsCtx, stopJobs := context.WithCancel(ctx)
+	defer stopJobs()
+	if cfg.Deletion.PurgeInterval > 0 {
+		go runBatchJob(jobsCtx, log.With(slog.String("job", "purge_deleted_accounts")), cfg.Deletion.PurgeInterval, accountUsecase.PurgeDeletedAccounts)
+	}
+	if cfg.Outbox.RelayInterval > 0 {
+		go runBatchJob(jobsCtx, log.With(slog.String("job", "outbox_relay")), cfg.Outbox.RelayInterval, outboxRelay.Relay)
+	}
+	if cfg.Webhook.DeliveryInterval > 0 {
+		go runBatchJob(jobsCtx, log.With(slog.String("job", "webhook_delivery")), cfg.Webhook.DeliveryInterval, webhookUsecase.DeliverDue)
+	}
+
+	go func() {
+		log.Info("Starting admin HTTP server", slog.String("addr", admin.Addr))
+		err := admin.ListenAndServe()
+		if err != nil && !errors.Is(err, http.ErrServerClosed) {
+			serverErrors <- err
+		}
+	}()
+
 	select {
 	case err := <-serverErrors:
 		log.Error("Server error", slog.String("error", err.Error()))
@@ -84,6 +266,12 @@ func Run(cfg *config.Config) {
 		log.Info("Received signal:", slog.String("signal", sig.String()))
 		log.Info("Shutting down gracefully...")
 
+		stopJobs()
+		checker.Shutdown()
+		grpcHealth.Shutdown()
+		log.Info("Waiting for load balancers to drain", slog.Duration("delay", cfg.Health.DrainDelay))
+		time.Sleep(cfg.Health.DrainDelay)
+
 		tc, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 		defer cancel()
 
@@ -92,6 +280,21 @@ func Run(cfg *config.Config) {
 		} else {
 			log.Info("Server stopped.")
 		}
+
+		stopped := make(chan struct{})
+		go func() {
+			grpcServer.GracefulStop()
+			close(stopped)
+		}()
+		select {
+		case <-stopped:
+		case <-tc.Done():
+			grpcServer.Stop()
+		}
+
+		if err := admin.Shutdown(tc); err != nil {
+			log.Error("Admin server shutdown failed", slog.String("error", err.Error()))
+		}
 	}
 }
 
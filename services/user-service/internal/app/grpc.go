@@ -0,0 +1,63 @@
+package app
+
+import (
+	"context"
+	"log/slog"
+	"runtime/debug"
+	"time"
+
+	userv1 "github.com/SkySock/lode/libs/shared-dto/user/grpc/v1"
+	grpcUser "github.com/SkySock/lode/services/user-service/internal/handler/grpc/v1/user"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	grpchealth "google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+)
+
+func newGRPCServer(log *slog.Logger, userServer *grpcUser.Server) (*grpc.Server, *grpchealth.Server) {
+	s := grpc.NewServer(grpc.ChainUnaryInterceptor(
+		loggingInterceptor(log),
+		recoveryInterceptor(log),
+	))
+
+	healthServer := grpchealth.NewServer()
+	healthServer.SetServingStatus(userv1.UserService_ServiceDesc.ServiceName, healthpb.HealthCheckResponse_SERVING)
+
+	userv1.RegisterUserServiceServer(s, userServer)
+	healthpb.RegisterHealthServer(s, healthServer)
+
+	return s, healthServer
+}
+
+func loggingInterceptor(log *slog.Logger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		start := time.Now()
+		resp, err := handler(ctx, req)
+
+		log.Info("gRPC call",
+			slog.String("method", info.FullMethod),
+			slog.String("code", status.Code(err).String()),
+			slog.Duration("duration", time.Since(start)),
+		)
+
+		return resp, err
+	}
+}
+
+func recoveryInterceptor(log *slog.Logger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp any, err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				log.Error("panic occurred",
+					slog.Any("error", r),
+					slog.String("method", info.FullMethod),
+					slog.String("stack", string(debug.Stack())),
+				)
+				err = status.Error(codes.Internal, "internal error")
+			}
+		}()
+
+		return handler(ctx, req)
+	}
+}
@@ -0,0 +1,39 @@
+package app
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+// runBatchJob calls run every interval until ctx is done. A run repeats while
+// it makes progress, so that a backlog larger than one batch is cleared at
+// once. run returns the number of processed items.
+func runBatchJob(ctx context.Context, log *slog.Logger, interval time.Duration, run func(ctx context.Context) (int, error)) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		total := 0
+		for ctx.Err() == nil {
+			n, err := run(ctx)
+			total += n
+			if err != nil {
+				log.Error("Batch job failed", slog.String("error", err.Error()))
+				break
+			}
+			if n == 0 {
+				break
+			}
+		}
+		if total > 0 {
+			log.Info("Batch job done", slog.Int("count", total))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
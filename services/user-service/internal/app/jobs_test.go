@@ -0,0 +1,39 @@
+package app
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+)
+
+func TestRunBatchJob(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	// A backlog of two full batches is cleared in the first run.
+	results := []int{100, 100, 3, 0}
+	calls := 0
+	run := func(ctx context.Context) (int, error) {
+		calls++
+		if calls == len(results) {
+			cancel()
+		}
+		return results[calls-1], nil
+	}
+
+	done := make(chan struct{})
+	go func() {
+		runBatchJob(ctx, slog.New(slog.NewTextHandler(io.Discard, nil)), time.Hour, run)
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Job did not stop after the context was cancelled")
+	}
+	if calls != len(results) {
+		t.Errorf("Run called %d times, want %d", calls, len(results))
+	}
+}
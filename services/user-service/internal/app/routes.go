@@ -2,11 +2,22 @@ package app
 
 import (
 	"log/slog"
+	"net/netip"
 
+	"github.com/SkySock/lode/libs/utils/http/health"
 	"github.com/SkySock/lode/libs/utils/http/middleware"
 	"github.com/SkySock/lode/services/user-service/docs"
+	"github.com/SkySock/lode/services/user-service/internal/config"
+	"github.com/SkySock/lode/services/user-service/internal/entity"
+	"github.com/SkySock/lode/services/user-service/internal/handler/http/oauth"
+	"github.com/SkySock/lode/services/user-service/internal/handler/http/v1/account"
+	"github.com/SkySock/lode/services/user-service/internal/handler/http/v1/admin"
 	"github.com/SkySock/lode/services/user-service/internal/handler/http/v1/auth"
+	"github.com/SkySock/lode/services/user-service/internal/handler/http/v1/profile"
+	"github.com/SkySock/lode/services/user-service/internal/storage"
 	"github.com/gorilla/mux"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 	httpSwagger "github.com/swaggo/http-swagger"
 )
 
@@ -14,17 +25,174 @@ type controllers struct {
 	SignUp  *auth.SignUp
 	SignIn  *auth.SignIn
 	SignOut *auth.SignOut
+	Refresh *auth.Refresh
+
+	ChangePassword *auth.ChangePassword
+	RestoreAccount *auth.RestoreAccount
+	ConfirmEmail   *auth.ConfirmEmail
+
+	GetProfile     *profile.GetProfile
+	SearchProfiles *profile.SearchProfiles
+	UploadAvatar   *profile.UploadAvatar
+	DeleteAvatar   *profile.DeleteAvatar
+	UpdatePrivacy  *profile.UpdatePrivacy
+
+	Follow        *profile.Follow
+	Unfollow      *profile.Unfollow
+	Block         *profile.Block
+	Unblock       *profile.Unblock
+	Mute          *profile.Mute
+	Unmute        *profile.Unmute
+	ListFollowers *profile.ListFollowers
+	ListFollowing *profile.ListFollowing
+	ListBlocked   *profile.ListBlocked
+	ListMuted     *profile.ListMuted
+
+	ListFollowRequests   *profile.ListFollowRequests
+	ApproveFollowRequest *profile.ApproveFollowRequest
+	DeclineFollowRequest *profile.DeclineFollowRequest
+
+	// Media serves uploaded files when they are stored locally, nil otherwise.
+	Media *storage.Local
+
+	ListSecurityEvents *account.ListSecurityEvents
+	DeleteAccount      *account.DeleteAccount
+	ExportData         *account.ExportData
+	ChangeUsername     *account.ChangeUsername
+	ChangeEmail        *account.ChangeEmail
+
+	Introspect *oauth.Introspect
+	Revoke     *oauth.Revoke
+
+	ListRoles       *admin.ListRoles
+	GetAccountRoles *admin.GetAccountRoles
+	GrantRole       *admin.GrantRole
+	RevokeRole      *admin.RevokeRole
+
+	SearchAccounts     *admin.SearchAccounts
+	GetAccount         *admin.GetAccount
+	LockAccount        *admin.LockAccount
+	UnlockAccount      *admin.UnlockAccount
+	ForcePasswordReset *admin.ForcePasswordReset
+	ForceLogout        *admin.ForceLogout
+	UpdateProfile      *admin.UpdateProfile
+	ListAuthEvents     *admin.ListAuthEvents
+
+	CreateWebhook         *admin.CreateWebhook
+	ListWebhooks          *admin.ListWebhooks
+	DeleteWebhook         *admin.DeleteWebhook
+	ListWebhookDeliveries *admin.ListWebhookDeliveries
+	ReplayWebhookDelivery *admin.ReplayWebhookDelivery
 }
 
-func newRouter(log *slog.Logger, controllers controllers) *mux.Router {
+func newRouter(
+	log *slog.Logger,
+	reg prometheus.Registerer,
+	checker *health.Checker,
+	csrf config.CSRFConfig,
+	trustedProxies []netip.Prefix,
+	verifyToken middleware.AccessTokenVerifier,
+	controllers controllers,
+) *mux.Router {
 	r := mux.NewRouter()
+
+	r.Handle("/healthz", checker.LivenessHandler()).Methods("GET")
+	r.Handle("/readyz", checker.ReadinessHandler()).Methods("GET")
+
+	if controllers.Media != nil {
+		r.PathPrefix(controllers.Media.Prefix()).Handler(controllers.Media).Methods("GET", "HEAD")
+	}
+
+	oauthRouter := r.PathPrefix("/oauth").Subrouter()
+	oauthRouter.Handle("/introspect", controllers.Introspect).Methods("POST")
+	oauthRouter.Handle("/revoke", controllers.Revoke).Methods("POST")
+
 	api := r.PathPrefix("/api").Subrouter()
 	apiV1 := api.PathPrefix("/v1").Subrouter()
 	authV1 := apiV1.PathPrefix("/auth").Subrouter()
 
 	authV1.Handle("/sign-in", controllers.SignIn).Methods("POST")
 	authV1.Handle("/sign-up", controllers.SignUp).Methods("POST")
-	authV1.Handle("/sign-out", controllers.SignOut).Methods("POST")
+	authV1.Handle("/change-password", controllers.ChangePassword).Methods("POST")
+	authV1.Handle("/restore-account", controllers.RestoreAccount).Methods("POST")
+	authV1.Handle("/confirm-email", controllers.ConfirmEmail).Methods("POST")
+
+	// Routes authenticated by the refresh token cookie.
+	cookieAuthV1 := authV1.NewRoute().Subrouter()
+	cookieAuthV1.Use(middleware.CSRF(csrf.TrustedOrigins))
+	cookieAuthV1.Handle("/sign-out", controllers.SignOut).Methods("POST")
+	cookieAuthV1.Handle("/refresh", controllers.Refresh).Methods("POST")
+
+	profileV1 := apiV1.PathPrefix("/profiles").Subrouter()
+
+	// Profiles and lists that a signed in account may see as one of its
+	// profiles.
+	viewedProfileV1 := profileV1.NewRoute().Subrouter()
+	viewedProfileV1.Use(middleware.AuthenticateOptional(verifyToken))
+	viewedProfileV1.Handle("", controllers.SearchProfiles).Methods("GET")
+	viewedProfileV1.Handle("/{profileName}", controllers.GetProfile).Methods("GET")
+	viewedProfileV1.Handle("/{profileId}/followers", controllers.ListFollowers).Methods("GET")
+	viewedProfileV1.Handle("/{profileId}/following", controllers.ListFollowing).Methods("GET")
+
+	ownProfileV1 := profileV1.NewRoute().Subrouter()
+	ownProfileV1.Use(middleware.Authenticate(verifyToken))
+	ownProfileV1.Handle("/{profileId}/avatar", controllers.UploadAvatar).Methods("PUT")
+	ownProfileV1.Handle("/{profileId}/avatar", controllers.DeleteAvatar).Methods("DELETE")
+	ownProfileV1.Handle("/{profileId}/privacy", controllers.UpdatePrivacy).Methods("PATCH")
+	ownProfileV1.Handle("/{profileId}/following/{targetId}", controllers.Follow).Methods("PUT")
+	ownProfileV1.Handle("/{profileId}/following/{targetId}", controllers.Unfollow).Methods("DELETE")
+	ownProfileV1.Handle("/{profileId}/follow-requests", controllers.ListFollowRequests).Methods("GET")
+	ownProfileV1.Handle("/{profileId}/follow-requests/{targetId}", controllers.ApproveFollowRequest).Methods("PUT")
+	ownProfileV1.Handle("/{profileId}/follow-requests/{targetId}", controllers.DeclineFollowRequest).Methods("DELETE")
+	ownProfileV1.Handle("/{profileId}/blocks", controllers.ListBlocked).Methods("GET")
+	ownProfileV1.Handle("/{profileId}/blocks/{targetId}", controllers.Block).Methods("PUT")
+	ownProfileV1.Handle("/{profileId}/blocks/{targetId}", controllers.Unblock).Methods("DELETE")
+	ownProfileV1.Handle("/{profileId}/mutes", controllers.ListMuted).Methods("GET")
+	ownProfileV1.Handle("/{profileId}/mutes/{targetId}", controllers.Mute).Methods("PUT")
+	ownProfileV1.Handle("/{profileId}/mutes/{targetId}", controllers.Unmute).Methods("DELETE")
+
+	accountV1 := apiV1.PathPrefix("/account").Subrouter()
+	accountV1.Use(middleware.Authenticate(verifyToken))
+	accountV1.Handle("", controllers.DeleteAccount).Methods("DELETE")
+	accountV1.Handle("/export", controllers.ExportData).Methods("GET")
+	accountV1.Handle("/username", controllers.ChangeUsername).Methods("PUT")
+	accountV1.Handle("/email", controllers.ChangeEmail).Methods("PUT")
+	accountV1.Handle("/security-events", controllers.ListSecurityEvents).Methods("GET")
+
+	adminV1 := apiV1.PathPrefix("/admin").Subrouter()
+	adminV1.Use(middleware.Authenticate(verifyToken))
+
+	rolesRead := adminV1.NewRoute().Subrouter()
+	rolesRead.Use(middleware.RequirePermission(entity.PermissionRolesRead))
+	rolesRead.Handle("/roles", controllers.ListRoles).Methods("GET")
+	rolesRead.Handle("/accounts/{accountId}/roles", controllers.GetAccountRoles).Methods("GET")
+
+	rolesWrite := adminV1.NewRoute().Subrouter()
+	rolesWrite.Use(middleware.RequirePermission(entity.PermissionRolesWrite))
+	rolesWrite.Handle("/accounts/{accountId}/roles/{role}", controllers.GrantRole).Methods("PUT")
+	rolesWrite.Handle("/accounts/{accountId}/roles/{role}", controllers.RevokeRole).Methods("DELETE")
+
+	accountsRead := adminV1.NewRoute().Subrouter()
+	accountsRead.Use(middleware.RequirePermission(entity.PermissionAccountsRead))
+	accountsRead.Handle("/accounts", controllers.SearchAccounts).Methods("GET")
+	accountsRead.Handle("/accounts/{accountId}", controllers.GetAccount).Methods("GET")
+	accountsRead.Handle("/auth-events", controllers.ListAuthEvents).Methods("GET")
+
+	accountsWrite := adminV1.NewRoute().Subrouter()
+	accountsWrite.Use(middleware.RequirePermission(entity.PermissionAccountsWrite))
+	accountsWrite.Handle("/accounts/{accountId}/lock", controllers.LockAccount).Methods("PUT")
+	accountsWrite.Handle("/accounts/{accountId}/lock", controllers.UnlockAccount).Methods("DELETE")
+	accountsWrite.Handle("/accounts/{accountId}/password-reset", controllers.ForcePasswordReset).Methods("POST")
+	accountsWrite.Handle("/accounts/{accountId}/logout", controllers.ForceLogout).Methods("POST")
+	accountsWrite.Handle("/profiles/{profileId}", controllers.UpdateProfile).Methods("PATCH")
+
+	webhooksManage := adminV1.NewRoute().Subrouter()
+	webhooksManage.Use(middleware.RequirePermission(entity.PermissionWebhooksManage))
+	webhooksManage.Handle("/webhooks", controllers.CreateWebhook).Methods("POST")
+	webhooksManage.Handle("/webhooks", controllers.ListWebhooks).Methods("GET")
+	webhooksManage.Handle("/webhooks/{webhookId}", controllers.DeleteWebhook).Methods("DELETE")
+	webhooksManage.Handle("/webhooks/{webhookId}/deliveries", controllers.ListWebhookDeliveries).Methods("GET")
+	webhooksManage.Handle("/webhooks/{webhookId}/deliveries/{deliveryId}/replay", controllers.ReplayWebhookDelivery).Methods("POST")
 
 	docs.SwaggerInfo.Title = "User Service API"
 	docs.SwaggerInfo.Version = "1.0"
@@ -34,8 +202,19 @@ func newRouter(log *slog.Logger, controllers controllers) *mux.Router {
 
 	apiV1.PathPrefix("/swagger/").Handler(httpSwagger.WrapHandler)
 
+	r.Use(middleware.ClientInfo(trustedProxies))
+	r.Use(middleware.Metrics(reg))
 	r.Use(middleware.Logging(log))
 	r.Use(middleware.Error(log))
+	r.Use(middleware.Deadline(0))
+
+	return r
+}
+
+func newAdminRouter(reg *prometheus.Registry) *mux.Router {
+	r := mux.NewRouter()
+
+	r.Handle("/metrics", promhttp.HandlerFor(reg, promhttp.HandlerOpts{Registry: reg})).Methods("GET")
 
 	return r
 }
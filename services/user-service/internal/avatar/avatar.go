@@ -0,0 +1,97 @@
+package avatar
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	_ "image/gif"
+	"image/jpeg"
+	_ "image/png"
+	"net/http"
+	"slices"
+
+	"golang.org/x/image/draw"
+	_ "golang.org/x/image/webp"
+)
+
+// ContentType is the type of the thumbnails.
+const ContentType = "image/jpeg"
+
+var (
+	ErrUnsupportedFormat = errors.New("unsupported image format")
+	ErrTooManyPixels     = errors.New("image has too many pixels")
+	ErrInvalidImage      = errors.New("invalid image")
+)
+
+// formats are the content types accepted for avatars.
+var formats = []string{"image/jpeg", "image/png", "image/gif", "image/webp"}
+
+// Processor turns uploaded images into square JPEG thumbnails. Decoding and
+// encoding the image again drops its metadata, EXIF included.
+type Processor struct {
+	maxPixels int
+	sizes     []int
+	quality   int
+}
+
+func NewProcessor(maxPixels int, sizes []int, quality int) *Processor {
+	return &Processor{maxPixels: maxPixels, sizes: sizes, quality: quality}
+}
+
+// Sizes are the sizes of the thumbnails, in pixels.
+func (p *Processor) Sizes() []int {
+	return p.sizes
+}
+
+// Thumbnails returns the encoded thumbnails of the image in data by size. The
+// format is sniffed from the data; the declared content type is not trusted.
+func (p *Processor) Thumbnails(data []byte) (map[int][]byte, error) {
+	if !slices.Contains(formats, http.DetectContentType(data)) {
+		return nil, ErrUnsupportedFormat
+	}
+
+	// The header is checked first, so that a small file can not make the
+	// service allocate a huge image.
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidImage, err)
+	}
+	if cfg.Width <= 0 || cfg.Height <= 0 {
+		return nil, ErrInvalidImage
+	}
+	if cfg.Width*cfg.Height > p.maxPixels {
+		return nil, ErrTooManyPixels
+	}
+
+	src, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidImage, err)
+	}
+	square := centerSquare(src.Bounds())
+
+	thumbnails := make(map[int][]byte, len(p.sizes))
+	for _, size := range p.sizes {
+		dst := image.NewRGBA(image.Rect(0, 0, size, size))
+		// JPEG has no transparency; transparent areas turn white.
+		draw.Draw(dst, dst.Bounds(), image.NewUniform(color.White), image.Point{}, draw.Src)
+		draw.CatmullRom.Scale(dst, dst.Bounds(), src, square, draw.Over, nil)
+
+		var buf bytes.Buffer
+		if err := jpeg.Encode(&buf, dst, &jpeg.Options{Quality: p.quality}); err != nil {
+			return nil, fmt.Errorf("avatar: encode failed: %w", err)
+		}
+		thumbnails[size] = buf.Bytes()
+	}
+
+	return thumbnails, nil
+}
+
+// centerSquare is the largest square in the middle of r.
+func centerSquare(r image.Rectangle) image.Rectangle {
+	side := min(r.Dx(), r.Dy())
+	x := r.Min.X + (r.Dx()-side)/2
+	y := r.Min.Y + (r.Dy()-side)/2
+	return image.Rect(x, y, x+side, y+side)
+}
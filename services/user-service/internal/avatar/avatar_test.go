@@ -0,0 +1,78 @@
+package avatar
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"testing"
+)
+
+func TestThumbnails(t *testing.T) {
+	src := image.NewRGBA(image.Rect(0, 0, 300, 200))
+	for x := 0; x < 300; x++ {
+		for y := 0; y < 200; y++ {
+			src.Set(x, y, color.RGBA{R: uint8(x), G: uint8(y), B: 100, A: 255})
+		}
+	}
+	var encoded bytes.Buffer
+	if err := jpeg.Encode(&encoded, src, nil); err != nil {
+		t.Fatal(err)
+	}
+	// An EXIF segment right after the start of image marker.
+	exif := []byte{0xFF, 0xE1, 0x00, 0x10, 'E', 'x', 'i', 'f', 0, 0, 'M', 'M', 0, 42, 0, 0, 0, 8}
+	data := append(append([]byte{}, encoded.Bytes()[:2]...), exif...)
+	data = append(data, encoded.Bytes()[2:]...)
+
+	p := NewProcessor(1_000_000, []int{32, 128}, 85)
+	thumbnails, err := p.Thumbnails(data)
+	if err != nil {
+		t.Fatalf("Thumbnails() error = %v", err)
+	}
+
+	for _, size := range []int{32, 128} {
+		thumbnail, ok := thumbnails[size]
+		if !ok {
+			t.Fatalf("Thumbnail of size %d is missing", size)
+		}
+		if bytes.Contains(thumbnail, []byte("Exif")) {
+			t.Errorf("Thumbnail of size %d keeps EXIF", size)
+		}
+		cfg, format, err := image.DecodeConfig(bytes.NewReader(thumbnail))
+		if err != nil {
+			t.Fatalf("Thumbnail of size %d does not decode: %v", size, err)
+		}
+		if format != "jpeg" || cfg.Width != size || cfg.Height != size {
+			t.Errorf("Thumbnail of size %d is a %dx%d %s", size, cfg.Width, cfg.Height, format)
+		}
+	}
+}
+
+func TestThumbnailsRejects(t *testing.T) {
+	p := NewProcessor(100*100, []int{32}, 85)
+
+	var large bytes.Buffer
+	if err := png.Encode(&large, image.NewGray(image.Rect(0, 0, 101, 100))); err != nil {
+		t.Fatal(err)
+	}
+	pngData := large.Bytes()
+
+	tests := []struct {
+		name string
+		data []byte
+		want error
+	}{
+		{"not an image", []byte("<svg xmlns='http://www.w3.org/2000/svg'></svg>"), ErrUnsupportedFormat},
+		{"truncated", pngData[:20], ErrInvalidImage},
+		{"too many pixels", pngData, ErrTooManyPixels},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := p.Thumbnails(tt.data); !errors.Is(err, tt.want) {
+				t.Errorf("Thumbnails() error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,38 @@
+package broker
+
+import (
+	"context"
+	"time"
+)
+
+// Message is a domain event as it is handed to a broker. Key identifies the
+// entity the event is about.
+type Message struct {
+	ID         string
+	Type       string
+	Key        string
+	OccurredAt time.Time
+	Payload    []byte
+}
+
+type Publisher interface {
+	Publish(ctx context.Context, msg Message) error
+}
+
+type fanout []Publisher
+
+// Fanout publishes every message to each of the publishers in turn. It stops
+// at the first error, so that the message is published again by the retry;
+// publishers must tolerate duplicates.
+func Fanout(publishers ...Publisher) Publisher {
+	return fanout(publishers)
+}
+
+func (f fanout) Publish(ctx context.Context, msg Message) error {
+	for _, p := range f {
+		if err := p.Publish(ctx, msg); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,39 @@
+package broker
+
+import (
+	"context"
+	"sync"
+)
+
+// Memory keeps published messages in memory. It is meant for tests; Err, when
+// set, is returned by Publish instead.
+type Memory struct {
+	mu       sync.Mutex
+	messages []Message
+	Err      error
+}
+
+var _ Publisher = (*Memory)(nil)
+
+func NewMemory() *Memory {
+	return &Memory{}
+}
+
+func (m *Memory) Publish(ctx context.Context, msg Message) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.Err != nil {
+		return m.Err
+	}
+	m.messages = append(m.messages, msg)
+
+	return nil
+}
+
+func (m *Memory) Messages() []Message {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return append([]Message(nil), m.messages...)
+}
@@ -0,0 +1,47 @@
+package broker
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	events "github.com/SkySock/lode/libs/shared-dto/user/events/v1"
+	"github.com/valkey-io/valkey-go"
+)
+
+type streamPublisher struct {
+	client valkey.Client
+	stream string
+	maxLen int64
+}
+
+// NewStreamPublisher appends messages to a Valkey stream. The stream is
+// trimmed to about maxLen entries; zero keeps all of them.
+func NewStreamPublisher(client valkey.Client, stream string, maxLen int64) Publisher {
+	return &streamPublisher{
+		client: client,
+		stream: stream,
+		maxLen: maxLen,
+	}
+}
+
+func (p *streamPublisher) Publish(ctx context.Context, msg Message) error {
+	cmd := p.client.B().Arbitrary("XADD").Keys(p.stream)
+	if p.maxLen > 0 {
+		cmd = cmd.Args("MAXLEN", "~", strconv.FormatInt(p.maxLen, 10))
+	}
+	cmd = cmd.Args("*",
+		events.FieldID, msg.ID,
+		events.FieldType, msg.Type,
+		events.FieldKey, msg.Key,
+		events.FieldOccurredAt, msg.OccurredAt.UTC().Format(time.RFC3339Nano),
+		events.FieldPayload, string(msg.Payload),
+	)
+
+	if err := p.client.Do(ctx, cmd.Build()).Error(); err != nil {
+		return fmt.Errorf("broker: XADD to %s failed: %w", p.stream, err)
+	}
+
+	return nil
+}
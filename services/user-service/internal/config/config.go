@@ -2,23 +2,54 @@ package config
 
 import (
 	"flag"
+	"net/http"
 	"os"
+	"strings"
 	"time"
 
 	"github.com/ilyakaznacheev/cleanenv"
 )
 
 type Config struct {
-	Env    string       `yaml:"env" env-default:"local"`
-	HTTP   HTTPConfig   `yaml:"http"`
-	DB     DBConfig     `yaml:"db"`
-	Valkey ValkeyConfig `yaml:"valkey"`
-	Auth   AuthConfig   `yaml:"auth"`
+	Env      string         `yaml:"env" env-default:"local"`
+	HTTP     HTTPConfig     `yaml:"http"`
+	GRPC     GRPCConfig     `yaml:"grpc"`
+	Admin    AdminConfig    `yaml:"admin"`
+	Health   HealthConfig   `yaml:"health"`
+	DB       DBConfig       `yaml:"db"`
+	Valkey   ValkeyConfig   `yaml:"valkey"`
+	Auth     AuthConfig     `yaml:"auth"`
+	RBAC     RBACConfig     `yaml:"rbac"`
+	Deletion DeletionConfig `yaml:"deletion"`
+	Identity IdentityConfig `yaml:"identity"`
+	Mail     MailConfig     `yaml:"mail"`
+	Outbox   OutboxConfig   `yaml:"outbox"`
+	Webhook  WebhookConfig  `yaml:"webhook"`
+	Avatar   AvatarConfig   `yaml:"avatar"`
+	Storage  StorageConfig  `yaml:"storage"`
 }
 
+// HTTPConfig.TrustedProxies lists the addresses and CIDR ranges whose
+// X-Forwarded-For header is trusted, e.g. those of the api-gateway.
 type HTTPConfig struct {
+	Host           string   `yaml:"host" env-default:"127.0.0.1"`
+	Port           uint16   `yaml:"port" env-default:"8000"`
+	TrustedProxies []string `yaml:"trusted_proxies"`
+}
+
+type GRPCConfig struct {
+	Host string `yaml:"host" env-default:"127.0.0.1"`
+	Port uint16 `yaml:"port" env-default:"9000"`
+}
+
+type AdminConfig struct {
 	Host string `yaml:"host" env-default:"127.0.0.1"`
-	Port uint16 `yaml:"port" env-default:"8000"`
+	Port uint16 `yaml:"port" env-default:"9090"`
+}
+
+type HealthConfig struct {
+	CheckTimeout time.Duration `yaml:"check_timeout" env-default:"2s"`
+	DrainDelay   time.Duration `yaml:"drain_delay" env-default:"5s"`
 }
 
 type DBConfig struct {
@@ -33,6 +64,143 @@ type ValkeyConfig struct {
 type AuthConfig struct {
 	AccessSecretKey string        `yaml:"access_secret"`
 	Lifetime        TokenLifetime `yaml:"lifetime"`
+	Audience        []string      `yaml:"audience"`
+	ClockSkew       time.Duration `yaml:"clock_skew" env-default:"30s"`
+	Cookie          CookieConfig  `yaml:"cookie"`
+	CSRF            CSRFConfig    `yaml:"csrf"`
+	OAuth           OAuthConfig   `yaml:"oauth"`
+}
+
+// CookieConfig scopes the refresh token cookie. Domain may be left empty to
+// bind the cookie to the exact host that set it.
+type CookieConfig struct {
+	Domain   string `yaml:"domain"`
+	Path     string `yaml:"path" env-default:"/api/v1/auth"`
+	SameSite string `yaml:"same_site" env-default:"strict"`
+}
+
+// SameSiteMode maps SameSite to its http value. Unknown values fall back to
+// the strict mode.
+func (c CookieConfig) SameSiteMode() http.SameSite {
+	switch strings.ToLower(c.SameSite) {
+	case "lax":
+		return http.SameSiteLaxMode
+	case "none":
+		return http.SameSiteNoneMode
+	default:
+		return http.SameSiteStrictMode
+	}
+}
+
+// CSRFConfig lists the origins, besides the service's own, that may call
+// cookie-authenticated endpoints.
+type CSRFConfig struct {
+	TrustedOrigins []string `yaml:"trusted_origins"`
+}
+
+// OAuthConfig lists the resource servers allowed to call the token
+// introspection endpoint. They authenticate with HTTP Basic credentials.
+type OAuthConfig struct {
+	Clients []OAuthClient `yaml:"clients"`
+}
+
+// OAuthClient is a resource server. When Audience is set, only tokens issued
+// for that audience are reported as active to it.
+type OAuthClient struct {
+	ID       string `yaml:"id"`
+	Secret   string `yaml:"secret"`
+	Audience string `yaml:"audience"`
+}
+
+// RBACConfig names an existing account that is granted the admin role at
+// startup, to have someone who can grant roles to others.
+type RBACConfig struct {
+	BootstrapAdmin string `yaml:"bootstrap_admin"`
+}
+
+// DeletionConfig sets how long a deleted account can still be restored and
+// how often accounts past that period are purged.
+type DeletionConfig struct {
+	GracePeriod   time.Duration `yaml:"grace_period" env-default:"720h"`
+	PurgeInterval time.Duration `yaml:"purge_interval" env-default:"1h"`
+}
+
+// IdentityConfig governs changes of usernames and emails. An old username
+// stays reserved for its account for UsernameReservation. EmailConfirmURL is
+// the page the confirmation token is sent to, as its token query parameter.
+type IdentityConfig struct {
+	UsernameCooldown    time.Duration `yaml:"username_cooldown" env-default:"720h"`
+	UsernameReservation time.Duration `yaml:"username_reservation" env-default:"2160h"`
+	EmailTokenTTL       time.Duration `yaml:"email_token_ttl" env-default:"24h"`
+	EmailConfirmURL     string        `yaml:"email_confirm_url" env-default:"http://localhost:5173/confirm-email"`
+}
+
+// MailConfig sets the SMTP server mail is sent through. Without SMTPAddr mail
+// is only logged, which is meant for local development.
+type MailConfig struct {
+	From     string `yaml:"from" env-default:"no-reply@lode.local"`
+	SMTPAddr string `yaml:"smtp_addr"`
+	Username string `yaml:"username"`
+	Password string `yaml:"password"`
+}
+
+// OutboxConfig sets how often domain events are relayed from the outbox to
+// the broker and how many at a time. The event stream is trimmed to about
+// StreamMaxLen entries.
+type OutboxConfig struct {
+	RelayInterval time.Duration `yaml:"relay_interval" env-default:"1s"`
+	BatchSize     int           `yaml:"batch_size" env-default:"100"`
+	StreamMaxLen  int64         `yaml:"stream_max_len" env-default:"100000"`
+}
+
+// WebhookConfig sets how often due webhook deliveries are sent and how many
+// at a time. A failed delivery is retried after RetryBase, doubling up to
+// RetryMax, until it has been attempted MaxAttempts times.
+type WebhookConfig struct {
+	DeliveryInterval time.Duration `yaml:"delivery_interval" env-default:"5s"`
+	BatchSize        int           `yaml:"batch_size" env-default:"20"`
+	Timeout          time.Duration `yaml:"timeout" env-default:"10s"`
+	MaxAttempts      int           `yaml:"max_attempts" env-default:"10"`
+	RetryBase        time.Duration `yaml:"retry_base" env-default:"30s"`
+	RetryMax         time.Duration `yaml:"retry_max" env-default:"6h"`
+}
+
+// AvatarConfig limits avatar uploads. MaxPixels bounds the decoded image, so
+// that a small file can not expand into a huge one. An upload is resized to
+// square JPEG thumbnails of Sizes pixels.
+type AvatarConfig struct {
+	MaxBytes  int64 `yaml:"max_bytes" env-default:"5242880"`
+	MaxPixels int   `yaml:"max_pixels" env-default:"40000000"`
+	Sizes     []int `yaml:"sizes" env-default:"64,128,256,512"`
+	Quality   int   `yaml:"quality" env-default:"85"`
+}
+
+// StorageConfig selects where uploaded files are kept: "local" for a directory
+// served by the service itself, meant for local development, or "s3" for an
+// S3-compatible object store.
+type StorageConfig struct {
+	Backend string             `yaml:"backend" env-default:"local"`
+	Local   LocalStorageConfig `yaml:"local"`
+	S3      S3StorageConfig    `yaml:"s3"`
+}
+
+// LocalStorageConfig.BaseURL is the URL the directory is served at.
+type LocalStorageConfig struct {
+	Dir     string `yaml:"dir" env-default:"./data/media"`
+	BaseURL string `yaml:"base_url" env-default:"http://localhost:8000/media"`
+}
+
+// S3StorageConfig addresses buckets by path, which all S3-compatible stores
+// support. PublicURL is the URL the bucket is served at, e.g. that of a CDN;
+// it defaults to the bucket URL.
+type S3StorageConfig struct {
+	Endpoint  string        `yaml:"endpoint"`
+	Region    string        `yaml:"region" env-default:"us-east-1"`
+	Bucket    string        `yaml:"bucket"`
+	AccessKey string        `yaml:"access_key"`
+	SecretKey string        `yaml:"secret_key"`
+	PublicURL string        `yaml:"public_url"`
+	Timeout   time.Duration `yaml:"timeout" env-default:"30s"`
 }
 
 type TokenLifetime struct {
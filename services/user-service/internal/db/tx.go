@@ -0,0 +1,107 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+const (
+	defaultTxAttempts = 3
+	txRetryDelay      = 20 * time.Millisecond
+)
+
+var ErrNoTxSupport = errors.New("query executor can not begin transactions")
+
+type TxOptions struct {
+	IsoLevel   pgx.TxIsoLevel
+	AccessMode pgx.TxAccessMode
+	// MaxAttempts bounds the runs of a transaction that fails with a
+	// serialization failure or a deadlock. Zero means defaultTxAttempts.
+	MaxAttempts int
+}
+
+type txBeginner interface {
+	BeginTx(ctx context.Context, txOptions pgx.TxOptions) (pgx.Tx, error)
+}
+
+// WithTx runs fn in a transaction and commits it if fn returns nil. The
+// transaction is rolled back when fn fails or panics.
+//
+// When qe is a transaction itself, fn runs in a savepoint of it: a failure of
+// fn rolls back only its own changes, and opts are ignored. Otherwise qe must
+// be able to begin a transaction, e.g. be a pool. A top-level transaction that
+// fails with a serialization failure or a deadlock is run again, so fn must
+// not have effects outside of it.
+func WithTx(ctx context.Context, qe QueryExecutor, opts TxOptions, fn func(tx QueryExecutor) error) error {
+	if tx, ok := qe.(pgx.Tx); ok {
+		return runTx(ctx, tx.Begin, fn)
+	}
+
+	beginner, ok := qe.(txBeginner)
+	if !ok {
+		return ErrNoTxSupport
+	}
+	begin := func(ctx context.Context) (pgx.Tx, error) {
+		return beginner.BeginTx(ctx, pgx.TxOptions{IsoLevel: opts.IsoLevel, AccessMode: opts.AccessMode})
+	}
+
+	attempts := opts.MaxAttempts
+	if attempts <= 0 {
+		attempts = defaultTxAttempts
+	}
+
+	var err error
+	for attempt := 1; ; attempt++ {
+		err = runTx(ctx, begin, fn)
+		if err == nil || attempt == attempts || !isRetryable(err) {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(time.Duration(attempt) * txRetryDelay):
+		}
+	}
+}
+
+func runTx(ctx context.Context, begin func(ctx context.Context) (pgx.Tx, error), fn func(tx QueryExecutor) error) (err error) {
+	tx, err := begin(ctx)
+	if err != nil {
+		return fmt.Errorf("Begin tx: %w", err)
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			_ = tx.Rollback(ctx)
+			panic(r)
+		}
+		if err != nil {
+			_ = tx.Rollback(ctx)
+		}
+	}()
+
+	if err := fn(tx); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("tx.Commit: %w", err)
+	}
+
+	return nil
+}
+
+// isRetryable reports whether err is a serialization failure or a deadlock,
+// after which the transaction may succeed when run again.
+func isRetryable(err error) bool {
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) {
+		return false
+	}
+	return pgErr.Code == "40001" || pgErr.Code == "40P01"
+}
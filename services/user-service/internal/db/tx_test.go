@@ -0,0 +1,129 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+type fakeTx struct {
+	pgx.Tx
+	log *[]string
+}
+
+func (tx *fakeTx) Begin(ctx context.Context) (pgx.Tx, error) {
+	*tx.log = append(*tx.log, "savepoint")
+	return &fakeTx{log: tx.log}, nil
+}
+
+func (tx *fakeTx) Commit(ctx context.Context) error {
+	*tx.log = append(*tx.log, "commit")
+	return nil
+}
+
+func (tx *fakeTx) Rollback(ctx context.Context) error {
+	*tx.log = append(*tx.log, "rollback")
+	return nil
+}
+
+type fakePool struct {
+	QueryExecutor
+	log []string
+}
+
+func (p *fakePool) BeginTx(ctx context.Context, opts pgx.TxOptions) (pgx.Tx, error) {
+	p.log = append(p.log, "begin "+string(opts.IsoLevel))
+	return &fakeTx{log: &p.log}, nil
+}
+
+func TestWithTx(t *testing.T) {
+	ctx := context.Background()
+	errFailed := errors.New("failed")
+	serialization := &pgconn.PgError{Code: "40001"}
+
+	tests := []struct {
+		name    string
+		opts    TxOptions
+		fn      func(calls int, tx QueryExecutor) error
+		wantErr error
+		wantLog []string
+	}{
+		{
+			name:    "commit",
+			opts:    TxOptions{IsoLevel: pgx.Serializable},
+			fn:      func(int, QueryExecutor) error { return nil },
+			wantLog: []string{"begin serializable", "commit"},
+		},
+		{
+			name:    "rollback",
+			fn:      func(int, QueryExecutor) error { return errFailed },
+			wantErr: errFailed,
+			wantLog: []string{"begin ", "rollback"},
+		},
+		{
+			name: "retry",
+			fn: func(calls int, _ QueryExecutor) error {
+				if calls == 1 {
+					return serialization
+				}
+				return nil
+			},
+			wantLog: []string{"begin ", "rollback", "begin ", "commit"},
+		},
+		{
+			name:    "attempts",
+			opts:    TxOptions{MaxAttempts: 2},
+			fn:      func(int, QueryExecutor) error { return serialization },
+			wantErr: serialization,
+			wantLog: []string{"begin ", "rollback", "begin ", "rollback"},
+		},
+		{
+			name: "savepoint",
+			fn: func(_ int, tx QueryExecutor) error {
+				err := WithTx(ctx, tx, TxOptions{}, func(QueryExecutor) error { return errFailed })
+				if !errors.Is(err, errFailed) {
+					return err
+				}
+				return nil
+			},
+			wantLog: []string{"begin ", "savepoint", "rollback", "commit"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pool := &fakePool{}
+			calls := 0
+			err := WithTx(ctx, pool, tt.opts, func(tx QueryExecutor) error {
+				calls++
+				return tt.fn(calls, tx)
+			})
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("WithTx() error = %v, want %v", err, tt.wantErr)
+			}
+			if !slices.Equal(pool.log, tt.wantLog) {
+				t.Errorf("WithTx() ran %q, want %q", pool.log, tt.wantLog)
+			}
+		})
+	}
+}
+
+func TestWithTxPanic(t *testing.T) {
+	pool := &fakePool{}
+	defer func() {
+		if recover() == nil {
+			t.Fatal("WithTx() did not pass the panic on")
+		}
+		if last := pool.log[len(pool.log)-1]; last != "rollback" {
+			t.Errorf("WithTx() ran %q, want a rollback last", pool.log)
+		}
+	}()
+
+	_ = WithTx(context.Background(), pool, TxOptions{}, func(QueryExecutor) error {
+		panic("boom")
+	})
+}
@@ -12,6 +12,21 @@ type Account struct {
 	Email        string
 	PasswordHash string
 	CreatedAt    time.Time
+
+	LockedAt              *time.Time
+	LockReason            string
+	PasswordResetRequired bool
+	// DeletedAt is set while the account waits for its purge.
+	DeletedAt         *time.Time
+	UsernameChangedAt *time.Time
+}
+
+func (a *Account) Locked() bool {
+	return a.LockedAt != nil
+}
+
+func (a *Account) Deleted() bool {
+	return a.DeletedAt != nil
 }
 
 type Profile struct {
@@ -22,12 +37,217 @@ type Profile struct {
 	Bio         string
 	Avatar      string
 	CreatedAt   time.Time
+	// IsDefault marks the profile an account signs in with.
+	IsDefault bool
+	// AvatarKey is the blob key prefix of an uploaded avatar, empty when the
+	// avatar was not uploaded. AvatarThumbnails maps the square sizes of the
+	// uploaded avatar, in pixels, to their URLs.
+	AvatarKey        string
+	AvatarThumbnails map[int]string
+	FollowersCount   int
+	FollowingCount   int
+	Privacy          ProfilePrivacy
+}
+
+// Visibilities of profile fields. The owner of a profile sees all of it.
+const (
+	VisibilityEveryone  = "everyone"
+	VisibilityFollowers = "followers"
+	VisibilityNobody    = "nobody"
+)
+
+// ProfilePrivacy sets who sees what of a profile. Only followers see the bio,
+// avatar and follows of a private profile, whatever the visibilities of its
+// fields, and follows of it wait for the approval of its owner. A profile that
+// is not discoverable is left out of search.
+type ProfilePrivacy struct {
+	Private          bool
+	BioVisibility    string
+	AvatarVisibility string
+	Discoverable     bool
+}
+
+// Audience is who views a profile, from the one that sees the least.
+type Audience int
+
+const (
+	AudienceAnonymous Audience = iota
+	AudienceFollower
+	AudienceOwner
+)
+
+// Relation is a follow, block or mute seen from one of its profiles: Profile
+// is the one on the other side.
+type Relation struct {
+	ID        uuid.UUID
+	Profile   *Profile
+	CreatedAt time.Time
+}
+
+// Permissions granted by roles. They are embedded into access tokens.
+const (
+	PermissionRolesRead      = "roles:read"
+	PermissionRolesWrite     = "roles:write"
+	PermissionAccountsRead   = "accounts:read"
+	PermissionAccountsWrite  = "accounts:write"
+	PermissionWebhooksManage = "webhooks:manage"
+)
+
+const RoleAdmin = "admin"
+
+type Role struct {
+	Name        string
+	Description string
+	Permissions []string
+}
+
+type AccountRole struct {
+	Role      string
+	GrantedBy *uuid.UUID
+	GrantedAt time.Time
 }
 
+// Session is stored under its refresh token. ID is a separate identifier that
+// may be exposed, e.g. as the sid claim of access tokens.
 type Session struct {
 	ID        string    `json:"id"`
 	UserID    string    `json:"user_id"`
 	ProfileID string    `json:"profile_id"`
 	ExpiresAt time.Time `json:"expires_at"`
 	Revoked   bool      `json:"revoked"`
+	// AccessTokenID is the jti of the access token issued with the session.
+	AccessTokenID string `json:"access_token_id,omitempty"`
+}
+
+// Actions recorded in the audit log.
+const (
+	AuditRoleGranted         = "role.granted"
+	AuditRoleRevoked         = "role.revoked"
+	AuditAccountLocked       = "account.locked"
+	AuditAccountUnlocked     = "account.unlocked"
+	AuditPasswordResetForced = "account.password_reset_forced"
+	AuditSessionsTerminated  = "account.sessions_terminated"
+	AuditProfileUpdated      = "profile.updated"
+	AuditAccountPurged       = "account.purged"
+	AuditWebhookCreated      = "webhook.created"
+	AuditWebhookDeleted      = "webhook.deleted"
+	AuditWebhookReplayed     = "webhook.delivery_replayed"
+)
+
+type AuditEntry struct {
+	ID        uuid.UUID
+	ActorID   *uuid.UUID
+	Action    string
+	TargetID  *uuid.UUID
+	Details   map[string]any
+	CreatedAt time.Time
+}
+
+// Types of authentication events.
+const (
+	AuthEventSignUp              = "sign_up"
+	AuthEventSignIn              = "sign_in"
+	AuthEventSignOut             = "sign_out"
+	AuthEventPasswordChange      = "password_change"
+	AuthEventPasswordResetForced = "password_reset_forced"
+	AuthEventSessionsTerminated  = "sessions_terminated"
+	AuthEventDeletionRequested   = "deletion_requested"
+	AuthEventDeletionCancelled   = "deletion_cancelled"
+	AuthEventDataExported        = "data_exported"
+	AuthEventUsernameChange      = "username_change"
+	AuthEventEmailChangeRequest  = "email_change_requested"
+	AuthEventEmailChange         = "email_change"
+)
+
+const (
+	AuthOutcomeSuccess = "success"
+	AuthOutcomeFailure = "failure"
+)
+
+// AuthEvent is a security relevant event of an account. AccountID is nil for
+// sign-in attempts with an unknown login.
+type AuthEvent struct {
+	ID        uuid.UUID
+	AccountID *uuid.UUID
+	Type      string
+	IP        string
+	UserAgent string
+	Outcome   string
+	Reason    string
+	CreatedAt time.Time
+}
+
+// Kinds of identity changes.
+const (
+	IdentityUsername = "username"
+	IdentityEmail    = "email"
+)
+
+// IdentityChange records a change of the username or email of an account.
+type IdentityChange struct {
+	ID        uuid.UUID
+	AccountID uuid.UUID
+	Kind      string
+	OldValue  string
+	NewValue  string
+	ChangedAt time.Time
+}
+
+// EmailChange is a pending change of the email, waiting for the new address
+// to be confirmed.
+type EmailChange struct {
+	AccountID uuid.UUID `json:"account_id"`
+	OldEmail  string    `json:"old_email"`
+	NewEmail  string    `json:"new_email"`
+}
+
+// OutboxMessage is a domain event waiting to be published. Payload is the
+// JSON encoding of the event.
+type OutboxMessage struct {
+	ID        uuid.UUID
+	Type      string
+	Key       string
+	Payload   []byte
+	CreatedAt time.Time
+}
+
+// WebhookSubscription is an endpoint of a partner that receives the domain
+// events of EventTypes. Deliveries are signed with Secret.
+type WebhookSubscription struct {
+	ID          uuid.UUID
+	URL         string
+	Secret      string
+	EventTypes  []string
+	Description string
+	CreatedBy   *uuid.UUID
+	CreatedAt   time.Time
+}
+
+// Statuses of webhook deliveries. Deliveries that ran out of attempts are
+// dead; they are only retried when replayed.
+const (
+	WebhookPending   = "pending"
+	WebhookDelivered = "delivered"
+	WebhookDead      = "dead"
+)
+
+type WebhookDelivery struct {
+	ID             uuid.UUID
+	SubscriptionID uuid.UUID
+	EventID        uuid.UUID
+	EventType      string
+	Payload        []byte
+	OccurredAt     time.Time
+	Status         string
+	Attempts       int
+	NextAttemptAt  time.Time
+	LastStatusCode *int
+	LastError      string
+	CreatedAt      time.Time
+	DeliveredAt    *time.Time
+
+	// URL and Secret of the subscription, set on deliveries claimed for
+	// sending.
+	URL    string
+	Secret string
 }
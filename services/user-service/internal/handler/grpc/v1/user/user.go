@@ -0,0 +1,186 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+
+	userv1 "github.com/SkySock/lode/libs/shared-dto/user/grpc/v1"
+	"github.com/SkySock/lode/services/user-service/internal/entity"
+	"github.com/SkySock/lode/services/user-service/internal/usecase"
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const maxProfilesPerRequest = 100
+
+type Server struct {
+	userv1.UnimplementedUserServiceServer
+
+	l        *slog.Logger
+	accounts accountUsecase
+	profiles profileUsecase
+	auth     authUsecase
+}
+
+type accountUsecase interface {
+	GetAccount(ctx context.Context, id uuid.UUID) (*entity.Account, error)
+}
+
+type profileUsecase interface {
+	GetUserProfile(ctx context.Context, id uuid.UUID) (*entity.Profile, error)
+	GetProfileByName(ctx context.Context, name string) (*entity.Profile, error)
+	GetProfilesByIDs(ctx context.Context, ids []uuid.UUID) ([]*entity.Profile, error)
+}
+
+type authUsecase interface {
+	ValidateSession(ctx context.Context, refreshToken string) (*entity.Session, error)
+	IntrospectAccessToken(ctx context.Context, accessToken string) (*usecase.AccessTokenClaims, error)
+}
+
+func NewServer(l *slog.Logger, accounts accountUsecase, profiles profileUsecase, auth authUsecase) *Server {
+	return &Server{l: l, accounts: accounts, profiles: profiles, auth: auth}
+}
+
+var _ userv1.UserServiceServer = (*Server)(nil)
+
+func (s *Server) GetAccount(ctx context.Context, req *userv1.GetAccountRequest) (*userv1.Account, error) {
+	id, err := uuid.Parse(req.GetId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "id must be a UUID")
+	}
+
+	account, err := s.accounts.GetAccount(ctx, id)
+	if err != nil {
+		if errors.Is(err, usecase.ErrAccountNotFound) {
+			return nil, status.Error(codes.NotFound, "account not found")
+		}
+		return nil, s.internal("GetAccount", err)
+	}
+
+	return &userv1.Account{
+		Id:        account.ID.String(),
+		Username:  account.Username,
+		Email:     account.Email,
+		CreatedAt: timestamppb.New(account.CreatedAt),
+	}, nil
+}
+
+// GetProfile returns the whole profile, whatever its privacy. The gRPC API is
+// internal: the services calling it are trusted to keep hidden fields from
+// the people they show profiles to.
+func (s *Server) GetProfile(ctx context.Context, req *userv1.GetProfileRequest) (*userv1.Profile, error) {
+	var (
+		profile *entity.Profile
+		err     error
+	)
+
+	switch lookup := req.GetLookup().(type) {
+	case *userv1.GetProfileRequest_Id:
+		id, parseErr := uuid.Parse(lookup.Id)
+		if parseErr != nil {
+			return nil, status.Error(codes.InvalidArgument, "id must be a UUID")
+		}
+		profile, err = s.profiles.GetUserProfile(ctx, id)
+	case *userv1.GetProfileRequest_ProfileName:
+		profile, err = s.profiles.GetProfileByName(ctx, lookup.ProfileName)
+	default:
+		return nil, status.Error(codes.InvalidArgument, "id or profile_name is required")
+	}
+
+	if err != nil {
+		if errors.Is(err, usecase.ErrProfileNotFound) {
+			return nil, status.Error(codes.NotFound, "profile not found")
+		}
+		return nil, s.internal("GetProfile", err)
+	}
+
+	return toProfile(profile), nil
+}
+
+// GetProfilesByIDs returns whole profiles, like GetProfile.
+func (s *Server) GetProfilesByIDs(ctx context.Context, req *userv1.GetProfilesByIDsRequest) (*userv1.GetProfilesByIDsResponse, error) {
+	if len(req.GetIds()) > maxProfilesPerRequest {
+		return nil, status.Errorf(codes.InvalidArgument, "at most %d ids are allowed", maxProfilesPerRequest)
+	}
+
+	ids := make([]uuid.UUID, 0, len(req.GetIds()))
+	for _, raw := range req.GetIds() {
+		id, err := uuid.Parse(raw)
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid id %q", raw)
+		}
+		ids = append(ids, id)
+	}
+
+	profiles, err := s.profiles.GetProfilesByIDs(ctx, ids)
+	if err != nil {
+		return nil, s.internal("GetProfilesByIDs", err)
+	}
+
+	resp := &userv1.GetProfilesByIDsResponse{Profiles: make([]*userv1.Profile, 0, len(profiles))}
+	for _, profile := range profiles {
+		resp.Profiles = append(resp.Profiles, toProfile(profile))
+	}
+
+	return resp, nil
+}
+
+func (s *Server) ValidateSession(ctx context.Context, req *userv1.ValidateSessionRequest) (*userv1.Session, error) {
+	if req.GetRefreshToken() == "" {
+		return nil, status.Error(codes.InvalidArgument, "refresh_token is required")
+	}
+
+	session, err := s.auth.ValidateSession(ctx, req.GetRefreshToken())
+	if err != nil {
+		if errors.Is(err, usecase.ErrSessionNotFound) ||
+			errors.Is(err, usecase.ErrSessionRevoked) ||
+			errors.Is(err, usecase.ErrSessionExpired) {
+			return nil, status.Error(codes.Unauthenticated, err.Error())
+		}
+		return nil, s.internal("ValidateSession", err)
+	}
+
+	return &userv1.Session{
+		UserId:    session.UserID,
+		ProfileId: session.ProfileID,
+		ExpiresAt: timestamppb.New(session.ExpiresAt),
+	}, nil
+}
+
+func (s *Server) IntrospectToken(ctx context.Context, req *userv1.IntrospectTokenRequest) (*userv1.IntrospectTokenResponse, error) {
+	claims, err := s.auth.IntrospectAccessToken(ctx, req.GetAccessToken())
+	if err != nil {
+		if errors.Is(err, usecase.ErrInvalidToken) {
+			return &userv1.IntrospectTokenResponse{Active: false}, nil
+		}
+		return nil, s.internal("IntrospectToken", err)
+	}
+
+	return &userv1.IntrospectTokenResponse{
+		Active:    true,
+		Subject:   claims.Subject,
+		ProfileId: claims.ProfileID,
+		Issuer:    claims.Issuer,
+		ExpiresAt: timestamppb.New(claims.ExpiresAt),
+	}, nil
+}
+
+func (s *Server) internal(method string, err error) error {
+	s.l.Error("gRPC call failed", slog.String("method", method), slog.String("error", err.Error()))
+	return status.Error(codes.Internal, "internal error")
+}
+
+func toProfile(profile *entity.Profile) *userv1.Profile {
+	return &userv1.Profile{
+		Id:          profile.ID.String(),
+		UserId:      profile.UserID.String(),
+		ProfileName: profile.ProfileName,
+		DisplayName: profile.DisplayName,
+		Bio:         profile.Bio,
+		Avatar:      profile.Avatar,
+		CreatedAt:   timestamppb.New(profile.CreatedAt),
+	}
+}
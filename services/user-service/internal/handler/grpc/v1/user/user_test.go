@@ -0,0 +1,139 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"testing"
+
+	userv1 "github.com/SkySock/lode/libs/shared-dto/user/grpc/v1"
+	"github.com/SkySock/lode/services/user-service/internal/entity"
+	"github.com/SkySock/lode/services/user-service/internal/usecase"
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type fakeUsecases struct {
+	accountErr error
+	sessionErr error
+	tokenErr   error
+}
+
+func (f *fakeUsecases) GetAccount(ctx context.Context, id uuid.UUID) (*entity.Account, error) {
+	if f.accountErr != nil {
+		return nil, f.accountErr
+	}
+	return &entity.Account{ID: id, Username: "alice"}, nil
+}
+
+func (f *fakeUsecases) GetUserProfile(ctx context.Context, id uuid.UUID) (*entity.Profile, error) {
+	return &entity.Profile{ID: id}, nil
+}
+
+func (f *fakeUsecases) GetProfileByName(ctx context.Context, name string) (*entity.Profile, error) {
+	return &entity.Profile{ProfileName: name}, nil
+}
+
+func (f *fakeUsecases) GetProfilesByIDs(ctx context.Context, ids []uuid.UUID) ([]*entity.Profile, error) {
+	profiles := make([]*entity.Profile, 0, len(ids))
+	for _, id := range ids {
+		profiles = append(profiles, &entity.Profile{ID: id})
+	}
+	return profiles, nil
+}
+
+func (f *fakeUsecases) ValidateSession(ctx context.Context, refreshToken string) (*entity.Session, error) {
+	if f.sessionErr != nil {
+		return nil, f.sessionErr
+	}
+	return &entity.Session{UserID: "user"}, nil
+}
+
+func (f *fakeUsecases) IntrospectAccessToken(ctx context.Context, accessToken string) (*usecase.AccessTokenClaims, error) {
+	if f.tokenErr != nil {
+		return nil, f.tokenErr
+	}
+	return &usecase.AccessTokenClaims{Subject: "user"}, nil
+}
+
+func newTestServer(f *fakeUsecases) *Server {
+	return NewServer(slog.New(slog.NewTextHandler(io.Discard, nil)), f, f, f)
+}
+
+func TestErrorCodes(t *testing.T) {
+	ctx := context.Background()
+	id := uuid.NewString()
+
+	testCases := []struct {
+		name       string
+		fake       *fakeUsecases
+		call       func(s *Server) error
+		expectCode codes.Code
+	}{
+		{
+			"Invalid account id",
+			&fakeUsecases{},
+			func(s *Server) error { _, err := s.GetAccount(ctx, &userv1.GetAccountRequest{Id: "42"}); return err },
+			codes.InvalidArgument,
+		},
+		{
+			"Account not found",
+			&fakeUsecases{accountErr: usecase.ErrAccountNotFound},
+			func(s *Server) error { _, err := s.GetAccount(ctx, &userv1.GetAccountRequest{Id: id}); return err },
+			codes.NotFound,
+		},
+		{
+			"Repository failure",
+			&fakeUsecases{accountErr: errors.New("connection reset")},
+			func(s *Server) error { _, err := s.GetAccount(ctx, &userv1.GetAccountRequest{Id: id}); return err },
+			codes.Internal,
+		},
+		{
+			"Profile lookup without key",
+			&fakeUsecases{},
+			func(s *Server) error { _, err := s.GetProfile(ctx, &userv1.GetProfileRequest{}); return err },
+			codes.InvalidArgument,
+		},
+		{
+			"Too many profile ids",
+			&fakeUsecases{},
+			func(s *Server) error {
+				_, err := s.GetProfilesByIDs(ctx, &userv1.GetProfilesByIDsRequest{Ids: make([]string, maxProfilesPerRequest+1)})
+				return err
+			},
+			codes.InvalidArgument,
+		},
+		{
+			"Revoked session",
+			&fakeUsecases{sessionErr: usecase.ErrSessionRevoked},
+			func(s *Server) error {
+				_, err := s.ValidateSession(ctx, &userv1.ValidateSessionRequest{RefreshToken: "token"})
+				return err
+			},
+			codes.Unauthenticated,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.call(newTestServer(tc.fake))
+			if code := status.Code(err); code != tc.expectCode {
+				t.Errorf("Wrong code: got %s, want %s", code, tc.expectCode)
+			}
+		})
+	}
+}
+
+func TestIntrospectInvalidToken(t *testing.T) {
+	s := newTestServer(&fakeUsecases{tokenErr: usecase.ErrInvalidToken})
+
+	resp, err := s.IntrospectToken(context.Background(), &userv1.IntrospectTokenRequest{AccessToken: "garbage"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if resp.GetActive() {
+		t.Error("Invalid token must be reported as inactive")
+	}
+}
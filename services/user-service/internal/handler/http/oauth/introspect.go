@@ -0,0 +1,132 @@
+package oauth
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"slices"
+	"strings"
+
+	v1 "github.com/SkySock/lode/libs/shared-dto/user/http/v1"
+	"github.com/SkySock/lode/services/user-service/internal/entity"
+	"github.com/SkySock/lode/services/user-service/internal/usecase"
+)
+
+type Introspect struct {
+	l       *slog.Logger
+	uc      introspectUsecase
+	clients []Client
+}
+
+type introspectUsecase interface {
+	ValidateSession(ctx context.Context, refreshToken string) (*entity.Session, error)
+	IntrospectAccessToken(ctx context.Context, accessToken string) (*usecase.AccessTokenClaims, error)
+}
+
+func NewIntrospect(l *slog.Logger, uc introspectUsecase, clients []Client) *Introspect {
+	return &Introspect{l, uc, clients}
+}
+
+var _ http.Handler = (*Introspect)(nil)
+
+// ServeHTTP answers whether a token is active. Only registered clients may
+// call it; inactive, unknown and malformed tokens all yield {"active":false}.
+func (h *Introspect) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	client, ok := authenticateClient(r, h.clients)
+	if !ok {
+		writeError(w, http.StatusUnauthorized, "invalid_client", "Client authentication failed")
+		return
+	}
+
+	token := r.PostFormValue("token")
+	if token == "" {
+		writeError(w, http.StatusBadRequest, "invalid_request", "Missing token parameter")
+		return
+	}
+
+	accessToken := func(ctx context.Context, token string) (*v1.IntrospectionResponse, error) {
+		return h.accessToken(ctx, token, client.Audience)
+	}
+
+	lookups := []func(context.Context, string) (*v1.IntrospectionResponse, error){accessToken, h.refreshToken}
+	if r.PostFormValue("token_type_hint") == usecase.TokenTypeRefresh {
+		lookups[0], lookups[1] = lookups[1], lookups[0]
+	}
+
+	resp := &v1.IntrospectionResponse{Active: false}
+	for _, lookup := range lookups {
+		found, err := lookup(r.Context(), token)
+		if err != nil {
+			h.l.Error("Token introspection failed", "error", err)
+			writeError(w, http.StatusInternalServerError, "server_error", "")
+			return
+		}
+		if found != nil {
+			resp = found
+			break
+		}
+	}
+
+	if err := writeJSON(w, http.StatusOK, resp); err != nil {
+		h.l.Error("JSON encoding failed", "error", err)
+	}
+}
+
+func (h *Introspect) accessToken(ctx context.Context, token, audience string) (*v1.IntrospectionResponse, error) {
+	claims, err := h.uc.IntrospectAccessToken(ctx, token)
+	if err != nil {
+		if errors.Is(err, usecase.ErrInvalidToken) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if audience != "" && !slices.Contains(claims.Audience, audience) {
+		return nil, nil
+	}
+
+	resp := &v1.IntrospectionResponse{
+		Active:    true,
+		TokenType: usecase.TokenTypeAccess,
+		Subject:   claims.Subject,
+		ProfileID: claims.ProfileID,
+		SessionID: claims.SessionID,
+		Issuer:    claims.Issuer,
+		Audience:  claims.Audience,
+		Roles:     claims.Roles,
+		Scope:     strings.Join(claims.Permissions, " "),
+		TokenID:   claims.ID,
+		ExpiresAt: claims.ExpiresAt.Unix(),
+	}
+	if claims.IssuedAt.Unix() > 0 {
+		resp.IssuedAt = claims.IssuedAt.Unix()
+	}
+
+	return resp, nil
+}
+
+func (h *Introspect) refreshToken(ctx context.Context, token string) (*v1.IntrospectionResponse, error) {
+	session, err := h.uc.ValidateSession(ctx, token)
+	if err != nil {
+		if errors.Is(err, usecase.ErrSessionNotFound) ||
+			errors.Is(err, usecase.ErrSessionRevoked) ||
+			errors.Is(err, usecase.ErrSessionExpired) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	resp := &v1.IntrospectionResponse{
+		Active:    true,
+		TokenType: usecase.TokenTypeRefresh,
+		Subject:   session.UserID,
+		ProfileID: session.ProfileID,
+		ExpiresAt: session.ExpiresAt.Unix(),
+	}
+	// Older sessions used the refresh token itself as their id.
+	if session.ID != token {
+		resp.SessionID = session.ID
+	}
+
+	return resp, nil
+}
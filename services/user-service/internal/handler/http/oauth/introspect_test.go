@@ -0,0 +1,105 @@
+package oauth
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	v1 "github.com/SkySock/lode/libs/shared-dto/user/http/v1"
+	"github.com/SkySock/lode/services/user-service/internal/entity"
+	"github.com/SkySock/lode/services/user-service/internal/usecase"
+)
+
+type fakeTokens struct{}
+
+func (fakeTokens) ValidateSession(ctx context.Context, refreshToken string) (*entity.Session, error) {
+	switch refreshToken {
+	case "refresh":
+		return &entity.Session{UserID: "user", ProfileID: "profile", ExpiresAt: time.Now().Add(time.Hour)}, nil
+	case "revoked":
+		return nil, usecase.ErrSessionRevoked
+	}
+	return nil, usecase.ErrSessionNotFound
+}
+
+func (fakeTokens) IntrospectAccessToken(ctx context.Context, accessToken string) (*usecase.AccessTokenClaims, error) {
+	if accessToken != "access" {
+		return nil, usecase.ErrInvalidToken
+	}
+	return &usecase.AccessTokenClaims{ID: "jti", Subject: "user", Audience: []string{"lode-api"}, ExpiresAt: time.Now().Add(time.Minute)}, nil
+}
+
+func introspect(h http.Handler, form url.Values, user, password string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/oauth/introspect", strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	if user != "" {
+		req.SetBasicAuth(user, password)
+	}
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+
+	return rec
+}
+
+func TestIntrospect(t *testing.T) {
+	h := NewIntrospect(slog.New(slog.NewTextHandler(io.Discard, nil)), fakeTokens{}, []Client{
+		{ID: "gateway", Secret: "secret"},
+		{ID: "search", Secret: "secret", Audience: "search-api"},
+	})
+
+	t.Run("Client authentication", func(t *testing.T) {
+		rec := introspect(h, url.Values{"token": {"access"}}, "gateway", "wrong")
+		if rec.Code != http.StatusUnauthorized || rec.Header().Get("WWW-Authenticate") == "" {
+			t.Errorf("Expected 401 with a challenge, got %d", rec.Code)
+		}
+	})
+
+	t.Run("Audience of the client", func(t *testing.T) {
+		rec := introspect(h, url.Values{"token": {"access"}}, "search", "secret")
+
+		var resp v1.IntrospectionResponse
+		if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+			t.Fatal(err)
+		}
+		if resp.Active {
+			t.Error("Token issued for another audience should be inactive")
+		}
+	})
+
+	tests := []struct {
+		name      string
+		form      url.Values
+		active    bool
+		tokenType string
+	}{
+		{"Access token", url.Values{"token": {"access"}}, true, usecase.TokenTypeAccess},
+		{"Refresh token", url.Values{"token": {"refresh"}}, true, usecase.TokenTypeRefresh},
+		{"Refresh token with hint", url.Values{"token": {"refresh"}, "token_type_hint": {"refresh_token"}}, true, usecase.TokenTypeRefresh},
+		{"Revoked session", url.Values{"token": {"revoked"}}, false, ""},
+		{"Unknown token", url.Values{"token": {"garbage"}}, false, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := introspect(h, tt.form, "gateway", "secret")
+			if rec.Code != http.StatusOK {
+				t.Fatalf("Wrong status code: got %d, want %d", rec.Code, http.StatusOK)
+			}
+
+			var resp v1.IntrospectionResponse
+			if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+				t.Fatal(err)
+			}
+			if resp.Active != tt.active || resp.TokenType != tt.tokenType {
+				t.Errorf("Wrong response: %+v", resp)
+			}
+		})
+	}
+}
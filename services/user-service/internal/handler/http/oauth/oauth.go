@@ -0,0 +1,55 @@
+// Package oauth implements the token introspection (RFC 7662) and revocation
+// (RFC 7009) endpoints.
+package oauth
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"net/http"
+)
+
+// Client is a resource server allowed to introspect tokens. When Audience is
+// set, access tokens issued for other audiences are inactive for the client.
+type Client struct {
+	ID       string
+	Secret   string
+	Audience string
+}
+
+// errorResponse is an RFC 6749 error response.
+type errorResponse struct {
+	Error       string `json:"error"`
+	Description string `json:"error_description,omitempty"`
+}
+
+func writeError(w http.ResponseWriter, status int, code, description string) {
+	if status == http.StatusUnauthorized {
+		w.Header().Set("WWW-Authenticate", `Basic realm="oauth"`)
+	}
+	writeJSON(w, status, &errorResponse{Error: code, Description: description})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(status)
+
+	return json.NewEncoder(w).Encode(v)
+}
+
+func authenticateClient(r *http.Request, clients []Client) (*Client, bool) {
+	id, secret, ok := r.BasicAuth()
+	if !ok {
+		return nil, false
+	}
+
+	for i := range clients {
+		idMatch := subtle.ConstantTimeCompare([]byte(id), []byte(clients[i].ID))
+		secretMatch := subtle.ConstantTimeCompare([]byte(secret), []byte(clients[i].Secret))
+		if idMatch&secretMatch == 1 {
+			return &clients[i], true
+		}
+	}
+
+	return nil, false
+}
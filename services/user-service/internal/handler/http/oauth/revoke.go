@@ -0,0 +1,42 @@
+package oauth
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+)
+
+type Revoke struct {
+	l  *slog.Logger
+	uc revokeUsecase
+}
+
+type revokeUsecase interface {
+	RevokeToken(ctx context.Context, token, hint string) error
+}
+
+func NewRevoke(l *slog.Logger, uc revokeUsecase) *Revoke {
+	return &Revoke{l, uc}
+}
+
+var _ http.Handler = (*Revoke)(nil)
+
+// ServeHTTP revokes a refresh or access token. Possession of the token is
+// enough to revoke it, and unknown tokens are answered with 200 as well so
+// that the response does not reveal whether a token existed.
+func (h *Revoke) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	token := r.PostFormValue("token")
+	if token == "" {
+		writeError(w, http.StatusBadRequest, "invalid_request", "Missing token parameter")
+		return
+	}
+
+	if err := h.uc.RevokeToken(r.Context(), token, r.PostFormValue("token_type_hint")); err != nil {
+		h.l.Error("Token revocation failed", "error", err)
+		writeError(w, http.StatusServiceUnavailable, "server_error", "")
+		return
+	}
+
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(http.StatusOK)
+}
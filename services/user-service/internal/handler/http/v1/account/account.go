@@ -0,0 +1,38 @@
+package account
+
+import (
+	"net/http"
+
+	v1 "github.com/SkySock/lode/libs/shared-dto/user/http/v1"
+	"github.com/SkySock/lode/libs/utils/http/middleware"
+	"github.com/SkySock/lode/libs/utils/http/response"
+	"github.com/SkySock/lode/services/user-service/internal/entity"
+	"github.com/google/uuid"
+)
+
+// accountIDFromContext returns the id of the authenticated account.
+func accountIDFromContext(w http.ResponseWriter, r *http.Request) (uuid.UUID, bool) {
+	claims, ok := middleware.ClaimsFromContext(r.Context())
+	if !ok {
+		response.WriteProblem(w, http.StatusUnauthorized, "Missing bearer token")
+		return uuid.Nil, false
+	}
+	id, err := uuid.Parse(claims.Subject)
+	if err != nil {
+		response.WriteProblem(w, http.StatusUnauthorized, "Invalid token subject")
+		return uuid.Nil, false
+	}
+	return id, true
+}
+
+func authEventResponse(e *entity.AuthEvent) v1.AuthEventResponse {
+	return v1.AuthEventResponse{
+		ID:        e.ID.String(),
+		Type:      e.Type,
+		IP:        e.IP,
+		UserAgent: e.UserAgent,
+		Outcome:   e.Outcome,
+		Reason:    e.Reason,
+		CreatedAt: e.CreatedAt,
+	}
+}
@@ -0,0 +1,85 @@
+package account
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+
+	v1 "github.com/SkySock/lode/libs/shared-dto/user/http/v1"
+	"github.com/SkySock/lode/libs/utils/http/response"
+	"github.com/SkySock/lode/services/user-service/internal/usecase"
+	"github.com/SkySock/lode/services/user-service/internal/validation"
+	"github.com/google/uuid"
+)
+
+type ChangeEmail struct {
+	l  *slog.Logger
+	uc changeEmailUsecase
+}
+
+type changeEmailUsecase interface {
+	RequestEmailChange(ctx context.Context, accountID uuid.UUID, password, email string) error
+}
+
+func NewChangeEmail(l *slog.Logger, uc changeEmailUsecase) *ChangeEmail {
+	return &ChangeEmail{l, uc}
+}
+
+var _ http.Handler = (*ChangeEmail)(nil)
+
+// ChangeEmail godoc
+// @Summary      Смена email
+// @Description  Отправляет на новый адрес ссылку для подтверждения. Email меняется после подтверждения через /auth/confirm-email, старый адрес получает уведомление
+// @Tags         Account
+// @Accept       json
+// @Security     BearerAuth
+// @Param        request body v1.ChangeEmailRequest true "Новый email и пароль"
+// @Success      202
+// @Failure      400  {object}  response.Problem
+// @Failure      401  {object}  response.Problem
+// @Failure      403  {object}  response.Problem "Incorrect password"
+// @Failure      404  {object}  response.Problem
+// @Failure      409  {object}  response.Problem "Email is taken"
+// @Failure      500  {object}  response.Problem
+// @Router       /account/email [put]
+func (h *ChangeEmail) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	accountID, ok := accountIDFromContext(w, r)
+	if !ok {
+		return
+	}
+
+	data := v1.ChangeEmailRequest{}
+	if err := json.NewDecoder(r.Body).Decode(&data); err != nil {
+		response.WriteProblem(w, http.StatusBadRequest, "Error input data")
+		return
+	}
+
+	data.Normalize()
+
+	if err := validation.ValidateChangeEmailRequest(&data); err != nil {
+		response.WriteProblem(w, http.StatusBadRequest, fmt.Sprintf("Error validating data: %s", err))
+		return
+	}
+
+	if err := h.uc.RequestEmailChange(r.Context(), accountID, data.Password, data.Email); err != nil {
+		switch {
+		case errors.Is(err, usecase.ErrIncorrectPassword):
+			response.WriteProblem(w, http.StatusForbidden, "Incorrect password")
+		case errors.Is(err, usecase.ErrAccountNotFound):
+			response.WriteProblem(w, http.StatusNotFound, "Account not found")
+		case errors.Is(err, usecase.ErrIdentityUnchanged):
+			response.WriteProblem(w, http.StatusBadRequest, "Email is the current one")
+		case errors.Is(err, usecase.ErrEmailTaken):
+			response.WriteProblem(w, http.StatusConflict, "Email is taken")
+		default:
+			h.l.Error("Change email failed", "error", err)
+			response.WriteProblem(w, http.StatusInternalServerError, "Change email failed")
+		}
+		return
+	}
+
+	w.WriteHeader(http.StatusAccepted)
+}
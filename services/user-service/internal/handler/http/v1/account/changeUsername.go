@@ -0,0 +1,89 @@
+package account
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+
+	v1 "github.com/SkySock/lode/libs/shared-dto/user/http/v1"
+	"github.com/SkySock/lode/libs/utils/http/response"
+	"github.com/SkySock/lode/services/user-service/internal/usecase"
+	"github.com/SkySock/lode/services/user-service/internal/validation"
+	"github.com/google/uuid"
+)
+
+type ChangeUsername struct {
+	l  *slog.Logger
+	uc changeUsernameUsecase
+}
+
+type changeUsernameUsecase interface {
+	ChangeUsername(ctx context.Context, accountID uuid.UUID, password, username string) error
+}
+
+func NewChangeUsername(l *slog.Logger, uc changeUsernameUsecase) *ChangeUsername {
+	return &ChangeUsername{l, uc}
+}
+
+var _ http.Handler = (*ChangeUsername)(nil)
+
+// ChangeUsername godoc
+// @Summary      Смена имени пользователя
+// @Description  Меняет имя пользователя. Менять его можно не чаще раза в период ожидания, старое имя какое-то время остается закрепленным за аккаунтом
+// @Tags         Account
+// @Accept       json
+// @Security     BearerAuth
+// @Param        request body v1.ChangeUsernameRequest true "Новое имя и пароль"
+// @Success      204
+// @Failure      400  {object}  response.Problem
+// @Failure      401  {object}  response.Problem
+// @Failure      403  {object}  response.Problem "Incorrect password"
+// @Failure      404  {object}  response.Problem
+// @Failure      409  {object}  response.Problem "Username is taken"
+// @Failure      429  {object}  response.Problem "Username changed too recently"
+// @Failure      500  {object}  response.Problem
+// @Router       /account/username [put]
+func (h *ChangeUsername) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	accountID, ok := accountIDFromContext(w, r)
+	if !ok {
+		return
+	}
+
+	data := v1.ChangeUsernameRequest{}
+	if err := json.NewDecoder(r.Body).Decode(&data); err != nil {
+		response.WriteProblem(w, http.StatusBadRequest, "Error input data")
+		return
+	}
+
+	data.Normalize()
+
+	if err := validation.ValidateChangeUsernameRequest(&data); err != nil {
+		response.WriteProblem(w, http.StatusBadRequest, fmt.Sprintf("Error validating data: %s", err))
+		return
+	}
+
+	if err := h.uc.ChangeUsername(r.Context(), accountID, data.Password, data.Username); err != nil {
+		switch {
+		case errors.Is(err, usecase.ErrIncorrectPassword):
+			response.WriteProblem(w, http.StatusForbidden, "Incorrect password")
+		case errors.Is(err, usecase.ErrAccountNotFound):
+			response.WriteProblem(w, http.StatusNotFound, "Account not found")
+		case errors.Is(err, usecase.ErrIdentityUnchanged):
+			response.WriteProblem(w, http.StatusBadRequest, "Username is the current one")
+		case errors.Is(err, usecase.ErrUsernameTaken):
+			response.WriteProblem(w, http.StatusConflict, "Username is taken")
+		case errors.Is(err, usecase.ErrUsernameCooldown):
+			response.WriteProblem(w, http.StatusTooManyRequests, "Username changed too recently")
+		default:
+			h.l.Error("Change username failed", "error", err)
+			response.WriteProblem(w, http.StatusInternalServerError, "Change username failed")
+		}
+		return
+	}
+
+	h.l.Info("Username changed", "account_id", accountID)
+	w.WriteHeader(http.StatusNoContent)
+}
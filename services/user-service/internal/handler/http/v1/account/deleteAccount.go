@@ -0,0 +1,83 @@
+package account
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+
+	v1 "github.com/SkySock/lode/libs/shared-dto/user/http/v1"
+	"github.com/SkySock/lode/libs/utils/http/response"
+	"github.com/SkySock/lode/services/user-service/internal/usecase"
+	"github.com/SkySock/lode/services/user-service/internal/validation"
+	"github.com/google/uuid"
+)
+
+type DeleteAccount struct {
+	l  *slog.Logger
+	uc deleteAccountUsecase
+}
+
+type deleteAccountUsecase interface {
+	DeleteAccount(ctx context.Context, id uuid.UUID, password string) (time.Time, error)
+}
+
+func NewDeleteAccount(l *slog.Logger, uc deleteAccountUsecase) *DeleteAccount {
+	return &DeleteAccount{l, uc}
+}
+
+var _ http.Handler = (*DeleteAccount)(nil)
+
+// DeleteAccount godoc
+// @Summary      Удаление аккаунта
+// @Description  Помечает аккаунт удаленным и завершает все его сессии. До окончательного удаления аккаунт можно восстановить через /auth/restore-account
+// @Tags         Account
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        request body v1.DeleteAccountRequest true "Подтверждение паролем"
+// @Success      202  {object}  v1.AccountDeletionResponse
+// @Failure      400  {object}  response.Problem
+// @Failure      401  {object}  response.Problem
+// @Failure      403  {object}  response.Problem "Incorrect password"
+// @Failure      404  {object}  response.Problem
+// @Failure      500  {object}  response.Problem
+// @Router       /account [delete]
+func (h *DeleteAccount) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	accountID, ok := accountIDFromContext(w, r)
+	if !ok {
+		return
+	}
+
+	data := v1.DeleteAccountRequest{}
+	if err := json.NewDecoder(r.Body).Decode(&data); err != nil {
+		response.WriteProblem(w, http.StatusBadRequest, "Error input data")
+		return
+	}
+	if err := validation.ValidateDeleteAccountRequest(&data); err != nil {
+		response.WriteProblem(w, http.StatusBadRequest, fmt.Sprintf("Error validating data: %s", err))
+		return
+	}
+
+	purgeAfter, err := h.uc.DeleteAccount(r.Context(), accountID, data.Password)
+	if err != nil {
+		switch {
+		case errors.Is(err, usecase.ErrIncorrectPassword):
+			response.WriteProblem(w, http.StatusForbidden, "Incorrect password")
+		case errors.Is(err, usecase.ErrAccountNotFound):
+			response.WriteProblem(w, http.StatusNotFound, "Account not found")
+		default:
+			h.l.Error("Delete account failed", "error", err)
+			response.WriteProblem(w, http.StatusInternalServerError, "Delete account failed")
+		}
+		return
+	}
+
+	h.l.Info("Account deleted", "account_id", accountID, "purge_after", purgeAfter)
+	if err := response.WriteJSON(w, http.StatusAccepted, v1.AccountDeletionResponse{PurgeAfter: purgeAfter}); err != nil {
+		h.l.Error("JSON encoding failed", "error", err)
+	}
+}
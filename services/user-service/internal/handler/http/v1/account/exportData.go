@@ -0,0 +1,124 @@
+package account
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+
+	v1 "github.com/SkySock/lode/libs/shared-dto/user/http/v1"
+	"github.com/SkySock/lode/libs/utils/http/response"
+	"github.com/SkySock/lode/services/user-service/internal/usecase"
+	"github.com/google/uuid"
+)
+
+type ExportData struct {
+	l  *slog.Logger
+	uc exportDataUsecase
+}
+
+type exportDataUsecase interface {
+	ExportData(ctx context.Context, id uuid.UUID) (*usecase.AccountExport, error)
+}
+
+func NewExportData(l *slog.Logger, uc exportDataUsecase) *ExportData {
+	return &ExportData{l, uc}
+}
+
+var _ http.Handler = (*ExportData)(nil)
+
+// ExportData godoc
+// @Summary      Выгрузка данных
+// @Description  JSON архив с аккаунтом, профилями, сессиями и событиями аутентификации
+// @Tags         Account
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200  {object}  v1.AccountExportResponse
+// @Failure      401  {object}  response.Problem
+// @Failure      404  {object}  response.Problem
+// @Failure      500  {object}  response.Problem
+// @Router       /account/export [get]
+func (h *ExportData) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	accountID, ok := accountIDFromContext(w, r)
+	if !ok {
+		return
+	}
+
+	export, err := h.uc.ExportData(r.Context(), accountID)
+	if err != nil {
+		if errors.Is(err, usecase.ErrAccountNotFound) {
+			response.WriteProblem(w, http.StatusNotFound, "Account not found")
+			return
+		}
+		h.l.Error("Export data failed", "error", err)
+		response.WriteProblem(w, http.StatusInternalServerError, "Export data failed")
+		return
+	}
+
+	account := export.Account
+	resp := v1.AccountExportResponse{
+		ExportedAt: time.Now().UTC(),
+		Account: v1.AccountResponse{
+			ID:                    account.ID.String(),
+			Username:              account.Username,
+			Email:                 account.Email,
+			CreatedAt:             account.CreatedAt,
+			LockedAt:              account.LockedAt,
+			LockReason:            account.LockReason,
+			PasswordResetRequired: account.PasswordResetRequired,
+			DeletedAt:             account.DeletedAt,
+			UsernameChangedAt:     account.UsernameChangedAt,
+		},
+		Profiles:        make([]v1.ProfileResponse, 0, len(export.Profiles)),
+		Sessions:        make([]v1.SessionResponse, 0, len(export.Sessions)),
+		AuthEvents:      make([]v1.AuthEventResponse, 0, len(export.AuthEvents)),
+		IdentityChanges: make([]v1.IdentityChangeResponse, 0, len(export.IdentityChanges)),
+	}
+	for _, p := range export.Profiles {
+		resp.Profiles = append(resp.Profiles, v1.ProfileResponse{
+			ID:               p.ID.String(),
+			UserID:           p.UserID.String(),
+			ProfileName:      p.ProfileName,
+			DisplayName:      p.DisplayName,
+			Bio:              p.Bio,
+			Avatar:           p.Avatar,
+			CreatedAt:        p.CreatedAt,
+			AvatarThumbnails: p.AvatarThumbnails,
+			FollowersCount:   p.FollowersCount,
+			FollowingCount:   p.FollowingCount,
+			IsPrivate:        p.Privacy.Private,
+			Privacy: &v1.ProfilePrivacyResponse{
+				IsPrivate:        p.Privacy.Private,
+				BioVisibility:    p.Privacy.BioVisibility,
+				AvatarVisibility: p.Privacy.AvatarVisibility,
+				Discoverable:     p.Privacy.Discoverable,
+			},
+		})
+	}
+	for _, s := range export.Sessions {
+		resp.Sessions = append(resp.Sessions, v1.SessionResponse{
+			ID:        s.ID,
+			ProfileID: s.ProfileID,
+			ExpiresAt: s.ExpiresAt,
+		})
+	}
+	for _, e := range export.AuthEvents {
+		resp.AuthEvents = append(resp.AuthEvents, authEventResponse(e))
+	}
+	for _, c := range export.IdentityChanges {
+		resp.IdentityChanges = append(resp.IdentityChanges, v1.IdentityChangeResponse{
+			Kind:      c.Kind,
+			OldValue:  c.OldValue,
+			NewValue:  c.NewValue,
+			ChangedAt: c.ChangedAt,
+		})
+	}
+
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="lode-account-%s.json"`, accountID))
+	w.Header().Set("Cache-Control", "no-store")
+	if err := response.WriteJSON(w, http.StatusOK, resp); err != nil {
+		h.l.Error("JSON encoding failed", "error", err)
+	}
+}
@@ -0,0 +1,95 @@
+package account
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	v1 "github.com/SkySock/lode/libs/shared-dto/user/http/v1"
+	"github.com/SkySock/lode/libs/utils/http/response"
+	repo "github.com/SkySock/lode/services/user-service/internal/repository"
+	"github.com/SkySock/lode/services/user-service/internal/usecase"
+	"github.com/google/uuid"
+)
+
+const (
+	defaultPageSize = 20
+	maxPageSize     = 50
+)
+
+type ListSecurityEvents struct {
+	l  *slog.Logger
+	uc listEventsUsecase
+}
+
+type listEventsUsecase interface {
+	ListEvents(ctx context.Context, filter repo.AuthEventFilter, limit int) (*usecase.AuthEventPage, error)
+}
+
+func NewListSecurityEvents(l *slog.Logger, uc listEventsUsecase) *ListSecurityEvents {
+	return &ListSecurityEvents{l, uc}
+}
+
+var _ http.Handler = (*ListSecurityEvents)(nil)
+
+// ListSecurityEvents godoc
+// @Summary      Активность безопасности
+// @Description  Последние входы, выходы и смены пароля аккаунта, начиная с новых
+// @Tags         Account
+// @Produce      json
+// @Security     BearerAuth
+// @Param        cursor query string false "Курсор следующей страницы"
+// @Param        limit  query int    false "Размер страницы" minimum(1) maximum(50) default(20)
+// @Success      200  {object}  v1.AuthEventListResponse
+// @Failure      400  {object}  response.Problem
+// @Failure      401  {object}  response.Problem
+// @Failure      500  {object}  response.Problem
+// @Router       /account/security-events [get]
+func (h *ListSecurityEvents) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	accountID, ok := accountIDFromContext(w, r)
+	if !ok {
+		return
+	}
+
+	filter := repo.AuthEventFilter{AccountID: &accountID}
+	q := r.URL.Query()
+
+	if cursor := q.Get("cursor"); cursor != "" {
+		before, err := uuid.Parse(cursor)
+		if err != nil {
+			response.WriteProblem(w, http.StatusBadRequest, "Invalid cursor")
+			return
+		}
+		filter.Before = &before
+	}
+
+	limit := defaultPageSize
+	if s := q.Get("limit"); s != "" {
+		n, err := strconv.Atoi(s)
+		if err != nil || n < 1 || n > maxPageSize {
+			response.WriteProblem(w, http.StatusBadRequest, "limit must be between 1 and 50")
+			return
+		}
+		limit = n
+	}
+
+	page, err := h.uc.ListEvents(r.Context(), filter, limit)
+	if err != nil {
+		h.l.Error("List security events failed", "error", err)
+		response.WriteProblem(w, http.StatusInternalServerError, "List security events failed")
+		return
+	}
+
+	resp := v1.AuthEventListResponse{
+		Events:     make([]v1.AuthEventResponse, 0, len(page.Events)),
+		NextCursor: page.NextCursor,
+	}
+	for _, event := range page.Events {
+		resp.Events = append(resp.Events, authEventResponse(event))
+	}
+
+	if err := response.WriteJSON(w, http.StatusOK, resp); err != nil {
+		h.l.Error("JSON encoding failed", "error", err)
+	}
+}
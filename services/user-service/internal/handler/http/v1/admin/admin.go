@@ -0,0 +1,107 @@
+package admin
+
+import (
+	"net/http"
+	"strconv"
+
+	v1 "github.com/SkySock/lode/libs/shared-dto/user/http/v1"
+	"github.com/SkySock/lode/libs/utils/http/middleware"
+	"github.com/SkySock/lode/libs/utils/http/response"
+	"github.com/SkySock/lode/services/user-service/internal/entity"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+)
+
+const (
+	defaultPageSize = 20
+	maxPageSize     = 100
+)
+
+// pageSize reads the limit query parameter of list endpoints.
+func pageSize(w http.ResponseWriter, r *http.Request) (int, bool) {
+	s := r.URL.Query().Get("limit")
+	if s == "" {
+		return defaultPageSize, true
+	}
+
+	n, err := strconv.Atoi(s)
+	if err != nil || n < 1 || n > maxPageSize {
+		response.WriteProblem(w, http.StatusBadRequest, "limit must be between 1 and 100")
+		return 0, false
+	}
+	return n, true
+}
+
+func accountIDFromPath(w http.ResponseWriter, r *http.Request) (uuid.UUID, bool) {
+	return uuidFromPath(w, r, "accountId")
+}
+
+func uuidFromPath(w http.ResponseWriter, r *http.Request, name string) (uuid.UUID, bool) {
+	id, err := uuid.Parse(mux.Vars(r)[name])
+	if err != nil {
+		response.WriteProblem(w, http.StatusBadRequest, name+" must be a UUID")
+		return uuid.Nil, false
+	}
+	return id, true
+}
+
+// actorIDFromContext returns the id of the authenticated admin.
+func actorIDFromContext(w http.ResponseWriter, r *http.Request) (uuid.UUID, bool) {
+	claims, ok := middleware.ClaimsFromContext(r.Context())
+	if !ok {
+		response.WriteProblem(w, http.StatusUnauthorized, "Missing bearer token")
+		return uuid.Nil, false
+	}
+	id, err := uuid.Parse(claims.Subject)
+	if err != nil {
+		response.WriteProblem(w, http.StatusUnauthorized, "Invalid token subject")
+		return uuid.Nil, false
+	}
+	return id, true
+}
+
+func accountResponse(a *entity.Account) v1.AccountResponse {
+	return v1.AccountResponse{
+		ID:                    a.ID.String(),
+		Username:              a.Username,
+		Email:                 a.Email,
+		CreatedAt:             a.CreatedAt,
+		LockedAt:              a.LockedAt,
+		LockReason:            a.LockReason,
+		PasswordResetRequired: a.PasswordResetRequired,
+		DeletedAt:             a.DeletedAt,
+		UsernameChangedAt:     a.UsernameChangedAt,
+	}
+}
+
+func profileResponse(p *entity.Profile) v1.ProfileResponse {
+	return v1.ProfileResponse{
+		ID:               p.ID.String(),
+		UserID:           p.UserID.String(),
+		ProfileName:      p.ProfileName,
+		DisplayName:      p.DisplayName,
+		Bio:              p.Bio,
+		Avatar:           p.Avatar,
+		CreatedAt:        p.CreatedAt,
+		AvatarThumbnails: p.AvatarThumbnails,
+		FollowersCount:   p.FollowersCount,
+		FollowingCount:   p.FollowingCount,
+		IsPrivate:        p.Privacy.Private,
+		Privacy: &v1.ProfilePrivacyResponse{
+			IsPrivate:        p.Privacy.Private,
+			BioVisibility:    p.Privacy.BioVisibility,
+			AvatarVisibility: p.Privacy.AvatarVisibility,
+			Discoverable:     p.Privacy.Discoverable,
+		},
+	}
+}
+
+func webhookResponse(s *entity.WebhookSubscription) v1.WebhookResponse {
+	return v1.WebhookResponse{
+		ID:          s.ID.String(),
+		URL:         s.URL,
+		EventTypes:  s.EventTypes,
+		Description: s.Description,
+		CreatedAt:   s.CreatedAt,
+	}
+}
@@ -0,0 +1,83 @@
+package admin
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+
+	v1 "github.com/SkySock/lode/libs/shared-dto/user/http/v1"
+	"github.com/SkySock/lode/libs/utils/http/response"
+	"github.com/SkySock/lode/services/user-service/internal/entity"
+	"github.com/SkySock/lode/services/user-service/internal/usecase"
+	"github.com/SkySock/lode/services/user-service/internal/validation"
+	"github.com/google/uuid"
+)
+
+type CreateWebhook struct {
+	l  *slog.Logger
+	uc createWebhookUsecase
+}
+
+type createWebhookUsecase interface {
+	CreateSubscription(ctx context.Context, actorID uuid.UUID, url, description string, eventTypes []string) (*entity.WebhookSubscription, error)
+}
+
+func NewCreateWebhook(l *slog.Logger, uc createWebhookUsecase) *CreateWebhook {
+	return &CreateWebhook{l, uc}
+}
+
+var _ http.Handler = (*CreateWebhook)(nil)
+
+// CreateWebhook godoc
+// @Summary      Создание вебхука
+// @Description  Подписывает URL партнера на события. Доставки подписываются HMAC-SHA256 секретом, который возвращается только в этом ответе. Требуется разрешение webhooks:manage
+// @Tags         Admin
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        request body v1.CreateWebhookRequest true "Подписка"
+// @Success      201  {object}  v1.WebhookResponse
+// @Failure      400  {object}  response.Problem
+// @Failure      401  {object}  response.Problem
+// @Failure      403  {object}  response.Problem
+// @Failure      500  {object}  response.Problem
+// @Router       /admin/webhooks [post]
+func (h *CreateWebhook) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	actorID, ok := actorIDFromContext(w, r)
+	if !ok {
+		return
+	}
+
+	data := v1.CreateWebhookRequest{}
+	if err := json.NewDecoder(r.Body).Decode(&data); err != nil {
+		response.WriteProblem(w, http.StatusBadRequest, "Error input data")
+		return
+	}
+	if err := validation.ValidateCreateWebhookRequest(&data); err != nil {
+		response.WriteProblem(w, http.StatusBadRequest, fmt.Sprintf("Error validating data: %s", err))
+		return
+	}
+
+	sub, err := h.uc.CreateSubscription(r.Context(), actorID, data.URL, data.Description, data.EventTypes)
+	if err != nil {
+		if errors.Is(err, usecase.ErrUnknownEventType) {
+			response.WriteProblem(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		h.l.Error("Create webhook failed", "error", err)
+		response.WriteProblem(w, http.StatusInternalServerError, "Create webhook failed")
+		return
+	}
+
+	h.l.Info("Webhook created", "webhook_id", sub.ID, "actor_id", actorID)
+
+	resp := webhookResponse(sub)
+	resp.Secret = sub.Secret
+	w.Header().Set("Cache-Control", "no-store")
+	if err := response.WriteJSON(w, http.StatusCreated, resp); err != nil {
+		h.l.Error("JSON encoding failed", "error", err)
+	}
+}
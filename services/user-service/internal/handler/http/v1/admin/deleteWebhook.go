@@ -0,0 +1,64 @@
+package admin
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/SkySock/lode/libs/utils/http/response"
+	"github.com/SkySock/lode/services/user-service/internal/usecase"
+	"github.com/google/uuid"
+)
+
+type DeleteWebhook struct {
+	l  *slog.Logger
+	uc deleteWebhookUsecase
+}
+
+type deleteWebhookUsecase interface {
+	DeleteSubscription(ctx context.Context, actorID, id uuid.UUID) error
+}
+
+func NewDeleteWebhook(l *slog.Logger, uc deleteWebhookUsecase) *DeleteWebhook {
+	return &DeleteWebhook{l, uc}
+}
+
+var _ http.Handler = (*DeleteWebhook)(nil)
+
+// DeleteWebhook godoc
+// @Summary      Удаление вебхука
+// @Description  Удаляет подписку вместе с ее доставками. Требуется разрешение webhooks:manage
+// @Tags         Admin
+// @Security     BearerAuth
+// @Param        webhookId path string true "ID вебхука"
+// @Success      204
+// @Failure      400  {object}  response.Problem
+// @Failure      401  {object}  response.Problem
+// @Failure      403  {object}  response.Problem
+// @Failure      404  {object}  response.Problem
+// @Failure      500  {object}  response.Problem
+// @Router       /admin/webhooks/{webhookId} [delete]
+func (h *DeleteWebhook) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	webhookID, ok := uuidFromPath(w, r, "webhookId")
+	if !ok {
+		return
+	}
+	actorID, ok := actorIDFromContext(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.uc.DeleteSubscription(r.Context(), actorID, webhookID); err != nil {
+		if errors.Is(err, usecase.ErrWebhookNotFound) {
+			response.WriteProblem(w, http.StatusNotFound, "Webhook not found")
+			return
+		}
+		h.l.Error("Delete webhook failed", "error", err)
+		response.WriteProblem(w, http.StatusInternalServerError, "Delete webhook failed")
+		return
+	}
+
+	h.l.Info("Webhook deleted", "webhook_id", webhookID, "actor_id", actorID)
+	w.WriteHeader(http.StatusNoContent)
+}
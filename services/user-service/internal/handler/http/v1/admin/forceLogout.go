@@ -0,0 +1,69 @@
+package admin
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	v1 "github.com/SkySock/lode/libs/shared-dto/user/http/v1"
+	"github.com/SkySock/lode/libs/utils/http/response"
+	"github.com/SkySock/lode/services/user-service/internal/usecase"
+	"github.com/google/uuid"
+)
+
+type ForceLogout struct {
+	l  *slog.Logger
+	uc forceLogoutUsecase
+}
+
+type forceLogoutUsecase interface {
+	ForceLogout(ctx context.Context, actorID, accountID uuid.UUID) (int, error)
+}
+
+func NewForceLogout(l *slog.Logger, uc forceLogoutUsecase) *ForceLogout {
+	return &ForceLogout{l, uc}
+}
+
+var _ http.Handler = (*ForceLogout)(nil)
+
+// ForceLogout godoc
+// @Summary      Завершение всех сессий
+// @Description  Удаляет все сессии аккаунта и делает недействительными его access токены. Требуется разрешение accounts:write
+// @Tags         Admin
+// @Produce      json
+// @Security     BearerAuth
+// @Param        accountId path string true "ID аккаунта"
+// @Success      200  {object}  v1.ForceLogoutResponse
+// @Failure      400  {object}  response.Problem
+// @Failure      401  {object}  response.Problem
+// @Failure      403  {object}  response.Problem
+// @Failure      404  {object}  response.Problem
+// @Failure      500  {object}  response.Problem
+// @Router       /admin/accounts/{accountId}/logout [post]
+func (h *ForceLogout) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	accountID, ok := accountIDFromPath(w, r)
+	if !ok {
+		return
+	}
+	actorID, ok := actorIDFromContext(w, r)
+	if !ok {
+		return
+	}
+
+	n, err := h.uc.ForceLogout(r.Context(), actorID, accountID)
+	if err != nil {
+		if errors.Is(err, usecase.ErrAccountNotFound) {
+			response.WriteProblem(w, http.StatusNotFound, "Account not found")
+			return
+		}
+		h.l.Error("Force logout failed", "error", err)
+		response.WriteProblem(w, http.StatusInternalServerError, "Force logout failed")
+		return
+	}
+
+	h.l.Info("Sessions terminated", "account_id", accountID, "actor_id", actorID, "sessions", n)
+	if err := response.WriteJSON(w, http.StatusOK, v1.ForceLogoutResponse{TerminatedSessions: n}); err != nil {
+		h.l.Error("JSON encoding failed", "error", err)
+	}
+}
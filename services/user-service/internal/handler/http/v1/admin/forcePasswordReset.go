@@ -0,0 +1,64 @@
+package admin
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/SkySock/lode/libs/utils/http/response"
+	"github.com/SkySock/lode/services/user-service/internal/usecase"
+	"github.com/google/uuid"
+)
+
+type ForcePasswordReset struct {
+	l  *slog.Logger
+	uc forcePasswordResetUsecase
+}
+
+type forcePasswordResetUsecase interface {
+	ForcePasswordReset(ctx context.Context, actorID, accountID uuid.UUID) error
+}
+
+func NewForcePasswordReset(l *slog.Logger, uc forcePasswordResetUsecase) *ForcePasswordReset {
+	return &ForcePasswordReset{l, uc}
+}
+
+var _ http.Handler = (*ForcePasswordReset)(nil)
+
+// ForcePasswordReset godoc
+// @Summary      Принудительный сброс пароля
+// @Description  Завершает все сессии аккаунта и не дает войти, пока пароль не будет сменен. Требуется разрешение accounts:write
+// @Tags         Admin
+// @Security     BearerAuth
+// @Param        accountId path string true "ID аккаунта"
+// @Success      204
+// @Failure      400  {object}  response.Problem
+// @Failure      401  {object}  response.Problem
+// @Failure      403  {object}  response.Problem
+// @Failure      404  {object}  response.Problem
+// @Failure      500  {object}  response.Problem
+// @Router       /admin/accounts/{accountId}/password-reset [post]
+func (h *ForcePasswordReset) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	accountID, ok := accountIDFromPath(w, r)
+	if !ok {
+		return
+	}
+	actorID, ok := actorIDFromContext(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.uc.ForcePasswordReset(r.Context(), actorID, accountID); err != nil {
+		if errors.Is(err, usecase.ErrAccountNotFound) {
+			response.WriteProblem(w, http.StatusNotFound, "Account not found")
+			return
+		}
+		h.l.Error("Force password reset failed", "error", err)
+		response.WriteProblem(w, http.StatusInternalServerError, "Force password reset failed")
+		return
+	}
+
+	h.l.Info("Password reset forced", "account_id", accountID, "actor_id", actorID)
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,80 @@
+package admin
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	v1 "github.com/SkySock/lode/libs/shared-dto/user/http/v1"
+	"github.com/SkySock/lode/libs/utils/http/response"
+	"github.com/SkySock/lode/services/user-service/internal/usecase"
+	"github.com/google/uuid"
+)
+
+type GetAccount struct {
+	l  *slog.Logger
+	uc getAccountUsecase
+}
+
+type getAccountUsecase interface {
+	GetAccountDetails(ctx context.Context, accountID uuid.UUID) (*usecase.AccountDetails, error)
+}
+
+func NewGetAccount(l *slog.Logger, uc getAccountUsecase) *GetAccount {
+	return &GetAccount{l, uc}
+}
+
+var _ http.Handler = (*GetAccount)(nil)
+
+// GetAccount godoc
+// @Summary      Аккаунт
+// @Description  Аккаунт с его профилями и активными сессиями. Требуется разрешение accounts:read
+// @Tags         Admin
+// @Produce      json
+// @Security     BearerAuth
+// @Param        accountId path string true "ID аккаунта"
+// @Success      200  {object}  v1.AccountDetailsResponse
+// @Failure      400  {object}  response.Problem
+// @Failure      401  {object}  response.Problem
+// @Failure      403  {object}  response.Problem
+// @Failure      404  {object}  response.Problem
+// @Failure      500  {object}  response.Problem
+// @Router       /admin/accounts/{accountId} [get]
+func (h *GetAccount) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	accountID, ok := accountIDFromPath(w, r)
+	if !ok {
+		return
+	}
+
+	details, err := h.uc.GetAccountDetails(r.Context(), accountID)
+	if err != nil {
+		if errors.Is(err, usecase.ErrAccountNotFound) {
+			response.WriteProblem(w, http.StatusNotFound, "Account not found")
+			return
+		}
+		h.l.Error("Get account failed", "error", err)
+		response.WriteProblem(w, http.StatusInternalServerError, "Get account failed")
+		return
+	}
+
+	resp := v1.AccountDetailsResponse{
+		Account:  accountResponse(details.Account),
+		Profiles: make([]v1.ProfileResponse, 0, len(details.Profiles)),
+		Sessions: make([]v1.SessionResponse, 0, len(details.Sessions)),
+	}
+	for _, profile := range details.Profiles {
+		resp.Profiles = append(resp.Profiles, profileResponse(profile))
+	}
+	for _, session := range details.Sessions {
+		resp.Sessions = append(resp.Sessions, v1.SessionResponse{
+			ID:        session.ID,
+			ProfileID: session.ProfileID,
+			ExpiresAt: session.ExpiresAt,
+		})
+	}
+
+	if err := response.WriteJSON(w, http.StatusOK, resp); err != nil {
+		h.l.Error("JSON encoding failed", "error", err)
+	}
+}
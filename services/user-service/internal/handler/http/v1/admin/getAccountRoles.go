@@ -0,0 +1,75 @@
+package admin
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	v1 "github.com/SkySock/lode/libs/shared-dto/user/http/v1"
+	"github.com/SkySock/lode/libs/utils/http/response"
+	"github.com/SkySock/lode/services/user-service/internal/entity"
+	"github.com/SkySock/lode/services/user-service/internal/usecase"
+	"github.com/google/uuid"
+)
+
+type GetAccountRoles struct {
+	l  *slog.Logger
+	uc getAccountRolesUsecase
+}
+
+type getAccountRolesUsecase interface {
+	GetAccountRoles(ctx context.Context, accountID uuid.UUID) ([]*entity.AccountRole, error)
+}
+
+func NewGetAccountRoles(l *slog.Logger, uc getAccountRolesUsecase) *GetAccountRoles {
+	return &GetAccountRoles{l, uc}
+}
+
+var _ http.Handler = (*GetAccountRoles)(nil)
+
+// GetAccountRoles godoc
+// @Summary      Роли аккаунта
+// @Description  Выданные аккаунту роли. Требуется разрешение roles:read
+// @Tags         Admin
+// @Produce      json
+// @Security     BearerAuth
+// @Param        accountId path string true "ID аккаунта"
+// @Success      200  {array}   v1.AccountRoleResponse
+// @Failure      400  {object}  response.Problem
+// @Failure      401  {object}  response.Problem
+// @Failure      403  {object}  response.Problem
+// @Failure      404  {object}  response.Problem
+// @Failure      500  {object}  response.Problem
+// @Router       /admin/accounts/{accountId}/roles [get]
+func (h *GetAccountRoles) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	accountID, ok := accountIDFromPath(w, r)
+	if !ok {
+		return
+	}
+
+	roles, err := h.uc.GetAccountRoles(r.Context(), accountID)
+	if err != nil {
+		if errors.Is(err, usecase.ErrAccountNotFound) {
+			response.WriteProblem(w, http.StatusNotFound, "Account not found")
+			return
+		}
+		h.l.Error("Get account roles failed", "error", err)
+		response.WriteProblem(w, http.StatusInternalServerError, "Get account roles failed")
+		return
+	}
+
+	resp := make([]v1.AccountRoleResponse, 0, len(roles))
+	for _, role := range roles {
+		item := v1.AccountRoleResponse{Role: role.Role, GrantedAt: role.GrantedAt}
+		if role.GrantedBy != nil {
+			grantedBy := role.GrantedBy.String()
+			item.GrantedBy = &grantedBy
+		}
+		resp = append(resp, item)
+	}
+
+	if err := response.WriteJSON(w, http.StatusOK, resp); err != nil {
+		h.l.Error("JSON encoding failed", "error", err)
+	}
+}
@@ -0,0 +1,71 @@
+package admin
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/SkySock/lode/libs/utils/http/response"
+	"github.com/SkySock/lode/services/user-service/internal/usecase"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+)
+
+type GrantRole struct {
+	l  *slog.Logger
+	uc grantRoleUsecase
+}
+
+type grantRoleUsecase interface {
+	GrantRole(ctx context.Context, actorID, accountID uuid.UUID, role string) error
+}
+
+func NewGrantRole(l *slog.Logger, uc grantRoleUsecase) *GrantRole {
+	return &GrantRole{l, uc}
+}
+
+var _ http.Handler = (*GrantRole)(nil)
+
+// GrantRole godoc
+// @Summary      Выдача роли
+// @Description  Выдает роль аккаунту. Роль попадает в access токен при следующем обновлении. Требуется разрешение roles:write
+// @Tags         Admin
+// @Security     BearerAuth
+// @Param        accountId path string true "ID аккаунта"
+// @Param        role      path string true "Название роли"
+// @Success      204
+// @Failure      400  {object}  response.Problem
+// @Failure      401  {object}  response.Problem
+// @Failure      403  {object}  response.Problem
+// @Failure      404  {object}  response.Problem "Account or role not found"
+// @Failure      500  {object}  response.Problem
+// @Router       /admin/accounts/{accountId}/roles/{role} [put]
+func (h *GrantRole) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	accountID, ok := accountIDFromPath(w, r)
+	if !ok {
+		return
+	}
+
+	actorID, ok := actorIDFromContext(w, r)
+	if !ok {
+		return
+	}
+
+	role := mux.Vars(r)["role"]
+	if err := h.uc.GrantRole(r.Context(), actorID, accountID, role); err != nil {
+		switch {
+		case errors.Is(err, usecase.ErrAccountNotFound):
+			response.WriteProblem(w, http.StatusNotFound, "Account not found")
+		case errors.Is(err, usecase.ErrRoleNotFound):
+			response.WriteProblem(w, http.StatusNotFound, "Role not found")
+		default:
+			h.l.Error("Grant role failed", "error", err)
+			response.WriteProblem(w, http.StatusInternalServerError, "Grant role failed")
+		}
+		return
+	}
+
+	h.l.Info("Role granted", "role", role, "account_id", accountID, "actor_id", actorID)
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,122 @@
+package admin
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"time"
+
+	v1 "github.com/SkySock/lode/libs/shared-dto/user/http/v1"
+	"github.com/SkySock/lode/libs/utils/http/response"
+	repo "github.com/SkySock/lode/services/user-service/internal/repository"
+	"github.com/SkySock/lode/services/user-service/internal/usecase"
+	"github.com/google/uuid"
+)
+
+type ListAuthEvents struct {
+	l  *slog.Logger
+	uc listAuthEventsUsecase
+}
+
+type listAuthEventsUsecase interface {
+	ListEvents(ctx context.Context, filter repo.AuthEventFilter, limit int) (*usecase.AuthEventPage, error)
+}
+
+func NewListAuthEvents(l *slog.Logger, uc listAuthEventsUsecase) *ListAuthEvents {
+	return &ListAuthEvents{l, uc}
+}
+
+var _ http.Handler = (*ListAuthEvents)(nil)
+
+// ListAuthEvents godoc
+// @Summary      События аутентификации
+// @Description  События аутентификации всех аккаунтов с фильтрами, начиная с новых. Требуется разрешение accounts:read
+// @Tags         Admin
+// @Produce      json
+// @Security     BearerAuth
+// @Param        accountId query string false "ID аккаунта"
+// @Param        type      query string false "Тип события" Enums(sign_up, sign_in, sign_out, password_change, password_reset_forced, sessions_terminated)
+// @Param        outcome   query string false "Результат" Enums(success, failure)
+// @Param        ip        query string false "IP адрес клиента"
+// @Param        from      query string false "Не раньше, RFC 3339"
+// @Param        to        query string false "Раньше, RFC 3339"
+// @Param        cursor    query string false "Курсор следующей страницы"
+// @Param        limit     query int    false "Размер страницы" minimum(1) maximum(100) default(20)
+// @Success      200  {object}  v1.AuthEventListResponse
+// @Failure      400  {object}  response.Problem
+// @Failure      401  {object}  response.Problem
+// @Failure      403  {object}  response.Problem
+// @Failure      500  {object}  response.Problem
+// @Router       /admin/auth-events [get]
+func (h *ListAuthEvents) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	filter := repo.AuthEventFilter{
+		Type:    q.Get("type"),
+		Outcome: q.Get("outcome"),
+		IP:      q.Get("ip"),
+	}
+
+	if s := q.Get("accountId"); s != "" {
+		id, err := uuid.Parse(s)
+		if err != nil {
+			response.WriteProblem(w, http.StatusBadRequest, "accountId must be a UUID")
+			return
+		}
+		filter.AccountID = &id
+	}
+	if s := q.Get("cursor"); s != "" {
+		id, err := uuid.Parse(s)
+		if err != nil {
+			response.WriteProblem(w, http.StatusBadRequest, "Invalid cursor")
+			return
+		}
+		filter.Before = &id
+	}
+	for name, t := range map[string]*time.Time{"from": &filter.From, "to": &filter.To} {
+		if s := q.Get(name); s != "" {
+			parsed, err := time.Parse(time.RFC3339, s)
+			if err != nil {
+				response.WriteProblem(w, http.StatusBadRequest, name+" must be an RFC 3339 time")
+				return
+			}
+			*t = parsed.UTC()
+		}
+	}
+
+	limit, ok := pageSize(w, r)
+	if !ok {
+		return
+	}
+
+	page, err := h.uc.ListEvents(r.Context(), filter, limit)
+	if err != nil {
+		h.l.Error("List auth events failed", "error", err)
+		response.WriteProblem(w, http.StatusInternalServerError, "List auth events failed")
+		return
+	}
+
+	resp := v1.AuthEventListResponse{
+		Events:     make([]v1.AuthEventResponse, 0, len(page.Events)),
+		NextCursor: page.NextCursor,
+	}
+	for _, event := range page.Events {
+		item := v1.AuthEventResponse{
+			ID:        event.ID.String(),
+			Type:      event.Type,
+			IP:        event.IP,
+			UserAgent: event.UserAgent,
+			Outcome:   event.Outcome,
+			Reason:    event.Reason,
+			CreatedAt: event.CreatedAt,
+		}
+		if event.AccountID != nil {
+			accountID := event.AccountID.String()
+			item.AccountID = &accountID
+		}
+		resp.Events = append(resp.Events, item)
+	}
+
+	if err := response.WriteJSON(w, http.StatusOK, resp); err != nil {
+		h.l.Error("JSON encoding failed", "error", err)
+	}
+}
@@ -0,0 +1,59 @@
+package admin
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+
+	v1 "github.com/SkySock/lode/libs/shared-dto/user/http/v1"
+	"github.com/SkySock/lode/libs/utils/http/response"
+	"github.com/SkySock/lode/services/user-service/internal/entity"
+)
+
+type ListRoles struct {
+	l  *slog.Logger
+	uc listRolesUsecase
+}
+
+type listRolesUsecase interface {
+	ListRoles(ctx context.Context) ([]*entity.Role, error)
+}
+
+func NewListRoles(l *slog.Logger, uc listRolesUsecase) *ListRoles {
+	return &ListRoles{l, uc}
+}
+
+var _ http.Handler = (*ListRoles)(nil)
+
+// ListRoles godoc
+// @Summary      Список ролей
+// @Description  Роли и входящие в них разрешения. Требуется разрешение roles:read
+// @Tags         Admin
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200  {array}   v1.RoleResponse
+// @Failure      401  {object}  response.Problem
+// @Failure      403  {object}  response.Problem
+// @Failure      500  {object}  response.Problem
+// @Router       /admin/roles [get]
+func (h *ListRoles) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	roles, err := h.uc.ListRoles(r.Context())
+	if err != nil {
+		h.l.Error("List roles failed", "error", err)
+		response.WriteProblem(w, http.StatusInternalServerError, "List roles failed")
+		return
+	}
+
+	resp := make([]v1.RoleResponse, 0, len(roles))
+	for _, role := range roles {
+		resp = append(resp, v1.RoleResponse{
+			Name:        role.Name,
+			Description: role.Description,
+			Permissions: role.Permissions,
+		})
+	}
+
+	if err := response.WriteJSON(w, http.StatusOK, resp); err != nil {
+		h.l.Error("JSON encoding failed", "error", err)
+	}
+}
@@ -0,0 +1,104 @@
+package admin
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	v1 "github.com/SkySock/lode/libs/shared-dto/user/http/v1"
+	"github.com/SkySock/lode/libs/utils/http/response"
+	"github.com/SkySock/lode/services/user-service/internal/entity"
+	"github.com/SkySock/lode/services/user-service/internal/usecase"
+	"github.com/google/uuid"
+)
+
+type ListWebhookDeliveries struct {
+	l  *slog.Logger
+	uc listWebhookDeliveriesUsecase
+}
+
+type listWebhookDeliveriesUsecase interface {
+	ListDeliveries(ctx context.Context, subscriptionID uuid.UUID, status, cursor string, limit int) (*usecase.WebhookDeliveryPage, error)
+}
+
+func NewListWebhookDeliveries(l *slog.Logger, uc listWebhookDeliveriesUsecase) *ListWebhookDeliveries {
+	return &ListWebhookDeliveries{l, uc}
+}
+
+var _ http.Handler = (*ListWebhookDeliveries)(nil)
+
+// ListWebhookDeliveries godoc
+// @Summary      Доставки вебхука
+// @Description  Доставки событий подписке, начиная с новых. Со статусом dead это список доставок, исчерпавших попытки. Требуется разрешение webhooks:manage
+// @Tags         Admin
+// @Produce      json
+// @Security     BearerAuth
+// @Param        webhookId path  string true  "ID вебхука"
+// @Param        status    query string false "Статус доставки" Enums(pending, delivered, dead)
+// @Param        cursor    query string false "Курсор следующей страницы"
+// @Param        limit     query int    false "Размер страницы" minimum(1) maximum(100) default(20)
+// @Success      200  {object}  v1.WebhookDeliveryListResponse
+// @Failure      400  {object}  response.Problem
+// @Failure      401  {object}  response.Problem
+// @Failure      403  {object}  response.Problem
+// @Failure      404  {object}  response.Problem
+// @Failure      500  {object}  response.Problem
+// @Router       /admin/webhooks/{webhookId}/deliveries [get]
+func (h *ListWebhookDeliveries) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	webhookID, ok := uuidFromPath(w, r, "webhookId")
+	if !ok {
+		return
+	}
+
+	q := r.URL.Query()
+	status := q.Get("status")
+	switch status {
+	case "", entity.WebhookPending, entity.WebhookDelivered, entity.WebhookDead:
+	default:
+		response.WriteProblem(w, http.StatusBadRequest, "status must be pending, delivered or dead")
+		return
+	}
+
+	limit, ok := pageSize(w, r)
+	if !ok {
+		return
+	}
+
+	page, err := h.uc.ListDeliveries(r.Context(), webhookID, status, q.Get("cursor"), limit)
+	if err != nil {
+		switch {
+		case errors.Is(err, usecase.ErrInvalidCursor):
+			response.WriteProblem(w, http.StatusBadRequest, "Invalid cursor")
+		case errors.Is(err, usecase.ErrWebhookNotFound):
+			response.WriteProblem(w, http.StatusNotFound, "Webhook not found")
+		default:
+			h.l.Error("List webhook deliveries failed", "error", err)
+			response.WriteProblem(w, http.StatusInternalServerError, "List webhook deliveries failed")
+		}
+		return
+	}
+
+	resp := v1.WebhookDeliveryListResponse{
+		Deliveries: make([]v1.WebhookDeliveryResponse, 0, len(page.Deliveries)),
+		NextCursor: page.NextCursor,
+	}
+	for _, d := range page.Deliveries {
+		resp.Deliveries = append(resp.Deliveries, v1.WebhookDeliveryResponse{
+			ID:             d.ID.String(),
+			EventID:        d.EventID.String(),
+			EventType:      d.EventType,
+			Status:         d.Status,
+			Attempts:       d.Attempts,
+			NextAttemptAt:  d.NextAttemptAt,
+			LastStatusCode: d.LastStatusCode,
+			LastError:      d.LastError,
+			CreatedAt:      d.CreatedAt,
+			DeliveredAt:    d.DeliveredAt,
+		})
+	}
+
+	if err := response.WriteJSON(w, http.StatusOK, resp); err != nil {
+		h.l.Error("JSON encoding failed", "error", err)
+	}
+}
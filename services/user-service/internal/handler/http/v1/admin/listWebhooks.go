@@ -0,0 +1,55 @@
+package admin
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+
+	v1 "github.com/SkySock/lode/libs/shared-dto/user/http/v1"
+	"github.com/SkySock/lode/libs/utils/http/response"
+	"github.com/SkySock/lode/services/user-service/internal/entity"
+)
+
+type ListWebhooks struct {
+	l  *slog.Logger
+	uc listWebhooksUsecase
+}
+
+type listWebhooksUsecase interface {
+	ListSubscriptions(ctx context.Context) ([]*entity.WebhookSubscription, error)
+}
+
+func NewListWebhooks(l *slog.Logger, uc listWebhooksUsecase) *ListWebhooks {
+	return &ListWebhooks{l, uc}
+}
+
+var _ http.Handler = (*ListWebhooks)(nil)
+
+// ListWebhooks godoc
+// @Summary      Список вебхуков
+// @Description  Возвращает все подписки на события без секретов. Требуется разрешение webhooks:manage
+// @Tags         Admin
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200  {object}  v1.WebhookListResponse
+// @Failure      401  {object}  response.Problem
+// @Failure      403  {object}  response.Problem
+// @Failure      500  {object}  response.Problem
+// @Router       /admin/webhooks [get]
+func (h *ListWebhooks) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	subs, err := h.uc.ListSubscriptions(r.Context())
+	if err != nil {
+		h.l.Error("List webhooks failed", "error", err)
+		response.WriteProblem(w, http.StatusInternalServerError, "List webhooks failed")
+		return
+	}
+
+	resp := v1.WebhookListResponse{Webhooks: make([]v1.WebhookResponse, 0, len(subs))}
+	for _, sub := range subs {
+		resp.Webhooks = append(resp.Webhooks, webhookResponse(sub))
+	}
+
+	if err := response.WriteJSON(w, http.StatusOK, resp); err != nil {
+		h.l.Error("JSON encoding failed", "error", err)
+	}
+}
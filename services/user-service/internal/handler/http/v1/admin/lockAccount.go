@@ -0,0 +1,81 @@
+package admin
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+
+	v1 "github.com/SkySock/lode/libs/shared-dto/user/http/v1"
+	"github.com/SkySock/lode/libs/utils/http/response"
+	"github.com/SkySock/lode/services/user-service/internal/usecase"
+	"github.com/SkySock/lode/services/user-service/internal/validation"
+	"github.com/google/uuid"
+)
+
+type LockAccount struct {
+	l  *slog.Logger
+	uc lockAccountUsecase
+}
+
+type lockAccountUsecase interface {
+	LockAccount(ctx context.Context, actorID, accountID uuid.UUID, reason string) error
+}
+
+func NewLockAccount(l *slog.Logger, uc lockAccountUsecase) *LockAccount {
+	return &LockAccount{l, uc}
+}
+
+var _ http.Handler = (*LockAccount)(nil)
+
+// LockAccount godoc
+// @Summary      Блокировка аккаунта
+// @Description  Запрещает вход в аккаунт и завершает все его сессии. Требуется разрешение accounts:write
+// @Tags         Admin
+// @Accept       json
+// @Security     BearerAuth
+// @Param        accountId path string                 true  "ID аккаунта"
+// @Param        request   body v1.LockAccountRequest  false "Причина блокировки"
+// @Success      204
+// @Failure      400  {object}  response.Problem
+// @Failure      401  {object}  response.Problem
+// @Failure      403  {object}  response.Problem
+// @Failure      404  {object}  response.Problem
+// @Failure      500  {object}  response.Problem
+// @Router       /admin/accounts/{accountId}/lock [put]
+func (h *LockAccount) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	accountID, ok := accountIDFromPath(w, r)
+	if !ok {
+		return
+	}
+	actorID, ok := actorIDFromContext(w, r)
+	if !ok {
+		return
+	}
+
+	data := v1.LockAccountRequest{}
+	if err := json.NewDecoder(r.Body).Decode(&data); err != nil && !errors.Is(err, io.EOF) {
+		response.WriteProblem(w, http.StatusBadRequest, "Error input data")
+		return
+	}
+	if err := validation.ValidateLockAccountRequest(&data); err != nil {
+		response.WriteProblem(w, http.StatusBadRequest, fmt.Sprintf("Error validating data: %s", err))
+		return
+	}
+
+	if err := h.uc.LockAccount(r.Context(), actorID, accountID, data.Reason); err != nil {
+		if errors.Is(err, usecase.ErrAccountNotFound) {
+			response.WriteProblem(w, http.StatusNotFound, "Account not found")
+			return
+		}
+		h.l.Error("Lock account failed", "error", err)
+		response.WriteProblem(w, http.StatusInternalServerError, "Lock account failed")
+		return
+	}
+
+	h.l.Info("Account locked", "account_id", accountID, "actor_id", actorID)
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,69 @@
+package admin
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/SkySock/lode/libs/utils/http/response"
+	"github.com/SkySock/lode/services/user-service/internal/usecase"
+	"github.com/google/uuid"
+)
+
+type ReplayWebhookDelivery struct {
+	l  *slog.Logger
+	uc replayWebhookDeliveryUsecase
+}
+
+type replayWebhookDeliveryUsecase interface {
+	ReplayDelivery(ctx context.Context, actorID, subscriptionID, deliveryID uuid.UUID) error
+}
+
+func NewReplayWebhookDelivery(l *slog.Logger, uc replayWebhookDeliveryUsecase) *ReplayWebhookDelivery {
+	return &ReplayWebhookDelivery{l, uc}
+}
+
+var _ http.Handler = (*ReplayWebhookDelivery)(nil)
+
+// ReplayWebhookDelivery godoc
+// @Summary      Повтор доставки вебхука
+// @Description  Ставит доставку в очередь заново с полным набором попыток, в том числе доставленную или исчерпавшую попытки. Требуется разрешение webhooks:manage
+// @Tags         Admin
+// @Security     BearerAuth
+// @Param        webhookId  path string true "ID вебхука"
+// @Param        deliveryId path string true "ID доставки"
+// @Success      202
+// @Failure      400  {object}  response.Problem
+// @Failure      401  {object}  response.Problem
+// @Failure      403  {object}  response.Problem
+// @Failure      404  {object}  response.Problem
+// @Failure      500  {object}  response.Problem
+// @Router       /admin/webhooks/{webhookId}/deliveries/{deliveryId}/replay [post]
+func (h *ReplayWebhookDelivery) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	webhookID, ok := uuidFromPath(w, r, "webhookId")
+	if !ok {
+		return
+	}
+	deliveryID, ok := uuidFromPath(w, r, "deliveryId")
+	if !ok {
+		return
+	}
+	actorID, ok := actorIDFromContext(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.uc.ReplayDelivery(r.Context(), actorID, webhookID, deliveryID); err != nil {
+		if errors.Is(err, usecase.ErrDeliveryNotFound) {
+			response.WriteProblem(w, http.StatusNotFound, "Delivery not found")
+			return
+		}
+		h.l.Error("Replay webhook delivery failed", "error", err)
+		response.WriteProblem(w, http.StatusInternalServerError, "Replay webhook delivery failed")
+		return
+	}
+
+	h.l.Info("Webhook delivery replayed", "delivery_id", deliveryID, "actor_id", actorID)
+	w.WriteHeader(http.StatusAccepted)
+}
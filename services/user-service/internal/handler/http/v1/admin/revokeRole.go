@@ -0,0 +1,71 @@
+package admin
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/SkySock/lode/libs/utils/http/response"
+	"github.com/SkySock/lode/services/user-service/internal/usecase"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+)
+
+type RevokeRole struct {
+	l  *slog.Logger
+	uc revokeRoleUsecase
+}
+
+type revokeRoleUsecase interface {
+	RevokeRole(ctx context.Context, actorID, accountID uuid.UUID, role string) error
+}
+
+func NewRevokeRole(l *slog.Logger, uc revokeRoleUsecase) *RevokeRole {
+	return &RevokeRole{l, uc}
+}
+
+var _ http.Handler = (*RevokeRole)(nil)
+
+// RevokeRole godoc
+// @Summary      Отзыв роли
+// @Description  Отзывает роль у аккаунта и делает недействительными его access токены. Требуется разрешение roles:write
+// @Tags         Admin
+// @Security     BearerAuth
+// @Param        accountId path string true "ID аккаунта"
+// @Param        role      path string true "Название роли"
+// @Success      204
+// @Failure      400  {object}  response.Problem
+// @Failure      401  {object}  response.Problem
+// @Failure      403  {object}  response.Problem
+// @Failure      404  {object}  response.Problem "Role not granted"
+// @Failure      409  {object}  response.Problem "Last admin"
+// @Failure      500  {object}  response.Problem
+// @Router       /admin/accounts/{accountId}/roles/{role} [delete]
+func (h *RevokeRole) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	accountID, ok := accountIDFromPath(w, r)
+	if !ok {
+		return
+	}
+	actorID, ok := actorIDFromContext(w, r)
+	if !ok {
+		return
+	}
+
+	role := mux.Vars(r)["role"]
+	if err := h.uc.RevokeRole(r.Context(), actorID, accountID, role); err != nil {
+		switch {
+		case errors.Is(err, usecase.ErrRoleNotGranted):
+			response.WriteProblem(w, http.StatusNotFound, "Role not granted")
+		case errors.Is(err, usecase.ErrLastAdmin):
+			response.WriteProblem(w, http.StatusConflict, "The last admin cannot lose the admin role")
+		default:
+			h.l.Error("Revoke role failed", "error", err)
+			response.WriteProblem(w, http.StatusInternalServerError, "Revoke role failed")
+		}
+		return
+	}
+
+	h.l.Info("Role revoked", "role", role, "account_id", accountID, "actor_id", actorID)
+	w.WriteHeader(http.StatusNoContent)
+}
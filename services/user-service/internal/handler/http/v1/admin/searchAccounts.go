@@ -0,0 +1,74 @@
+package admin
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	v1 "github.com/SkySock/lode/libs/shared-dto/user/http/v1"
+	"github.com/SkySock/lode/libs/utils/http/response"
+	"github.com/SkySock/lode/services/user-service/internal/usecase"
+)
+
+type SearchAccounts struct {
+	l  *slog.Logger
+	uc searchAccountsUsecase
+}
+
+type searchAccountsUsecase interface {
+	SearchAccounts(ctx context.Context, query, cursor string, limit int) (*usecase.AccountPage, error)
+}
+
+func NewSearchAccounts(l *slog.Logger, uc searchAccountsUsecase) *SearchAccounts {
+	return &SearchAccounts{l, uc}
+}
+
+var _ http.Handler = (*SearchAccounts)(nil)
+
+// SearchAccounts godoc
+// @Summary      Поиск аккаунтов
+// @Description  Ищет аккаунты по началу имени пользователя или email. Требуется разрешение accounts:read
+// @Tags         Admin
+// @Produce      json
+// @Security     BearerAuth
+// @Param        q      query string false "Начало имени пользователя или email"
+// @Param        cursor query string false "Курсор следующей страницы"
+// @Param        limit  query int    false "Размер страницы" minimum(1) maximum(100) default(20)
+// @Success      200  {object}  v1.AccountListResponse
+// @Failure      400  {object}  response.Problem
+// @Failure      401  {object}  response.Problem
+// @Failure      403  {object}  response.Problem
+// @Failure      500  {object}  response.Problem
+// @Router       /admin/accounts [get]
+func (h *SearchAccounts) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+
+	limit, ok := pageSize(w, r)
+	if !ok {
+		return
+	}
+
+	page, err := h.uc.SearchAccounts(r.Context(), q.Get("q"), q.Get("cursor"), limit)
+	if err != nil {
+		if errors.Is(err, usecase.ErrInvalidCursor) {
+			response.WriteProblem(w, http.StatusBadRequest, "Invalid cursor")
+			return
+		}
+		h.l.Error("Search accounts failed", "error", err)
+		response.WriteProblem(w, http.StatusInternalServerError, "Search accounts failed")
+		return
+	}
+
+	resp := v1.AccountListResponse{
+		Accounts:   make([]v1.AccountResponse, 0, len(page.Accounts)),
+		NextCursor: page.NextCursor,
+	}
+	for _, account := range page.Accounts {
+		resp.Accounts = append(resp.Accounts, accountResponse(account))
+	}
+
+	if err := response.WriteJSON(w, http.StatusOK, resp); err != nil {
+		h.l.Error("JSON encoding failed", "error", err)
+	}
+}
@@ -0,0 +1,64 @@
+package admin
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/SkySock/lode/libs/utils/http/response"
+	"github.com/SkySock/lode/services/user-service/internal/usecase"
+	"github.com/google/uuid"
+)
+
+type UnlockAccount struct {
+	l  *slog.Logger
+	uc unlockAccountUsecase
+}
+
+type unlockAccountUsecase interface {
+	UnlockAccount(ctx context.Context, actorID, accountID uuid.UUID) error
+}
+
+func NewUnlockAccount(l *slog.Logger, uc unlockAccountUsecase) *UnlockAccount {
+	return &UnlockAccount{l, uc}
+}
+
+var _ http.Handler = (*UnlockAccount)(nil)
+
+// UnlockAccount godoc
+// @Summary      Разблокировка аккаунта
+// @Description  Снимает блокировку с аккаунта. Требуется разрешение accounts:write
+// @Tags         Admin
+// @Security     BearerAuth
+// @Param        accountId path string true "ID аккаунта"
+// @Success      204
+// @Failure      400  {object}  response.Problem
+// @Failure      401  {object}  response.Problem
+// @Failure      403  {object}  response.Problem
+// @Failure      404  {object}  response.Problem
+// @Failure      500  {object}  response.Problem
+// @Router       /admin/accounts/{accountId}/lock [delete]
+func (h *UnlockAccount) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	accountID, ok := accountIDFromPath(w, r)
+	if !ok {
+		return
+	}
+	actorID, ok := actorIDFromContext(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.uc.UnlockAccount(r.Context(), actorID, accountID); err != nil {
+		if errors.Is(err, usecase.ErrAccountNotFound) {
+			response.WriteProblem(w, http.StatusNotFound, "Account not found")
+			return
+		}
+		h.l.Error("Unlock account failed", "error", err)
+		response.WriteProblem(w, http.StatusInternalServerError, "Unlock account failed")
+		return
+	}
+
+	h.l.Info("Account unlocked", "account_id", accountID, "actor_id", actorID)
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,91 @@
+package admin
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+
+	v1 "github.com/SkySock/lode/libs/shared-dto/user/http/v1"
+	"github.com/SkySock/lode/libs/utils/http/response"
+	"github.com/SkySock/lode/services/user-service/internal/entity"
+	"github.com/SkySock/lode/services/user-service/internal/usecase"
+	"github.com/SkySock/lode/services/user-service/internal/validation"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+)
+
+type UpdateProfile struct {
+	l  *slog.Logger
+	uc updateProfileUsecase
+}
+
+type updateProfileUsecase interface {
+	UpdateProfile(ctx context.Context, actorID, profileID uuid.UUID, update usecase.ProfileUpdate) (*entity.Profile, error)
+}
+
+func NewUpdateProfile(l *slog.Logger, uc updateProfileUsecase) *UpdateProfile {
+	return &UpdateProfile{l, uc}
+}
+
+var _ http.Handler = (*UpdateProfile)(nil)
+
+// UpdateProfile godoc
+// @Summary      Редактирование профиля
+// @Description  Меняет переданные поля профиля. Требуется разрешение accounts:write
+// @Tags         Admin
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        profileId path string                   true "ID профиля"
+// @Param        request   body v1.UpdateProfileRequest  true "Новые значения полей"
+// @Success      200  {object}  v1.ProfileResponse
+// @Failure      400  {object}  response.Problem
+// @Failure      401  {object}  response.Problem
+// @Failure      403  {object}  response.Problem
+// @Failure      404  {object}  response.Problem
+// @Failure      500  {object}  response.Problem
+// @Router       /admin/profiles/{profileId} [patch]
+func (h *UpdateProfile) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	profileID, err := uuid.Parse(mux.Vars(r)["profileId"])
+	if err != nil {
+		response.WriteProblem(w, http.StatusBadRequest, "profileId must be a UUID")
+		return
+	}
+	actorID, ok := actorIDFromContext(w, r)
+	if !ok {
+		return
+	}
+
+	data := v1.UpdateProfileRequest{}
+	if err := json.NewDecoder(r.Body).Decode(&data); err != nil {
+		response.WriteProblem(w, http.StatusBadRequest, "Error input data")
+		return
+	}
+	if err := validation.ValidateUpdateProfileRequest(&data); err != nil {
+		response.WriteProblem(w, http.StatusBadRequest, fmt.Sprintf("Error validating data: %s", err))
+		return
+	}
+
+	profile, err := h.uc.UpdateProfile(r.Context(), actorID, profileID, usecase.ProfileUpdate{
+		DisplayName: data.DisplayName,
+		Bio:         data.Bio,
+		Avatar:      data.Avatar,
+	})
+	if err != nil {
+		if errors.Is(err, usecase.ErrProfileNotFound) {
+			response.WriteProblem(w, http.StatusNotFound, "Profile not found")
+			return
+		}
+		h.l.Error("Update profile failed", "error", err)
+		response.WriteProblem(w, http.StatusInternalServerError, "Update profile failed")
+		return
+	}
+
+	h.l.Info("Profile updated", "profile_id", profileID, "actor_id", actorID)
+	if err := response.WriteJSON(w, http.StatusOK, profileResponse(profile)); err != nil {
+		h.l.Error("JSON encoding failed", "error", err)
+	}
+}
@@ -0,0 +1,77 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+
+	v1 "github.com/SkySock/lode/libs/shared-dto/user/http/v1"
+	"github.com/SkySock/lode/libs/utils/http/response"
+	"github.com/SkySock/lode/services/user-service/internal/usecase"
+	"github.com/SkySock/lode/services/user-service/internal/validation"
+)
+
+type ChangePassword struct {
+	l  *slog.Logger
+	uc changePasswordUsecase
+}
+
+type changePasswordUsecase interface {
+	ChangePassword(ctx context.Context, login, password, newPassword string) error
+}
+
+func NewChangePassword(l *slog.Logger, uc changePasswordUsecase) *ChangePassword {
+	return &ChangePassword{l, uc}
+}
+
+var _ http.Handler = (*ChangePassword)(nil)
+
+// ChangePassword godoc
+// @Summary      Смена пароля
+// @Description  Меняет пароль и завершает все сессии аккаунта. Так же завершается сброс пароля, назначенный администратором
+// @Tags         Auth
+// @Accept       json
+// @Param        request body v1.ChangePasswordRequest true "Текущие данные входа и новый пароль"
+// @Success      204
+// @Failure      400  {object}  response.Problem
+// @Failure      401  {object}  response.Problem
+// @Failure      403  {object}  response.Problem "Account locked or deleted"
+// @Failure      500  {object}  response.Problem
+// @Router       /auth/change-password [post]
+func (h *ChangePassword) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	data := v1.ChangePasswordRequest{}
+
+	if err := json.NewDecoder(r.Body).Decode(&data); err != nil {
+		response.WriteProblem(w, http.StatusBadRequest, "Error input data")
+		return
+	}
+
+	data.Normalize()
+
+	if err := validation.ValidateChangePasswordRequest(&data); err != nil {
+		response.WriteProblem(w, http.StatusBadRequest, fmt.Sprintf("Error validating data: %s", err))
+		return
+	}
+
+	if err := h.uc.ChangePassword(r.Context(), data.Login, data.Password, data.NewPassword); err != nil {
+		switch {
+		case errors.Is(err, usecase.ErrIncorrectPassword),
+			errors.Is(err, usecase.ErrEmailNotFound),
+			errors.Is(err, usecase.ErrUsernameNotFound):
+			response.WriteProblem(w, http.StatusUnauthorized, "Invalid credentials")
+		case errors.Is(err, usecase.ErrAccountLocked):
+			response.WriteProblem(w, http.StatusForbidden, "Account is locked")
+		case errors.Is(err, usecase.ErrAccountDeleted):
+			response.WriteProblem(w, http.StatusForbidden, "Account is scheduled for deletion")
+		default:
+			h.l.Error("Change password failed", "error", err)
+			response.WriteProblem(w, http.StatusInternalServerError, "Change password failed")
+		}
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
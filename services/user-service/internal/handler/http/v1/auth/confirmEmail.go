@@ -0,0 +1,70 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+
+	v1 "github.com/SkySock/lode/libs/shared-dto/user/http/v1"
+	"github.com/SkySock/lode/libs/utils/http/response"
+	"github.com/SkySock/lode/services/user-service/internal/usecase"
+	"github.com/SkySock/lode/services/user-service/internal/validation"
+)
+
+type ConfirmEmail struct {
+	l  *slog.Logger
+	uc confirmEmailUsecase
+}
+
+type confirmEmailUsecase interface {
+	ConfirmEmailChange(ctx context.Context, token string) error
+}
+
+func NewConfirmEmail(l *slog.Logger, uc confirmEmailUsecase) *ConfirmEmail {
+	return &ConfirmEmail{l, uc}
+}
+
+var _ http.Handler = (*ConfirmEmail)(nil)
+
+// ConfirmEmail godoc
+// @Summary      Подтверждение нового email
+// @Description  Применяет смену email по токену из письма. Токен одноразовый
+// @Tags         Auth
+// @Accept       json
+// @Param        request body v1.ConfirmEmailRequest true "Токен из письма"
+// @Success      204
+// @Failure      400  {object}  response.Problem "Invalid or expired token"
+// @Failure      409  {object}  response.Problem "Email is taken"
+// @Failure      500  {object}  response.Problem
+// @Router       /auth/confirm-email [post]
+func (h *ConfirmEmail) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	data := v1.ConfirmEmailRequest{}
+
+	if err := json.NewDecoder(r.Body).Decode(&data); err != nil {
+		response.WriteProblem(w, http.StatusBadRequest, "Error input data")
+		return
+	}
+
+	if err := validation.ValidateConfirmEmailRequest(&data); err != nil {
+		response.WriteProblem(w, http.StatusBadRequest, fmt.Sprintf("Error validating data: %s", err))
+		return
+	}
+
+	if err := h.uc.ConfirmEmailChange(r.Context(), data.Token); err != nil {
+		switch {
+		case errors.Is(err, usecase.ErrInvalidToken):
+			response.WriteProblem(w, http.StatusBadRequest, "Invalid or expired token")
+		case errors.Is(err, usecase.ErrEmailTaken):
+			response.WriteProblem(w, http.StatusConflict, "Email is taken")
+		default:
+			h.l.Error("Confirm email failed", "error", err)
+			response.WriteProblem(w, http.StatusInternalServerError, "Confirm email failed")
+		}
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,36 @@
+package auth
+
+import (
+	"net/http"
+	"time"
+)
+
+const refreshCookieName = "refreshToken"
+
+// CookieOptions scopes the refresh token cookie so that browsers only send it
+// to the auth endpoints and never along with cross-site requests.
+type CookieOptions struct {
+	Domain   string
+	Path     string
+	SameSite http.SameSite
+}
+
+func (o CookieOptions) refreshCookie(value string) *http.Cookie {
+	return &http.Cookie{
+		Name:     refreshCookieName,
+		Value:    value,
+		Domain:   o.Domain,
+		Path:     o.Path,
+		HttpOnly: true,
+		Secure:   true,
+		SameSite: o.SameSite,
+	}
+}
+
+func (o CookieOptions) expiredRefreshCookie() *http.Cookie {
+	c := o.refreshCookie("")
+	c.MaxAge = -1
+	c.Expires = time.Unix(0, 0)
+
+	return c
+}
@@ -0,0 +1,66 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	v1 "github.com/SkySock/lode/libs/shared-dto/user/http/v1"
+	"github.com/SkySock/lode/libs/utils/http/response"
+	"github.com/SkySock/lode/services/user-service/internal/usecase"
+)
+
+type Refresh struct {
+	l      *slog.Logger
+	uc     refreshUsecase
+	cookie CookieOptions
+}
+
+type refreshUsecase interface {
+	Refresh(ctx context.Context, refreshToken string) (*usecase.AuthTokens, error)
+}
+
+func NewRefresh(l *slog.Logger, uc refreshUsecase, cookie CookieOptions) *Refresh {
+	return &Refresh{l, uc, cookie}
+}
+
+var _ http.Handler = (*Refresh)(nil)
+
+// Refresh godoc
+// @Summary      Обновление токенов
+// @Description  Выдает новый access токен и заменяет refresh токен в файле cookie refreshToken
+// @Tags         Auth
+// @Produce      json
+// @Success      200  {object}  v1.RefreshResponse
+// @Failure      401  {object}  response.Problem
+// @Failure      403  {object}  response.Problem "Cross-origin request rejected"
+// @Failure      500  {object}  response.Problem
+// @Router       /auth/refresh [post]
+func (h *Refresh) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	refresh, err := r.Cookie(refreshCookieName)
+	if err != nil || refresh.Value == "" {
+		response.WriteProblem(w, http.StatusUnauthorized, "Missing or empty refreshToken cookie")
+		return
+	}
+
+	tokens, err := h.uc.Refresh(r.Context(), refresh.Value)
+	if err != nil {
+		if errors.Is(err, usecase.ErrSessionNotFound) ||
+			errors.Is(err, usecase.ErrSessionRevoked) ||
+			errors.Is(err, usecase.ErrSessionExpired) {
+			http.SetCookie(w, h.cookie.expiredRefreshCookie())
+			response.WriteProblem(w, http.StatusUnauthorized, "Session is no longer valid")
+			return
+		}
+		h.l.Error("Refresh failed", "error", err)
+		response.WriteProblem(w, http.StatusInternalServerError, "Refresh failed")
+		return
+	}
+
+	http.SetCookie(w, h.cookie.refreshCookie(tokens.RefreshToken))
+
+	if err := response.WriteJSON(w, http.StatusOK, &v1.RefreshResponse{AccessToken: tokens.AccessToken}); err != nil {
+		h.l.Error("JSON encoding failed", "error", err)
+	}
+}
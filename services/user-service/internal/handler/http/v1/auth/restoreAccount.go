@@ -0,0 +1,79 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+
+	v1 "github.com/SkySock/lode/libs/shared-dto/user/http/v1"
+	"github.com/SkySock/lode/libs/utils/http/response"
+	"github.com/SkySock/lode/services/user-service/internal/usecase"
+	"github.com/SkySock/lode/services/user-service/internal/validation"
+)
+
+type RestoreAccount struct {
+	l  *slog.Logger
+	uc restoreAccountUsecase
+}
+
+type restoreAccountUsecase interface {
+	RestoreAccount(ctx context.Context, login, password string) error
+}
+
+func NewRestoreAccount(l *slog.Logger, uc restoreAccountUsecase) *RestoreAccount {
+	return &RestoreAccount{l, uc}
+}
+
+var _ http.Handler = (*RestoreAccount)(nil)
+
+// RestoreAccount godoc
+// @Summary      Восстановление аккаунта
+// @Description  Отменяет удаление аккаунта, пока оно не стало окончательным
+// @Tags         Auth
+// @Accept       json
+// @Param        request body v1.SignInRequest true "Данные входа"
+// @Success      204
+// @Failure      400  {object}  response.Problem
+// @Failure      401  {object}  response.Problem
+// @Failure      403  {object}  response.Problem "Account locked"
+// @Failure      409  {object}  response.Problem "Account is not deleted"
+// @Failure      500  {object}  response.Problem
+// @Router       /auth/restore-account [post]
+func (h *RestoreAccount) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	data := v1.SignInRequest{}
+
+	if err := json.NewDecoder(r.Body).Decode(&data); err != nil {
+		response.WriteProblem(w, http.StatusBadRequest, "Error input data")
+		return
+	}
+
+	data.Normalize()
+
+	if err := validation.ValidateSignInRequest(&data); err != nil {
+		response.WriteProblem(w, http.StatusBadRequest, fmt.Sprintf("Error validating data: %s", err))
+		return
+	}
+
+	if err := h.uc.RestoreAccount(r.Context(), data.Login, data.Password); err != nil {
+		switch {
+		case errors.Is(err, usecase.ErrIncorrectPassword),
+			errors.Is(err, usecase.ErrEmailNotFound),
+			errors.Is(err, usecase.ErrUsernameNotFound),
+			errors.Is(err, usecase.ErrAccountNotFound):
+			response.WriteProblem(w, http.StatusUnauthorized, "Invalid credentials")
+		case errors.Is(err, usecase.ErrAccountLocked):
+			response.WriteProblem(w, http.StatusForbidden, "Account is locked")
+		case errors.Is(err, usecase.ErrAccountNotDeleted):
+			response.WriteProblem(w, http.StatusConflict, "Account is not deleted")
+		default:
+			h.l.Error("Restore account failed", "error", err)
+			response.WriteProblem(w, http.StatusInternalServerError, "Restore account failed")
+		}
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
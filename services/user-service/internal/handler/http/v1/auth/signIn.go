@@ -15,16 +15,17 @@ import (
 )
 
 type SignIn struct {
-	l  *slog.Logger
-	uc loginUsecase
+	l      *slog.Logger
+	uc     loginUsecase
+	cookie CookieOptions
 }
 
 type loginUsecase interface {
 	Login(ctx context.Context, login, password string) (*usecase.AuthTokens, error)
 }
 
-func NewSignIn(l *slog.Logger, uc loginUsecase) *SignIn {
-	return &SignIn{l, uc}
+func NewSignIn(l *slog.Logger, uc loginUsecase, cookie CookieOptions) *SignIn {
+	return &SignIn{l, uc, cookie}
 }
 
 var _ http.Handler = (*SignIn)(nil)
@@ -37,6 +38,10 @@ var _ http.Handler = (*SignIn)(nil)
 // @Produce      json
 // @Param        request body v1.SignInRequest true "Данные регистрации"
 // @Success      200  {object}  v1.SignInResponse
+// @Failure      400  {object}  response.Problem
+// @Failure      401  {object}  response.Problem
+// @Failure      403  {object}  response.Problem "Account locked, deleted or password reset required"
+// @Failure      500  {object}  response.Problem
 // @Router       /auth/sign-in [post]
 func (h *SignIn) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost {
@@ -48,14 +53,14 @@ func (h *SignIn) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	data := v1.SignInRequest{}
 
 	if err := json.NewDecoder(r.Body).Decode(&data); err != nil {
-		http.Error(w, "Error input data", http.StatusBadRequest)
+		response.WriteProblem(w, http.StatusBadRequest, "Error input data")
 		return
 	}
 
 	data.Normalize()
 
 	if err := validation.ValidateSignInRequest(&data); err != nil {
-		http.Error(w, fmt.Sprintf("Error validating data: %s", err), http.StatusBadRequest)
+		response.WriteProblem(w, http.StatusBadRequest, fmt.Sprintf("Error validating data: %s", err))
 		return
 	}
 
@@ -64,22 +69,27 @@ func (h *SignIn) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		if errors.Is(err, usecase.ErrIncorrectPassword) ||
 			errors.Is(err, usecase.ErrEmailNotFound) ||
 			errors.Is(err, usecase.ErrUsernameNotFound) {
-			http.Error(w, "Invalid credentials", http.StatusUnauthorized)
+			response.WriteProblem(w, http.StatusUnauthorized, "Invalid credentials")
+			return
+		}
+		if errors.Is(err, usecase.ErrAccountLocked) {
+			response.WriteProblem(w, http.StatusForbidden, "Account is locked")
+			return
+		}
+		if errors.Is(err, usecase.ErrPasswordResetRequired) {
+			response.WriteProblem(w, http.StatusForbidden, "Password reset required")
+			return
+		}
+		if errors.Is(err, usecase.ErrAccountDeleted) {
+			response.WriteProblem(w, http.StatusForbidden, "Account is scheduled for deletion")
 			return
 		}
 		h.l.Error("Login failed", "error", err)
-		http.Error(w, "Authentication failed", http.StatusInternalServerError)
+		response.WriteProblem(w, http.StatusInternalServerError, "Authentication failed")
 		return
 	}
 
-	refreshToken := http.Cookie{
-		Name:     "refreshToken",
-		Value:    tokens.RefreshToken,
-		Path:     "/",
-		HttpOnly: true,
-		Secure:   true,
-	}
-	http.SetCookie(w, &refreshToken)
+	http.SetCookie(w, h.cookie.refreshCookie(tokens.RefreshToken))
 
 	responseBody := &v1.SignInResponse{
 		AccessToken: tokens.AccessToken,
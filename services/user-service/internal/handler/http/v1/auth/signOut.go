@@ -4,20 +4,22 @@ import (
 	"context"
 	"log/slog"
 	"net/http"
-	"time"
+
+	"github.com/SkySock/lode/libs/utils/http/response"
 )
 
 type SignOut struct {
-	l  *slog.Logger
-	uc logoutUsecase
+	l      *slog.Logger
+	uc     logoutUsecase
+	cookie CookieOptions
 }
 
 type logoutUsecase interface {
 	Logout(ctx context.Context, refreshToken string) error
 }
 
-func NewSignOut(l *slog.Logger, uc logoutUsecase) *SignOut {
-	return &SignOut{l, uc}
+func NewSignOut(l *slog.Logger, uc logoutUsecase, cookie CookieOptions) *SignOut {
+	return &SignOut{l, uc, cookie}
 }
 
 var _ http.Handler = (*SignOut)(nil)
@@ -29,8 +31,9 @@ var _ http.Handler = (*SignOut)(nil)
 // @Accept       json
 // @Produce      json
 // @Success      200 {string} string "Successfully logged out"
-// @Failure      400 {string} string "Missing or empty refreshToken cookie"
-// @Failure      500 {string} string "Failed to logout"
+// @Failure      400 {object} response.Problem "Missing or empty refreshToken cookie"
+// @Failure      403 {object} response.Problem "Cross-origin request rejected"
+// @Failure      500 {object} response.Problem "Failed to logout"
 // @Router       /auth/sign-out [post]
 func (h *SignOut) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost {
@@ -39,26 +42,18 @@ func (h *SignOut) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	refresh, err := r.Cookie("refreshToken")
+	refresh, err := r.Cookie(refreshCookieName)
 	if err != nil || refresh.Value == "" {
 		h.l.Warn("missing or empty refreshToken cookie", "error", err)
-		w.WriteHeader(http.StatusBadRequest)
+		response.WriteProblem(w, http.StatusBadRequest, "Missing or empty refreshToken cookie")
 		return
 	}
 
 	if err = h.uc.Logout(r.Context(), refresh.Value); err != nil {
 		h.l.Error("failed to logout", "error", err)
-		w.WriteHeader(http.StatusInternalServerError)
+		response.WriteProblem(w, http.StatusInternalServerError, "Failed to logout")
 		return
 	}
-	http.SetCookie(w, &http.Cookie{
-		Name:     "refreshToken",
-		Value:    "",
-		Path:     "/",
-		MaxAge:   -1,
-		Expires:  time.Unix(0, 0),
-		HttpOnly: true,
-		Secure:   true,
-	})
+	http.SetCookie(w, h.cookie.expiredRefreshCookie())
 	w.WriteHeader(http.StatusOK)
 }
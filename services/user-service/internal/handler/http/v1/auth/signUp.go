@@ -39,6 +39,9 @@ var _ http.Handler = (*SignUp)(nil)
 // @Produce      json
 // @Param        request body v1.SignUpRequest true "Данные регистрации"
 // @Success      201  {object}  v1.SignUpResponse
+// @Failure      400  {object}  response.Problem
+// @Failure      409  {object}  response.Problem
+// @Failure      500  {object}  response.Problem
 // @Router       /auth/sign-up [post]
 func (h *SignUp) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost {
@@ -51,14 +54,14 @@ func (h *SignUp) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 
 	err := json.NewDecoder(r.Body).Decode(&data)
 	if err != nil {
-		http.Error(w, "Error input data", http.StatusBadRequest)
+		response.WriteProblem(w, http.StatusBadRequest, "Error input data")
 		return
 	}
 	data.Normalize()
 
 	err = validation.ValidateSignUpRequest(&data)
 	if err != nil {
-		http.Error(w, fmt.Sprintf("Error validating data: %s", err), http.StatusBadRequest)
+		response.WriteProblem(w, http.StatusBadRequest, fmt.Sprintf("Error validating data: %s", err))
 		return
 	}
 
@@ -71,10 +74,11 @@ func (h *SignUp) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	userId, err := h.uc.RegisterUser(r.Context(), newUser)
 	if err != nil {
 		if errors.Is(err, usecase.ErrEmailOrUsernameAlreadyExists) {
-			http.Error(w, "Username or email already exists", http.StatusConflict)
+			response.WriteProblem(w, http.StatusConflict, "Username or email already exists")
 			return
 		}
-		http.Error(w, "Error", http.StatusInternalServerError)
+		h.l.Error("Registration failed", "error", err)
+		response.WriteProblem(w, http.StatusInternalServerError, "Registration failed")
 		return
 	}
 
@@ -0,0 +1,68 @@
+package profile
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/SkySock/lode/libs/utils/http/response"
+	"github.com/SkySock/lode/services/user-service/internal/usecase"
+	"github.com/google/uuid"
+)
+
+type ApproveFollowRequest struct {
+	l  *slog.Logger
+	uc approveFollowRequestUsecase
+}
+
+type approveFollowRequestUsecase interface {
+	ApproveFollowRequest(ctx context.Context, accountID, profileID, requesterID uuid.UUID) error
+}
+
+func NewApproveFollowRequest(l *slog.Logger, uc approveFollowRequestUsecase) *ApproveFollowRequest {
+	return &ApproveFollowRequest{l, uc}
+}
+
+var _ http.Handler = (*ApproveFollowRequest)(nil)
+
+// ApproveFollowRequest godoc
+// @Summary      Подтверждение запроса на подписку
+// @Description  Подписывает отправивший запрос профиль на профиль аккаунта
+// @Tags         Relation
+// @Security     BearerAuth
+// @Param        profileId path string true "ID профиля аккаунта"
+// @Param        targetId  path string true "ID профиля, отправившего запрос"
+// @Success      204
+// @Failure      400  {object}  response.Problem
+// @Failure      401  {object}  response.Problem
+// @Failure      403  {object}  response.Problem "Профиль принадлежит другому аккаунту или профили заблокировали друг друга"
+// @Failure      404  {object}  response.Problem "Профиль или запрос на подписку не найден"
+// @Failure      500  {object}  response.Problem
+// @Router       /profiles/{profileId}/follow-requests/{targetId} [put]
+func (h *ApproveFollowRequest) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	accountID, profileID, requesterID, ok := relationFromPath(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.uc.ApproveFollowRequest(r.Context(), accountID, profileID, requesterID); err != nil {
+		switch {
+		case errors.Is(err, usecase.ErrProfileNotFound):
+			response.WriteProblem(w, http.StatusNotFound, "Profile not found")
+		case errors.Is(err, usecase.ErrFollowRequestNotFound):
+			response.WriteProblem(w, http.StatusNotFound, "Follow request not found")
+		case errors.Is(err, usecase.ErrNotProfileOwner):
+			response.WriteProblem(w, http.StatusForbidden, "Profile belongs to another account")
+		case errors.Is(err, usecase.ErrBlocked):
+			response.WriteProblem(w, http.StatusForbidden, "Profiles block each other")
+		default:
+			h.l.Error("Approve follow request failed", "error", err)
+			response.WriteProblem(w, http.StatusInternalServerError, "Approve follow request failed")
+		}
+		return
+	}
+
+	h.l.Info("Follow request approved", "profile_id", profileID, "requester_id", requesterID)
+	w.WriteHeader(http.StatusNoContent)
+}
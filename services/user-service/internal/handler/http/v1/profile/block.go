@@ -0,0 +1,66 @@
+package profile
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/SkySock/lode/libs/utils/http/response"
+	"github.com/SkySock/lode/services/user-service/internal/usecase"
+	"github.com/google/uuid"
+)
+
+type Block struct {
+	l  *slog.Logger
+	uc blockUsecase
+}
+
+type blockUsecase interface {
+	Block(ctx context.Context, accountID, profileID, targetID uuid.UUID) error
+}
+
+func NewBlock(l *slog.Logger, uc blockUsecase) *Block {
+	return &Block{l, uc}
+}
+
+var _ http.Handler = (*Block)(nil)
+
+// Block godoc
+// @Summary      Блокировка профиля
+// @Description  Блокирует профиль: удаляет подписки профилей друг на друга и запрещает новые, а также скрывает профили друг от друга в списках подписок
+// @Tags         Relation
+// @Security     BearerAuth
+// @Param        profileId path string true "ID профиля аккаунта"
+// @Param        targetId  path string true "ID блокируемого профиля"
+// @Success      204
+// @Failure      400  {object}  response.Problem
+// @Failure      401  {object}  response.Problem
+// @Failure      403  {object}  response.Problem "Профиль принадлежит другому аккаунту"
+// @Failure      404  {object}  response.Problem
+// @Failure      500  {object}  response.Problem
+// @Router       /profiles/{profileId}/blocks/{targetId} [put]
+func (h *Block) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	accountID, profileID, targetID, ok := relationFromPath(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.uc.Block(r.Context(), accountID, profileID, targetID); err != nil {
+		switch {
+		case errors.Is(err, usecase.ErrProfileNotFound):
+			response.WriteProblem(w, http.StatusNotFound, "Profile not found")
+		case errors.Is(err, usecase.ErrNotProfileOwner):
+			response.WriteProblem(w, http.StatusForbidden, "Profile belongs to another account")
+		case errors.Is(err, usecase.ErrSelfRelation):
+			response.WriteProblem(w, http.StatusBadRequest, "Profile can not target itself")
+		default:
+			h.l.Error("Block failed", "error", err)
+			response.WriteProblem(w, http.StatusInternalServerError, "Block failed")
+		}
+		return
+	}
+
+	h.l.Info("Profile blocked", "profile_id", profileID, "target_id", targetID)
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,64 @@
+package profile
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/SkySock/lode/libs/utils/http/response"
+	"github.com/SkySock/lode/services/user-service/internal/usecase"
+	"github.com/google/uuid"
+)
+
+type DeclineFollowRequest struct {
+	l  *slog.Logger
+	uc declineFollowRequestUsecase
+}
+
+type declineFollowRequestUsecase interface {
+	DeclineFollowRequest(ctx context.Context, accountID, profileID, requesterID uuid.UUID) error
+}
+
+func NewDeclineFollowRequest(l *slog.Logger, uc declineFollowRequestUsecase) *DeclineFollowRequest {
+	return &DeclineFollowRequest{l, uc}
+}
+
+var _ http.Handler = (*DeclineFollowRequest)(nil)
+
+// DeclineFollowRequest godoc
+// @Summary      Отклонение запроса на подписку
+// @Description  Удаляет запрос на подписку на профиль аккаунта. Отклонение отсутствующего запроса не является ошибкой
+// @Tags         Relation
+// @Security     BearerAuth
+// @Param        profileId path string true "ID профиля аккаунта"
+// @Param        targetId  path string true "ID профиля, отправившего запрос"
+// @Success      204
+// @Failure      400  {object}  response.Problem
+// @Failure      401  {object}  response.Problem
+// @Failure      403  {object}  response.Problem "Профиль принадлежит другому аккаунту"
+// @Failure      404  {object}  response.Problem
+// @Failure      500  {object}  response.Problem
+// @Router       /profiles/{profileId}/follow-requests/{targetId} [delete]
+func (h *DeclineFollowRequest) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	accountID, profileID, requesterID, ok := relationFromPath(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.uc.DeclineFollowRequest(r.Context(), accountID, profileID, requesterID); err != nil {
+		switch {
+		case errors.Is(err, usecase.ErrProfileNotFound):
+			response.WriteProblem(w, http.StatusNotFound, "Profile not found")
+		case errors.Is(err, usecase.ErrNotProfileOwner):
+			response.WriteProblem(w, http.StatusForbidden, "Profile belongs to another account")
+		default:
+			h.l.Error("Decline follow request failed", "error", err)
+			response.WriteProblem(w, http.StatusInternalServerError, "Decline follow request failed")
+		}
+		return
+	}
+
+	h.l.Info("Follow request declined", "profile_id", profileID, "requester_id", requesterID)
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,67 @@
+package profile
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/SkySock/lode/libs/utils/http/response"
+	"github.com/SkySock/lode/services/user-service/internal/usecase"
+	"github.com/google/uuid"
+)
+
+type DeleteAvatar struct {
+	l  *slog.Logger
+	uc deleteAvatarUsecase
+}
+
+type deleteAvatarUsecase interface {
+	DeleteAvatar(ctx context.Context, accountID, profileID uuid.UUID) error
+}
+
+func NewDeleteAvatar(l *slog.Logger, uc deleteAvatarUsecase) *DeleteAvatar {
+	return &DeleteAvatar{l, uc}
+}
+
+var _ http.Handler = (*DeleteAvatar)(nil)
+
+// DeleteAvatar godoc
+// @Summary      Удаление аватара
+// @Description  Удаляет аватар профиля аккаунта вместе с его уменьшенными копиями
+// @Tags         Profile
+// @Security     BearerAuth
+// @Param        profileId path string true "ID профиля"
+// @Success      204
+// @Failure      400  {object}  response.Problem
+// @Failure      401  {object}  response.Problem
+// @Failure      403  {object}  response.Problem "Профиль принадлежит другому аккаунту"
+// @Failure      404  {object}  response.Problem
+// @Failure      500  {object}  response.Problem
+// @Router       /profiles/{profileId}/avatar [delete]
+func (h *DeleteAvatar) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	profileID, ok := profileIDFromPath(w, r)
+	if !ok {
+		return
+	}
+	accountID, ok := accountIDFromContext(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.uc.DeleteAvatar(r.Context(), accountID, profileID); err != nil {
+		switch {
+		case errors.Is(err, usecase.ErrProfileNotFound):
+			response.WriteProblem(w, http.StatusNotFound, "Profile not found")
+		case errors.Is(err, usecase.ErrNotProfileOwner):
+			response.WriteProblem(w, http.StatusForbidden, "Profile belongs to another account")
+		default:
+			h.l.Error("Delete avatar failed", "error", err)
+			response.WriteProblem(w, http.StatusInternalServerError, "Delete avatar failed")
+		}
+		return
+	}
+
+	h.l.Info("Avatar deleted", "profile_id", profileID)
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,76 @@
+package profile
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/SkySock/lode/libs/utils/http/response"
+	"github.com/SkySock/lode/services/user-service/internal/usecase"
+	"github.com/google/uuid"
+)
+
+type Follow struct {
+	l  *slog.Logger
+	uc followUsecase
+}
+
+type followUsecase interface {
+	Follow(ctx context.Context, accountID, profileID, targetID uuid.UUID) (bool, error)
+}
+
+func NewFollow(l *slog.Logger, uc followUsecase) *Follow {
+	return &Follow{l, uc}
+}
+
+var _ http.Handler = (*Follow)(nil)
+
+// Follow godoc
+// @Summary      Подписка на профиль
+// @Description  Подписывает профиль аккаунта на другой профиль. На закрытый профиль отправляется запрос на подписку, который подтверждает его владелец. Повторная подписка не является ошибкой. Подписаться нельзя, если один из профилей заблокировал другой
+// @Tags         Relation
+// @Security     BearerAuth
+// @Param        profileId path string true "ID профиля аккаунта"
+// @Param        targetId  path string true "ID профиля, на который подписываются"
+// @Success      202  "Отправлен запрос на подписку"
+// @Success      204
+// @Failure      400  {object}  response.Problem
+// @Failure      401  {object}  response.Problem
+// @Failure      403  {object}  response.Problem "Профиль принадлежит другому аккаунту или профили заблокировали друг друга"
+// @Failure      404  {object}  response.Problem
+// @Failure      500  {object}  response.Problem
+// @Router       /profiles/{profileId}/following/{targetId} [put]
+func (h *Follow) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	accountID, profileID, targetID, ok := relationFromPath(w, r)
+	if !ok {
+		return
+	}
+
+	requested, err := h.uc.Follow(r.Context(), accountID, profileID, targetID)
+	if err != nil {
+		switch {
+		case errors.Is(err, usecase.ErrProfileNotFound):
+			response.WriteProblem(w, http.StatusNotFound, "Profile not found")
+		case errors.Is(err, usecase.ErrNotProfileOwner):
+			response.WriteProblem(w, http.StatusForbidden, "Profile belongs to another account")
+		case errors.Is(err, usecase.ErrSelfRelation):
+			response.WriteProblem(w, http.StatusBadRequest, "Profile can not target itself")
+		case errors.Is(err, usecase.ErrBlocked):
+			response.WriteProblem(w, http.StatusForbidden, "Profiles block each other")
+		default:
+			h.l.Error("Follow failed", "error", err)
+			response.WriteProblem(w, http.StatusInternalServerError, "Follow failed")
+		}
+		return
+	}
+
+	if requested {
+		h.l.Info("Profile follow requested", "profile_id", profileID, "target_id", targetID)
+		w.WriteHeader(http.StatusAccepted)
+		return
+	}
+
+	h.l.Info("Profile followed", "profile_id", profileID, "target_id", targetID)
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,115 @@
+package profile
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	v1 "github.com/SkySock/lode/libs/shared-dto/user/http/v1"
+	"github.com/SkySock/lode/libs/utils/http/response"
+	"github.com/SkySock/lode/services/user-service/internal/entity"
+	"github.com/SkySock/lode/services/user-service/internal/usecase"
+	"github.com/gorilla/mux"
+)
+
+// cacheControl lets shared caches such as the gateway absorb lookups of
+// popular profiles.
+const cacheControl = "public, max-age=30"
+
+// setViewerCacheControl keeps responses seen by a viewer out of shared
+// caches, since they differ between viewers.
+func setViewerCacheControl(w http.ResponseWriter, viewer *usecase.Viewer) {
+	w.Header().Set("Vary", "Authorization")
+	if viewer != nil {
+		w.Header().Set("Cache-Control", "private")
+	} else {
+		w.Header().Set("Cache-Control", cacheControl)
+	}
+}
+
+type GetProfile struct {
+	l  *slog.Logger
+	uc getProfileUsecase
+}
+
+type getProfileUsecase interface {
+	ViewProfile(ctx context.Context, name string, viewer *usecase.Viewer) (*usecase.ProfileView, error)
+}
+
+func NewGetProfile(l *slog.Logger, uc getProfileUsecase) *GetProfile {
+	return &GetProfile{l, uc}
+}
+
+var _ http.Handler = (*GetProfile)(nil)
+
+// GetProfile godoc
+// @Summary      Получение профиля
+// @Description  Данные профиля по его имени. Био и аватар видны в соответствии с настройками приватности профиля: профиль просматривается от имени профиля из параметра as, а без него — от имени профиля, с которым выполнен вход. Владельцу профиля видны все поля и настройки приватности
+// @Tags         Profile
+// @Produce      json
+// @Security     BearerAuth
+// @Param        profileName path  string true  "Имя профиля"
+// @Param        as          query string false "ID профиля аккаунта, от имени которого просматривается профиль"
+// @Success      200  {object}  v1.ProfileResponse
+// @Failure      400  {object}  response.Problem
+// @Failure      401  {object}  response.Problem "Параметр as указан без токена доступа"
+// @Failure      403  {object}  response.Problem "Профиль из параметра as принадлежит другому аккаунту"
+// @Failure      404  {object}  response.Problem
+// @Failure      500  {object}  response.Problem
+// @Router       /profiles/{profileName} [get]
+func (h *GetProfile) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	viewer, ok := viewerFromRequest(w, r)
+	if !ok {
+		return
+	}
+
+	view, err := h.uc.ViewProfile(r.Context(), mux.Vars(r)["profileName"], viewer)
+	if err != nil {
+		switch {
+		case errors.Is(err, usecase.ErrProfileNotFound):
+			response.WriteProblem(w, http.StatusNotFound, "Profile not found")
+		case errors.Is(err, usecase.ErrNotProfileOwner):
+			response.WriteProblem(w, http.StatusForbidden, "Profile belongs to another account")
+		default:
+			h.l.Error("Get profile failed", "error", err)
+			response.WriteProblem(w, http.StatusInternalServerError, "Get profile failed")
+		}
+		return
+	}
+
+	resp := profileResponse(view.Profile)
+	if view.Audience == entity.AudienceOwner {
+		resp.Privacy = privacyResponse(view.Profile.Privacy)
+	}
+
+	setViewerCacheControl(w, viewer)
+	if err := response.WriteJSON(w, http.StatusOK, resp); err != nil {
+		h.l.Error("JSON encoding failed", "error", err)
+	}
+}
+
+func profileResponse(profile *entity.Profile) v1.ProfileResponse {
+	return v1.ProfileResponse{
+		ID:               profile.ID.String(),
+		UserID:           profile.UserID.String(),
+		ProfileName:      profile.ProfileName,
+		DisplayName:      profile.DisplayName,
+		Bio:              profile.Bio,
+		Avatar:           profile.Avatar,
+		CreatedAt:        profile.CreatedAt,
+		AvatarThumbnails: profile.AvatarThumbnails,
+		FollowersCount:   profile.FollowersCount,
+		FollowingCount:   profile.FollowingCount,
+		IsPrivate:        profile.Privacy.Private,
+	}
+}
+
+func privacyResponse(privacy entity.ProfilePrivacy) *v1.ProfilePrivacyResponse {
+	return &v1.ProfilePrivacyResponse{
+		IsPrivate:        privacy.Private,
+		BioVisibility:    privacy.BioVisibility,
+		AvatarVisibility: privacy.AvatarVisibility,
+		Discoverable:     privacy.Discoverable,
+	}
+}
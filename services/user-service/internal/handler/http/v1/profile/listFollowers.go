@@ -0,0 +1,93 @@
+package profile
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/SkySock/lode/libs/shared-dto/pagination"
+	v1 "github.com/SkySock/lode/libs/shared-dto/user/http/v1"
+	"github.com/SkySock/lode/libs/utils/http/response"
+	"github.com/SkySock/lode/services/user-service/internal/usecase"
+	"github.com/google/uuid"
+)
+
+type ListFollowers struct {
+	l  *slog.Logger
+	uc listFollowersUsecase
+}
+
+type listFollowersUsecase interface {
+	ListFollowers(ctx context.Context, profileID uuid.UUID, viewer *usecase.Viewer, cursor string, limit int) (*usecase.RelationPage, error)
+}
+
+func NewListFollowers(l *slog.Logger, uc listFollowersUsecase) *ListFollowers {
+	return &ListFollowers{l, uc}
+}
+
+var _ http.Handler = (*ListFollowers)(nil)
+
+// ListFollowers godoc
+// @Summary      Подписчики профиля
+// @Description  Список подписчиков профиля, новые первыми. Список просматривается от имени профиля из параметра as, а без него — от имени профиля, с которым выполнен вход: в нём нет профилей, которые он заблокировал или скрыл, и профилей, заблокировавших его. Списки закрытого профиля видны только его подписчикам и владельцу
+// @Tags         Relation
+// @Produce      json
+// @Security     BearerAuth
+// @Param        profileId path  string true  "ID профиля"
+// @Param        as        query string false "ID профиля аккаунта, от имени которого просматривается список"
+// @Param        cursor    query string false "Курсор следующей страницы"
+// @Param        limit     query int    false "Размер страницы" minimum(1) maximum(100) default(20)
+// @Success      200  {object}  pagination.Page[v1.RelationResponse]
+// @Failure      400  {object}  response.Problem
+// @Failure      401  {object}  response.Problem "Параметр as указан без токена доступа"
+// @Failure      403  {object}  response.Problem "Профиль закрыт или профиль из параметра as принадлежит другому аккаунту"
+// @Failure      404  {object}  response.Problem
+// @Failure      500  {object}  response.Problem
+// @Router       /profiles/{profileId}/followers [get]
+func (h *ListFollowers) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	profileID, ok := profileIDFromPath(w, r)
+	if !ok {
+		return
+	}
+	q := r.URL.Query()
+	limit, ok := limitFromQuery(w, q)
+	if !ok {
+		return
+	}
+	viewer, ok := viewerFromRequest(w, r)
+	if !ok {
+		return
+	}
+
+	page, err := h.uc.ListFollowers(r.Context(), profileID, viewer, q.Get("cursor"), limit)
+	if err != nil {
+		switch {
+		case errors.Is(err, usecase.ErrProfileNotFound):
+			response.WriteProblem(w, http.StatusNotFound, "Profile not found")
+		case errors.Is(err, usecase.ErrNotProfileOwner):
+			response.WriteProblem(w, http.StatusForbidden, "Profile belongs to another account")
+		case errors.Is(err, usecase.ErrProfilePrivate):
+			response.WriteProblem(w, http.StatusForbidden, "Profile is private")
+		case errors.Is(err, usecase.ErrInvalidCursor):
+			response.WriteProblem(w, http.StatusBadRequest, "Invalid cursor")
+		default:
+			h.l.Error("List followers failed", "error", err)
+			response.WriteProblem(w, http.StatusInternalServerError, "List followers failed")
+		}
+		return
+	}
+
+	resp := pagination.Page[v1.RelationResponse]{
+		Items:      make([]v1.RelationResponse, 0, len(page.Relations)),
+		NextCursor: page.NextCursor,
+	}
+	for _, relation := range page.Relations {
+		resp.Items = append(resp.Items, relationResponse(relation))
+	}
+
+	setViewerCacheControl(w, viewer)
+	if err := response.WriteJSON(w, http.StatusOK, resp); err != nil {
+		h.l.Error("JSON encoding failed", "error", err)
+	}
+}
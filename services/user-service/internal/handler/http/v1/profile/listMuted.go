@@ -0,0 +1,90 @@
+package profile
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/SkySock/lode/libs/shared-dto/pagination"
+	v1 "github.com/SkySock/lode/libs/shared-dto/user/http/v1"
+	"github.com/SkySock/lode/libs/utils/http/response"
+	"github.com/SkySock/lode/services/user-service/internal/usecase"
+	"github.com/google/uuid"
+)
+
+type ListMuted struct {
+	l  *slog.Logger
+	uc listMutedUsecase
+}
+
+type listMutedUsecase interface {
+	ListMuted(ctx context.Context, accountID, profileID uuid.UUID, cursor string, limit int) (*usecase.RelationPage, error)
+}
+
+func NewListMuted(l *slog.Logger, uc listMutedUsecase) *ListMuted {
+	return &ListMuted{l, uc}
+}
+
+var _ http.Handler = (*ListMuted)(nil)
+
+// ListMuted godoc
+// @Summary      Скрытые профили
+// @Description  Список профилей, скрытых профилем аккаунта, новые первыми
+// @Tags         Relation
+// @Produce      json
+// @Security     BearerAuth
+// @Param        profileId path  string true  "ID профиля аккаунта"
+// @Param        cursor    query string false "Курсор следующей страницы"
+// @Param        limit     query int    false "Размер страницы" minimum(1) maximum(100) default(20)
+// @Success      200  {object}  pagination.Page[v1.RelationResponse]
+// @Failure      400  {object}  response.Problem
+// @Failure      401  {object}  response.Problem
+// @Failure      403  {object}  response.Problem "Профиль принадлежит другому аккаунту"
+// @Failure      404  {object}  response.Problem
+// @Failure      500  {object}  response.Problem
+// @Router       /profiles/{profileId}/mutes [get]
+func (h *ListMuted) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	profileID, ok := profileIDFromPath(w, r)
+	if !ok {
+		return
+	}
+	accountID, ok := accountIDFromContext(w, r)
+	if !ok {
+		return
+	}
+	q := r.URL.Query()
+	limit, ok := limitFromQuery(w, q)
+	if !ok {
+		return
+	}
+
+	page, err := h.uc.ListMuted(r.Context(), accountID, profileID, q.Get("cursor"), limit)
+	if err != nil {
+		switch {
+		case errors.Is(err, usecase.ErrProfileNotFound):
+			response.WriteProblem(w, http.StatusNotFound, "Profile not found")
+		case errors.Is(err, usecase.ErrNotProfileOwner):
+			response.WriteProblem(w, http.StatusForbidden, "Profile belongs to another account")
+		case errors.Is(err, usecase.ErrInvalidCursor):
+			response.WriteProblem(w, http.StatusBadRequest, "Invalid cursor")
+		default:
+			h.l.Error("List muted failed", "error", err)
+			response.WriteProblem(w, http.StatusInternalServerError, "List muted failed")
+		}
+		return
+	}
+
+	resp := pagination.Page[v1.RelationResponse]{
+		Items:      make([]v1.RelationResponse, 0, len(page.Relations)),
+		NextCursor: page.NextCursor,
+	}
+	for _, relation := range page.Relations {
+		resp.Items = append(resp.Items, relationResponse(relation))
+	}
+
+	w.Header().Set("Cache-Control", "private")
+	if err := response.WriteJSON(w, http.StatusOK, resp); err != nil {
+		h.l.Error("JSON encoding failed", "error", err)
+	}
+}
@@ -0,0 +1,66 @@
+package profile
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/SkySock/lode/libs/utils/http/response"
+	"github.com/SkySock/lode/services/user-service/internal/usecase"
+	"github.com/google/uuid"
+)
+
+type Mute struct {
+	l  *slog.Logger
+	uc muteUsecase
+}
+
+type muteUsecase interface {
+	Mute(ctx context.Context, accountID, profileID, targetID uuid.UUID) error
+}
+
+func NewMute(l *slog.Logger, uc muteUsecase) *Mute {
+	return &Mute{l, uc}
+}
+
+var _ http.Handler = (*Mute)(nil)
+
+// Mute godoc
+// @Summary      Скрытие профиля
+// @Description  Скрывает профиль из списков подписок, просматриваемых от имени профиля аккаунта. Подписки не затрагиваются
+// @Tags         Relation
+// @Security     BearerAuth
+// @Param        profileId path string true "ID профиля аккаунта"
+// @Param        targetId  path string true "ID скрываемого профиля"
+// @Success      204
+// @Failure      400  {object}  response.Problem
+// @Failure      401  {object}  response.Problem
+// @Failure      403  {object}  response.Problem "Профиль принадлежит другому аккаунту"
+// @Failure      404  {object}  response.Problem
+// @Failure      500  {object}  response.Problem
+// @Router       /profiles/{profileId}/mutes/{targetId} [put]
+func (h *Mute) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	accountID, profileID, targetID, ok := relationFromPath(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.uc.Mute(r.Context(), accountID, profileID, targetID); err != nil {
+		switch {
+		case errors.Is(err, usecase.ErrProfileNotFound):
+			response.WriteProblem(w, http.StatusNotFound, "Profile not found")
+		case errors.Is(err, usecase.ErrNotProfileOwner):
+			response.WriteProblem(w, http.StatusForbidden, "Profile belongs to another account")
+		case errors.Is(err, usecase.ErrSelfRelation):
+			response.WriteProblem(w, http.StatusBadRequest, "Profile can not target itself")
+		default:
+			h.l.Error("Mute failed", "error", err)
+			response.WriteProblem(w, http.StatusInternalServerError, "Mute failed")
+		}
+		return
+	}
+
+	h.l.Info("Profile muted", "profile_id", profileID, "target_id", targetID)
+	w.WriteHeader(http.StatusNoContent)
+}
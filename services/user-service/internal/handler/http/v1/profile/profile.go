@@ -0,0 +1,34 @@
+package profile
+
+import (
+	"net/http"
+
+	"github.com/SkySock/lode/libs/utils/http/middleware"
+	"github.com/SkySock/lode/libs/utils/http/response"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+)
+
+// accountIDFromContext returns the id of the authenticated account.
+func accountIDFromContext(w http.ResponseWriter, r *http.Request) (uuid.UUID, bool) {
+	claims, ok := middleware.ClaimsFromContext(r.Context())
+	if !ok {
+		response.WriteProblem(w, http.StatusUnauthorized, "Missing bearer token")
+		return uuid.Nil, false
+	}
+	id, err := uuid.Parse(claims.Subject)
+	if err != nil {
+		response.WriteProblem(w, http.StatusUnauthorized, "Invalid token subject")
+		return uuid.Nil, false
+	}
+	return id, true
+}
+
+func profileIDFromPath(w http.ResponseWriter, r *http.Request) (uuid.UUID, bool) {
+	id, err := uuid.Parse(mux.Vars(r)["profileId"])
+	if err != nil {
+		response.WriteProblem(w, http.StatusBadRequest, "profileId must be a UUID")
+		return uuid.Nil, false
+	}
+	return id, true
+}
@@ -0,0 +1,92 @@
+package profile
+
+import (
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/SkySock/lode/libs/shared-dto/pagination"
+	v1 "github.com/SkySock/lode/libs/shared-dto/user/http/v1"
+	"github.com/SkySock/lode/libs/utils/http/middleware"
+	"github.com/SkySock/lode/libs/utils/http/response"
+	"github.com/SkySock/lode/services/user-service/internal/entity"
+	"github.com/SkySock/lode/services/user-service/internal/usecase"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+)
+
+// relationFromPath returns the signed in account, its profile and the target
+// of a follow, block or mute.
+func relationFromPath(w http.ResponseWriter, r *http.Request) (uuid.UUID, uuid.UUID, uuid.UUID, bool) {
+	profileID, ok := profileIDFromPath(w, r)
+	if !ok {
+		return uuid.Nil, uuid.Nil, uuid.Nil, false
+	}
+	targetID, err := uuid.Parse(mux.Vars(r)["targetId"])
+	if err != nil {
+		response.WriteProblem(w, http.StatusBadRequest, "targetId must be a UUID")
+		return uuid.Nil, uuid.Nil, uuid.Nil, false
+	}
+	accountID, ok := accountIDFromContext(w, r)
+	if !ok {
+		return uuid.Nil, uuid.Nil, uuid.Nil, false
+	}
+
+	return accountID, profileID, targetID, true
+}
+
+// viewerFromRequest returns the signed in account viewing as the profile named
+// by the as query parameter, which must belong to it. Without as the account
+// views as the profile it signed in with, and an anonymous request has a nil
+// viewer.
+func viewerFromRequest(w http.ResponseWriter, r *http.Request) (*usecase.Viewer, bool) {
+	s := r.URL.Query().Get("as")
+	claims, signedIn := middleware.ClaimsFromContext(r.Context())
+	if !signedIn && s == "" {
+		return nil, true
+	}
+
+	accountID, ok := accountIDFromContext(w, r)
+	if !ok {
+		return nil, false
+	}
+	viewer := &usecase.Viewer{AccountID: accountID}
+	if s == "" {
+		// A token without a profile views as no profile.
+		if profileID, err := uuid.Parse(claims.ProfileID); err == nil {
+			viewer.ProfileID = profileID
+		}
+		return viewer, true
+	}
+
+	profileID, err := uuid.Parse(s)
+	if err != nil {
+		response.WriteProblem(w, http.StatusBadRequest, "as must be a UUID")
+		return nil, false
+	}
+	viewer.ProfileID = profileID
+
+	return viewer, true
+}
+
+func limitFromQuery(w http.ResponseWriter, q url.Values) (int, bool) {
+	s := q.Get("limit")
+	if s == "" {
+		return pagination.DefaultLimit, true
+	}
+
+	n, err := strconv.Atoi(s)
+	if err != nil || n < 1 || n > pagination.MaxLimit {
+		response.WriteProblem(w, http.StatusBadRequest, "limit must be between 1 and 100")
+		return 0, false
+	}
+	return n, true
+}
+
+func relationResponse(relation *entity.Relation) v1.RelationResponse {
+	return v1.RelationResponse{
+		ID:        relation.ID.String(),
+		Profile:   profileResponse(relation.Profile),
+		CreatedAt: relation.CreatedAt,
+	}
+}
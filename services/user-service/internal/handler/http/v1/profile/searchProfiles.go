@@ -0,0 +1,96 @@
+package profile
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"unicode/utf8"
+
+	"github.com/SkySock/lode/libs/shared-dto/pagination"
+	v1 "github.com/SkySock/lode/libs/shared-dto/user/http/v1"
+	"github.com/SkySock/lode/libs/utils/http/response"
+	"github.com/SkySock/lode/services/user-service/internal/usecase"
+)
+
+const maxQueryLength = 64
+
+type SearchProfiles struct {
+	l  *slog.Logger
+	uc searchProfilesUsecase
+}
+
+type searchProfilesUsecase interface {
+	SearchProfiles(ctx context.Context, query string, viewer *usecase.Viewer, cursor string, limit int) (*usecase.ProfilePage, error)
+}
+
+func NewSearchProfiles(l *slog.Logger, uc searchProfilesUsecase) *SearchProfiles {
+	return &SearchProfiles{l, uc}
+}
+
+var _ http.Handler = (*SearchProfiles)(nil)
+
+// SearchProfiles godoc
+// @Summary      Поиск профилей
+// @Description  Ищет профили по началу имени или отображаемого имени, а также по похожим именам. Без запроса возвращает все профили. Профили упорядочены по времени создания. Поиск выполняется от имени профиля из параметра as, а без него — от имени профиля, с которым выполнен вход: в результатах нет профилей, которые он заблокировал, и профилей, заблокировавших его
+// @Tags         Profile
+// @Produce      json
+// @Security     BearerAuth
+// @Param        q      query string false "Имя профиля или отображаемое имя" maxlength(64)
+// @Param        as     query string false "ID профиля аккаунта, от имени которого выполняется поиск"
+// @Param        cursor query string false "Курсор следующей страницы"
+// @Param        limit  query int    false "Размер страницы" minimum(1) maximum(100) default(20)
+// @Success      200  {object}  pagination.Page[v1.ProfileResponse]
+// @Failure      400  {object}  response.Problem
+// @Failure      401  {object}  response.Problem "Параметр as указан без токена доступа"
+// @Failure      403  {object}  response.Problem "Профиль из параметра as принадлежит другому аккаунту"
+// @Failure      404  {object}  response.Problem "Профиль из параметра as не найден"
+// @Failure      500  {object}  response.Problem
+// @Router       /profiles [get]
+func (h *SearchProfiles) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+
+	query := q.Get("q")
+	if utf8.RuneCountInString(query) > maxQueryLength {
+		response.WriteProblem(w, http.StatusBadRequest, "q must be at most 64 characters")
+		return
+	}
+
+	limit, ok := limitFromQuery(w, q)
+	if !ok {
+		return
+	}
+	viewer, ok := viewerFromRequest(w, r)
+	if !ok {
+		return
+	}
+
+	page, err := h.uc.SearchProfiles(r.Context(), query, viewer, q.Get("cursor"), limit)
+	if err != nil {
+		switch {
+		case errors.Is(err, usecase.ErrInvalidCursor):
+			response.WriteProblem(w, http.StatusBadRequest, "Invalid cursor")
+		case errors.Is(err, usecase.ErrProfileNotFound):
+			response.WriteProblem(w, http.StatusNotFound, "Profile not found")
+		case errors.Is(err, usecase.ErrNotProfileOwner):
+			response.WriteProblem(w, http.StatusForbidden, "Profile belongs to another account")
+		default:
+			h.l.Error("Search profiles failed", "error", err)
+			response.WriteProblem(w, http.StatusInternalServerError, "Search profiles failed")
+		}
+		return
+	}
+
+	resp := pagination.Page[v1.ProfileResponse]{
+		Items:      make([]v1.ProfileResponse, 0, len(page.Profiles)),
+		NextCursor: page.NextCursor,
+	}
+	for _, profile := range page.Profiles {
+		resp.Items = append(resp.Items, profileResponse(profile))
+	}
+
+	setViewerCacheControl(w, viewer)
+	if err := response.WriteJSON(w, http.StatusOK, resp); err != nil {
+		h.l.Error("JSON encoding failed", "error", err)
+	}
+}
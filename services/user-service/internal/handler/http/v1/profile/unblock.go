@@ -0,0 +1,64 @@
+package profile
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/SkySock/lode/libs/utils/http/response"
+	"github.com/SkySock/lode/services/user-service/internal/usecase"
+	"github.com/google/uuid"
+)
+
+type Unblock struct {
+	l  *slog.Logger
+	uc unblockUsecase
+}
+
+type unblockUsecase interface {
+	Unblock(ctx context.Context, accountID, profileID, targetID uuid.UUID) error
+}
+
+func NewUnblock(l *slog.Logger, uc unblockUsecase) *Unblock {
+	return &Unblock{l, uc}
+}
+
+var _ http.Handler = (*Unblock)(nil)
+
+// Unblock godoc
+// @Summary      Разблокировка профиля
+// @Description  Снимает блокировку профиля. Удалённые блокировкой подписки не восстанавливаются
+// @Tags         Relation
+// @Security     BearerAuth
+// @Param        profileId path string true "ID профиля аккаунта"
+// @Param        targetId  path string true "ID разблокируемого профиля"
+// @Success      204
+// @Failure      400  {object}  response.Problem
+// @Failure      401  {object}  response.Problem
+// @Failure      403  {object}  response.Problem "Профиль принадлежит другому аккаунту"
+// @Failure      404  {object}  response.Problem
+// @Failure      500  {object}  response.Problem
+// @Router       /profiles/{profileId}/blocks/{targetId} [delete]
+func (h *Unblock) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	accountID, profileID, targetID, ok := relationFromPath(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.uc.Unblock(r.Context(), accountID, profileID, targetID); err != nil {
+		switch {
+		case errors.Is(err, usecase.ErrProfileNotFound):
+			response.WriteProblem(w, http.StatusNotFound, "Profile not found")
+		case errors.Is(err, usecase.ErrNotProfileOwner):
+			response.WriteProblem(w, http.StatusForbidden, "Profile belongs to another account")
+		default:
+			h.l.Error("Unblock failed", "error", err)
+			response.WriteProblem(w, http.StatusInternalServerError, "Unblock failed")
+		}
+		return
+	}
+
+	h.l.Info("Profile unblocked", "profile_id", profileID, "target_id", targetID)
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,64 @@
+package profile
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/SkySock/lode/libs/utils/http/response"
+	"github.com/SkySock/lode/services/user-service/internal/usecase"
+	"github.com/google/uuid"
+)
+
+type Unfollow struct {
+	l  *slog.Logger
+	uc unfollowUsecase
+}
+
+type unfollowUsecase interface {
+	Unfollow(ctx context.Context, accountID, profileID, targetID uuid.UUID) error
+}
+
+func NewUnfollow(l *slog.Logger, uc unfollowUsecase) *Unfollow {
+	return &Unfollow{l, uc}
+}
+
+var _ http.Handler = (*Unfollow)(nil)
+
+// Unfollow godoc
+// @Summary      Отписка от профиля
+// @Description  Отписывает профиль аккаунта от другого профиля и отзывает запрос на подписку
+// @Tags         Relation
+// @Security     BearerAuth
+// @Param        profileId path string true "ID профиля аккаунта"
+// @Param        targetId  path string true "ID профиля, от которого отписываются"
+// @Success      204
+// @Failure      400  {object}  response.Problem
+// @Failure      401  {object}  response.Problem
+// @Failure      403  {object}  response.Problem "Профиль принадлежит другому аккаунту"
+// @Failure      404  {object}  response.Problem
+// @Failure      500  {object}  response.Problem
+// @Router       /profiles/{profileId}/following/{targetId} [delete]
+func (h *Unfollow) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	accountID, profileID, targetID, ok := relationFromPath(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.uc.Unfollow(r.Context(), accountID, profileID, targetID); err != nil {
+		switch {
+		case errors.Is(err, usecase.ErrProfileNotFound):
+			response.WriteProblem(w, http.StatusNotFound, "Profile not found")
+		case errors.Is(err, usecase.ErrNotProfileOwner):
+			response.WriteProblem(w, http.StatusForbidden, "Profile belongs to another account")
+		default:
+			h.l.Error("Unfollow failed", "error", err)
+			response.WriteProblem(w, http.StatusInternalServerError, "Unfollow failed")
+		}
+		return
+	}
+
+	h.l.Info("Profile unfollowed", "profile_id", profileID, "target_id", targetID)
+	w.WriteHeader(http.StatusNoContent)
+}
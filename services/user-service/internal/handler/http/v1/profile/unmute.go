@@ -0,0 +1,64 @@
+package profile
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/SkySock/lode/libs/utils/http/response"
+	"github.com/SkySock/lode/services/user-service/internal/usecase"
+	"github.com/google/uuid"
+)
+
+type Unmute struct {
+	l  *slog.Logger
+	uc unmuteUsecase
+}
+
+type unmuteUsecase interface {
+	Unmute(ctx context.Context, accountID, profileID, targetID uuid.UUID) error
+}
+
+func NewUnmute(l *slog.Logger, uc unmuteUsecase) *Unmute {
+	return &Unmute{l, uc}
+}
+
+var _ http.Handler = (*Unmute)(nil)
+
+// Unmute godoc
+// @Summary      Отмена скрытия профиля
+// @Description  Возвращает скрытый профиль в списки подписок
+// @Tags         Relation
+// @Security     BearerAuth
+// @Param        profileId path string true "ID профиля аккаунта"
+// @Param        targetId  path string true "ID скрытого профиля"
+// @Success      204
+// @Failure      400  {object}  response.Problem
+// @Failure      401  {object}  response.Problem
+// @Failure      403  {object}  response.Problem "Профиль принадлежит другому аккаунту"
+// @Failure      404  {object}  response.Problem
+// @Failure      500  {object}  response.Problem
+// @Router       /profiles/{profileId}/mutes/{targetId} [delete]
+func (h *Unmute) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	accountID, profileID, targetID, ok := relationFromPath(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.uc.Unmute(r.Context(), accountID, profileID, targetID); err != nil {
+		switch {
+		case errors.Is(err, usecase.ErrProfileNotFound):
+			response.WriteProblem(w, http.StatusNotFound, "Profile not found")
+		case errors.Is(err, usecase.ErrNotProfileOwner):
+			response.WriteProblem(w, http.StatusForbidden, "Profile belongs to another account")
+		default:
+			h.l.Error("Unmute failed", "error", err)
+			response.WriteProblem(w, http.StatusInternalServerError, "Unmute failed")
+		}
+		return
+	}
+
+	h.l.Info("Profile unmuted", "profile_id", profileID, "target_id", targetID)
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,96 @@
+package profile
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+
+	v1 "github.com/SkySock/lode/libs/shared-dto/user/http/v1"
+	"github.com/SkySock/lode/libs/utils/http/response"
+	"github.com/SkySock/lode/services/user-service/internal/entity"
+	"github.com/SkySock/lode/services/user-service/internal/usecase"
+	"github.com/SkySock/lode/services/user-service/internal/validation"
+	"github.com/google/uuid"
+)
+
+type UpdatePrivacy struct {
+	l  *slog.Logger
+	uc updatePrivacyUsecase
+}
+
+type updatePrivacyUsecase interface {
+	UpdatePrivacy(ctx context.Context, accountID, profileID uuid.UUID, update usecase.PrivacyUpdate) (*entity.Profile, error)
+}
+
+func NewUpdatePrivacy(l *slog.Logger, uc updatePrivacyUsecase) *UpdatePrivacy {
+	return &UpdatePrivacy{l, uc}
+}
+
+var _ http.Handler = (*UpdatePrivacy)(nil)
+
+// UpdatePrivacy godoc
+// @Summary      Настройки приватности профиля
+// @Description  Меняет переданные настройки приватности профиля аккаунта. Био и аватар закрытого профиля видны только его подписчикам, как и его подписки и подписчики. Профиль, не доступный для поиска, не попадает в результаты поиска
+// @Tags         Profile
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        profileId path string                  true "ID профиля"
+// @Param        request   body v1.UpdatePrivacyRequest true "Новые значения настроек"
+// @Success      200  {object}  v1.ProfileResponse
+// @Failure      400  {object}  response.Problem
+// @Failure      401  {object}  response.Problem
+// @Failure      403  {object}  response.Problem "Профиль принадлежит другому аккаунту"
+// @Failure      404  {object}  response.Problem
+// @Failure      500  {object}  response.Problem
+// @Router       /profiles/{profileId}/privacy [patch]
+func (h *UpdatePrivacy) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	profileID, ok := profileIDFromPath(w, r)
+	if !ok {
+		return
+	}
+	accountID, ok := accountIDFromContext(w, r)
+	if !ok {
+		return
+	}
+
+	data := v1.UpdatePrivacyRequest{}
+	if err := json.NewDecoder(r.Body).Decode(&data); err != nil {
+		response.WriteProblem(w, http.StatusBadRequest, "Error input data")
+		return
+	}
+	if err := validation.ValidateUpdatePrivacyRequest(&data); err != nil {
+		response.WriteProblem(w, http.StatusBadRequest, fmt.Sprintf("Error validating data: %s", err))
+		return
+	}
+
+	profile, err := h.uc.UpdatePrivacy(r.Context(), accountID, profileID, usecase.PrivacyUpdate{
+		Private:          data.IsPrivate,
+		BioVisibility:    data.BioVisibility,
+		AvatarVisibility: data.AvatarVisibility,
+		Discoverable:     data.Discoverable,
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, usecase.ErrProfileNotFound):
+			response.WriteProblem(w, http.StatusNotFound, "Profile not found")
+		case errors.Is(err, usecase.ErrNotProfileOwner):
+			response.WriteProblem(w, http.StatusForbidden, "Profile belongs to another account")
+		default:
+			h.l.Error("Update privacy failed", "error", err)
+			response.WriteProblem(w, http.StatusInternalServerError, "Update privacy failed")
+		}
+		return
+	}
+
+	h.l.Info("Profile privacy updated", "profile_id", profileID)
+
+	resp := profileResponse(profile)
+	resp.Privacy = privacyResponse(profile.Privacy)
+	if err := response.WriteJSON(w, http.StatusOK, resp); err != nil {
+		h.l.Error("JSON encoding failed", "error", err)
+	}
+}
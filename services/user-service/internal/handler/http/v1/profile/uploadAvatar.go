@@ -0,0 +1,151 @@
+package profile
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+
+	v1 "github.com/SkySock/lode/libs/shared-dto/user/http/v1"
+	"github.com/SkySock/lode/libs/utils/http/response"
+	"github.com/SkySock/lode/services/user-service/internal/avatar"
+	"github.com/SkySock/lode/services/user-service/internal/entity"
+	"github.com/SkySock/lode/services/user-service/internal/usecase"
+	"github.com/google/uuid"
+)
+
+const (
+	avatarField = "avatar"
+	// multipartOverhead leaves room for the boundaries and part headers of
+	// the form around the file.
+	multipartOverhead = 64 << 10
+)
+
+type UploadAvatar struct {
+	l        *slog.Logger
+	uc       uploadAvatarUsecase
+	maxBytes int64
+}
+
+type uploadAvatarUsecase interface {
+	UpdateAvatar(ctx context.Context, accountID, profileID uuid.UUID, image []byte) (*entity.Profile, error)
+}
+
+func NewUploadAvatar(l *slog.Logger, uc uploadAvatarUsecase, maxBytes int64) *UploadAvatar {
+	return &UploadAvatar{l, uc, maxBytes}
+}
+
+var _ http.Handler = (*UploadAvatar)(nil)
+
+// UploadAvatar godoc
+// @Summary      Загрузка аватара
+// @Description  Заменяет аватар профиля аккаунта. Принимаются JPEG, PNG, GIF и WebP; формат определяется по содержимому файла. Изображение обрезается до квадрата, уменьшается до нескольких размеров и сохраняется в JPEG без метаданных
+// @Tags         Profile
+// @Accept       mpfd
+// @Produce      json
+// @Security     BearerAuth
+// @Param        profileId path     string true "ID профиля"
+// @Param        avatar    formData file   true "Изображение"
+// @Success      200  {object}  v1.ProfileResponse
+// @Failure      400  {object}  response.Problem
+// @Failure      401  {object}  response.Problem
+// @Failure      403  {object}  response.Problem "Профиль принадлежит другому аккаунту"
+// @Failure      404  {object}  response.Problem
+// @Failure      413  {object}  response.Problem
+// @Failure      415  {object}  response.Problem
+// @Failure      500  {object}  response.Problem
+// @Router       /profiles/{profileId}/avatar [put]
+func (h *UploadAvatar) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	profileID, ok := profileIDFromPath(w, r)
+	if !ok {
+		return
+	}
+	accountID, ok := accountIDFromContext(w, r)
+	if !ok {
+		return
+	}
+
+	image, ok := h.readImage(w, r)
+	if !ok {
+		return
+	}
+
+	profile, err := h.uc.UpdateAvatar(r.Context(), accountID, profileID, image)
+	if err != nil {
+		switch {
+		case errors.Is(err, usecase.ErrProfileNotFound):
+			response.WriteProblem(w, http.StatusNotFound, "Profile not found")
+		case errors.Is(err, usecase.ErrNotProfileOwner):
+			response.WriteProblem(w, http.StatusForbidden, "Profile belongs to another account")
+		case errors.Is(err, avatar.ErrUnsupportedFormat):
+			response.WriteProblem(w, http.StatusUnsupportedMediaType, "Avatar must be a JPEG, PNG, GIF or WebP image")
+		case errors.Is(err, avatar.ErrTooManyPixels):
+			response.WriteProblem(w, http.StatusRequestEntityTooLarge, "Avatar has too many pixels")
+		case errors.Is(err, avatar.ErrInvalidImage):
+			response.WriteProblem(w, http.StatusBadRequest, "Avatar is not a valid image")
+		default:
+			h.l.Error("Upload avatar failed", "error", err)
+			response.WriteProblem(w, http.StatusInternalServerError, "Upload avatar failed")
+		}
+		return
+	}
+
+	h.l.Info("Avatar uploaded", "profile_id", profileID)
+
+	var resp v1.ProfileResponse = profileResponse(profile)
+	resp.Privacy = privacyResponse(profile.Privacy)
+	if err := response.WriteJSON(w, http.StatusOK, resp); err != nil {
+		h.l.Error("JSON encoding failed", "error", err)
+	}
+}
+
+// readImage reads the avatar field of the multipart form, refusing files over
+// the size limit without reading them whole.
+func (h *UploadAvatar) readImage(w http.ResponseWriter, r *http.Request) ([]byte, bool) {
+	tooLarge := fmt.Sprintf("Avatar must be at most %d bytes", h.maxBytes)
+
+	r.Body = http.MaxBytesReader(w, r.Body, h.maxBytes+multipartOverhead)
+	mr, err := r.MultipartReader()
+	if err != nil {
+		response.WriteProblem(w, http.StatusBadRequest, "Expected a multipart form")
+		return nil, false
+	}
+
+	for {
+		part, err := mr.NextPart()
+		if err != nil {
+			var maxBytesErr *http.MaxBytesError
+			switch {
+			case errors.As(err, &maxBytesErr):
+				response.WriteProblem(w, http.StatusRequestEntityTooLarge, tooLarge)
+			case errors.Is(err, io.EOF):
+				response.WriteProblem(w, http.StatusBadRequest, "Missing avatar file")
+			default:
+				response.WriteProblem(w, http.StatusBadRequest, "Error input data")
+			}
+			return nil, false
+		}
+		if part.FormName() != avatarField {
+			continue
+		}
+
+		data, err := io.ReadAll(io.LimitReader(part, h.maxBytes+1))
+		if err != nil {
+			var maxBytesErr *http.MaxBytesError
+			if errors.As(err, &maxBytesErr) {
+				response.WriteProblem(w, http.StatusRequestEntityTooLarge, tooLarge)
+			} else {
+				response.WriteProblem(w, http.StatusBadRequest, "Error input data")
+			}
+			return nil, false
+		}
+		if int64(len(data)) > h.maxBytes {
+			response.WriteProblem(w, http.StatusRequestEntityTooLarge, tooLarge)
+			return nil, false
+		}
+
+		return data, true
+	}
+}
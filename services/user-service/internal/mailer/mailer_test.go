@@ -0,0 +1,29 @@
+package mailer
+
+import (
+	"mime"
+	"net/mail"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCompose(t *testing.T) {
+	msg := Message{To: "alice@example.com", Subject: "Смена email", Body: "Hello\n"}
+
+	m, err := mail.ReadMessage(strings.NewReader(string(compose("no-reply@lode.local", msg, time.Now()))))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	subject, err := new(mime.WordDecoder).DecodeHeader(m.Header.Get("Subject"))
+	if err != nil || subject != msg.Subject {
+		t.Errorf("Wrong subject: %q, %v", subject, err)
+	}
+	if m.Header.Get("To") != msg.To || m.Header.Get("From") != "no-reply@lode.local" {
+		t.Errorf("Wrong addresses: %v", m.Header)
+	}
+	if _, err := m.Header.Date(); err != nil {
+		t.Errorf("Wrong date: %v", err)
+	}
+}
@@ -0,0 +1,72 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	SignInReasonIncorrectPassword     = "incorrect_password"
+	SignInReasonUsernameNotFound      = "username_not_found"
+	SignInReasonEmailNotFound         = "email_not_found"
+	SignInReasonAccountLocked         = "account_locked"
+	SignInReasonAccountDeleted        = "account_deleted"
+	SignInReasonPasswordResetRequired = "password_reset_required"
+	SignInReasonInternal              = "internal"
+)
+
+type AuthMetrics struct {
+	signUps         prometheus.Counter
+	signInSuccesses prometheus.Counter
+	signInFailures  *prometheus.CounterVec
+	sessionsCreated prometheus.Counter
+	sessionsRevoked prometheus.Counter
+}
+
+func NewAuthMetrics(reg prometheus.Registerer) *AuthMetrics {
+	m := &AuthMetrics{
+		signUps: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "user_sign_ups_total",
+			Help: "Total number of registered accounts.",
+		}),
+		signInSuccesses: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "user_sign_in_successes_total",
+			Help: "Total number of successful sign-ins.",
+		}),
+		signInFailures: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "user_sign_in_failures_total",
+			Help: "Total number of failed sign-ins by reason.",
+		}, []string{"reason"}),
+		sessionsCreated: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "user_sessions_created_total",
+			Help: "Total number of created refresh sessions.",
+		}),
+		sessionsRevoked: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "user_sessions_revoked_total",
+			Help: "Total number of revoked refresh sessions.",
+		}),
+	}
+
+	reg.MustRegister(m.signUps, m.signInSuccesses, m.signInFailures, m.sessionsCreated, m.sessionsRevoked)
+
+	return m
+}
+
+func (m *AuthMetrics) SignUp() {
+	m.signUps.Inc()
+}
+
+func (m *AuthMetrics) SignInSucceeded() {
+	m.signInSuccesses.Inc()
+}
+
+func (m *AuthMetrics) SignInFailed(reason string) {
+	m.signInFailures.WithLabelValues(reason).Inc()
+}
+
+func (m *AuthMetrics) SessionCreated() {
+	m.sessionsCreated.Inc()
+}
+
+func (m *AuthMetrics) SessionRevoked() {
+	m.sessionsRevoked.Inc()
+}
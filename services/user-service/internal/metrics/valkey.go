@@ -0,0 +1,121 @@
+package metrics
+
+import (
+	"context"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/valkey-io/valkey-go"
+)
+
+type valkeyMetrics struct {
+	commands *prometheus.CounterVec
+	errors   *prometheus.CounterVec
+	duration *prometheus.HistogramVec
+}
+
+type instrumentedClient struct {
+	valkey.Client
+	m *valkeyMetrics
+}
+
+type instrumentedDedicatedClient struct {
+	valkey.DedicatedClient
+	m *valkeyMetrics
+}
+
+// InstrumentValkey wraps the client so that every Do/DoMulti call, including ones made on
+// dedicated connections, is counted and timed by command name.
+func InstrumentValkey(client valkey.Client, reg prometheus.Registerer) valkey.Client {
+	m := &valkeyMetrics{
+		commands: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "valkey_commands_total",
+			Help: "Total number of Valkey commands sent.",
+		}, []string{"command"}),
+		errors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "valkey_command_errors_total",
+			Help: "Total number of Valkey commands that failed, nil replies excluded.",
+		}, []string{"command"}),
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "valkey_command_duration_seconds",
+			Help:    "Valkey command round-trip latency.",
+			Buckets: []float64{.0005, .001, .0025, .005, .01, .025, .05, .1, .25, .5, 1},
+		}, []string{"command"}),
+	}
+
+	reg.MustRegister(m.commands, m.errors, m.duration)
+
+	return &instrumentedClient{Client: client, m: m}
+}
+
+func (c *instrumentedClient) Do(ctx context.Context, cmd valkey.Completed) valkey.ValkeyResult {
+	return c.m.do(ctx, c.Client, cmd)
+}
+
+func (c *instrumentedClient) DoMulti(ctx context.Context, multi ...valkey.Completed) []valkey.ValkeyResult {
+	return c.m.doMulti(ctx, c.Client, multi)
+}
+
+func (c *instrumentedClient) Dedicated(fn func(valkey.DedicatedClient) error) error {
+	return c.Client.Dedicated(func(dc valkey.DedicatedClient) error {
+		return fn(&instrumentedDedicatedClient{DedicatedClient: dc, m: c.m})
+	})
+}
+
+func (c *instrumentedClient) Dedicate() (valkey.DedicatedClient, func()) {
+	dc, cancel := c.Client.Dedicate()
+	return &instrumentedDedicatedClient{DedicatedClient: dc, m: c.m}, cancel
+}
+
+func (c *instrumentedDedicatedClient) Do(ctx context.Context, cmd valkey.Completed) valkey.ValkeyResult {
+	return c.m.do(ctx, c.DedicatedClient, cmd)
+}
+
+func (c *instrumentedDedicatedClient) DoMulti(ctx context.Context, multi ...valkey.Completed) []valkey.ValkeyResult {
+	return c.m.doMulti(ctx, c.DedicatedClient, multi)
+}
+
+func (m *valkeyMetrics) do(ctx context.Context, c valkey.CoreClient, cmd valkey.Completed) valkey.ValkeyResult {
+	// The command is recycled by Do, so its name has to be read beforehand.
+	name := commandName(&cmd)
+	startTime := time.Now()
+
+	resp := c.Do(ctx, cmd)
+
+	m.observe(name, time.Since(startTime), resp.Error())
+
+	return resp
+}
+
+func (m *valkeyMetrics) doMulti(ctx context.Context, c valkey.CoreClient, multi []valkey.Completed) []valkey.ValkeyResult {
+	names := make([]string, len(multi))
+	for i := range multi {
+		names[i] = commandName(&multi[i])
+	}
+	startTime := time.Now()
+
+	resps := c.DoMulti(ctx, multi...)
+
+	duration := time.Since(startTime)
+	for i, resp := range resps {
+		m.observe(names[i], duration, resp.Error())
+	}
+
+	return resps
+}
+
+func (m *valkeyMetrics) observe(name string, duration time.Duration, err error) {
+	m.commands.WithLabelValues(name).Inc()
+	m.duration.WithLabelValues(name).Observe(duration.Seconds())
+	if err != nil && !valkey.IsValkeyNil(err) {
+		m.errors.WithLabelValues(name).Inc()
+	}
+}
+
+func commandName(cmd *valkey.Completed) string {
+	parts := cmd.Commands()
+	if len(parts) == 0 {
+		return "unknown"
+	}
+	return parts[0]
+}
@@ -4,6 +4,8 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/SkySock/lode/services/user-service/internal/db"
 	"github.com/SkySock/lode/services/user-service/internal/entity"
@@ -48,45 +50,192 @@ func (r *accountRepository) Create(ctx context.Context, qe db.QueryExecutor, acc
 }
 
 func (r *accountRepository) GetById(ctx context.Context, qe db.QueryExecutor, id uuid.UUID) (*entity.Account, error) {
-	query := `SELECT id, username, email, password_hash, created_at FROM account WHERE id = $1`
-	var account entity.Account
+	query := `SELECT ` + accountColumns + ` FROM account WHERE id = $1`
+
+	return scanAccount(qe.QueryRow(ctx, query, id))
+}
+
+func (r *accountRepository) GetByUsername(ctx context.Context, qe db.QueryExecutor, username string) (*entity.Account, error) {
+	query := `SELECT ` + accountColumns + ` FROM account WHERE username = $1`
+
+	return scanAccount(qe.QueryRow(ctx, query, username))
+}
+
+func (r *accountRepository) GetByEmail(ctx context.Context, qe db.QueryExecutor, email string) (*entity.Account, error) {
+	query := `SELECT ` + accountColumns + ` FROM account WHERE email = $1`
 
-	err := qe.QueryRow(ctx, query, id).Scan(&account.ID, &account.Username, &account.Email, &account.PasswordHash, &account.CreatedAt)
+	return scanAccount(qe.QueryRow(ctx, query, email))
+}
+
+// Search returns accounts whose username or email starts with prefix, ordered
+// by username and starting after the username after.
+func (r *accountRepository) Search(ctx context.Context, qe db.QueryExecutor, prefix, after string, limit int) ([]*entity.Account, error) {
+	query := `
+		SELECT ` + accountColumns + `
+			FROM account
+			WHERE (username LIKE $1 OR email LIKE $1) AND username > $2
+			ORDER BY username
+			LIMIT $3
+	`
+
+	rows, err := qe.Query(ctx, query, escapeLike(prefix)+"%", after, limit)
 	if err != nil {
-		if errors.Is(err, pgx.ErrNoRows) {
-			return nil, ErrNotFound
+		return nil, fmt.Errorf("repo: query execution failed: %w", err)
+	}
+	defer rows.Close()
+
+	var accounts []*entity.Account
+	for rows.Next() {
+		account, err := scanAccount(rows)
+		if err != nil {
+			return nil, err
 		}
-		return nil, fmt.Errorf("repo: get user failed: %w", err)
+
+		accounts = append(accounts, account)
 	}
 
-	return &account, nil
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("repo: rows iteration error: %w", err)
+	}
+
+	return accounts, nil
 }
 
-func (r *accountRepository) GetByUsername(ctx context.Context, qe db.QueryExecutor, username string) (*entity.Account, error) {
-	query := `SELECT id, username, email, password_hash, created_at FROM account WHERE username = $1`
-	var account entity.Account
+// SetLock locks the account when lockedAt is set and unlocks it otherwise.
+func (r *accountRepository) SetLock(ctx context.Context, qe db.QueryExecutor, id uuid.UUID, lockedAt *time.Time, reason string) error {
+	query := `UPDATE account SET locked_at = $2, lock_reason = $3 WHERE id = $1`
+
+	return execAccountUpdate(ctx, qe, query, id, lockedAt, reason)
+}
+
+func (r *accountRepository) SetPasswordResetRequired(ctx context.Context, qe db.QueryExecutor, id uuid.UUID, required bool) error {
+	query := `UPDATE account SET password_reset_required = $2 WHERE id = $1`
+
+	return execAccountUpdate(ctx, qe, query, id, required)
+}
+
+// UpdatePassword also clears a pending password reset.
+func (r *accountRepository) UpdatePassword(ctx context.Context, qe db.QueryExecutor, id uuid.UUID, passwordHash string) error {
+	query := `UPDATE account SET password_hash = $2, password_reset_required = false WHERE id = $1`
+
+	return execAccountUpdate(ctx, qe, query, id, passwordHash)
+}
+
+// SetDeleted marks the account as deleted when deletedAt is set and restores
+// it otherwise.
+func (r *accountRepository) SetDeleted(ctx context.Context, qe db.QueryExecutor, id uuid.UUID, deletedAt *time.Time) error {
+	query := `UPDATE account SET deleted_at = $2 WHERE id = $1`
+
+	return execAccountUpdate(ctx, qe, query, id, deletedAt)
+}
 
-	err := qe.QueryRow(ctx, query, username).Scan(&account.ID, &account.Username, &account.Email, &account.PasswordHash, &account.CreatedAt)
+// LockDeleted locks up to limit accounts deleted before the given time.
+// Accounts locked by another transaction are skipped, so that several
+// instances can purge at the same time.
+func (r *accountRepository) LockDeleted(ctx context.Context, qe db.QueryExecutor, before time.Time, limit int) ([]uuid.UUID, error) {
+	query := `
+		SELECT id
+			FROM account
+			WHERE deleted_at < $1
+			ORDER BY deleted_at
+			LIMIT $2
+			FOR UPDATE SKIP LOCKED
+	`
+
+	rows, err := qe.Query(ctx, query, before, limit)
 	if err != nil {
-		if errors.Is(err, pgx.ErrNoRows) {
-			return nil, ErrNotFound
+		return nil, fmt.Errorf("repo: query execution failed: %w", err)
+	}
+	defer rows.Close()
+
+	var ids []uuid.UUID
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("repo: scan account id failed: %w", err)
 		}
-		return nil, fmt.Errorf("repo: get user failed: %w", err)
+		ids = append(ids, id)
 	}
 
-	return &account, nil
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("repo: rows iteration error: %w", err)
+	}
+
+	return ids, nil
 }
 
-func (r *accountRepository) GetByEmail(ctx context.Context, qe db.QueryExecutor, email string) (*entity.Account, error) {
-	query := `SELECT id, username, email, password_hash, created_at FROM account WHERE email = $1`
+// Delete removes the account; its profiles, roles and auth events go with it.
+func (r *accountRepository) Delete(ctx context.Context, qe db.QueryExecutor, id uuid.UUID) error {
+	query := `DELETE FROM account WHERE id = $1`
+
+	return execAccountUpdate(ctx, qe, query, id)
+}
+
+// UpdateUsername returns ErrDuplicate when the username is taken.
+func (r *accountRepository) UpdateUsername(ctx context.Context, qe db.QueryExecutor, id uuid.UUID, username string, changedAt time.Time) error {
+	query := `UPDATE account SET username = $2, username_changed_at = $3 WHERE id = $1`
+
+	return execUniqueUpdate(ctx, qe, query, id, username, changedAt)
+}
+
+// UpdateEmail returns ErrDuplicate when the email is taken.
+func (r *accountRepository) UpdateEmail(ctx context.Context, qe db.QueryExecutor, id uuid.UUID, email string) error {
+	query := `UPDATE account SET email = $2 WHERE id = $1`
+
+	return execUniqueUpdate(ctx, qe, query, id, email)
+}
+
+const accountColumns = `id, username, email, password_hash, created_at, locked_at, lock_reason, password_reset_required, deleted_at, username_changed_at`
+
+func scanAccount(row pgx.Row) (*entity.Account, error) {
 	var account entity.Account
 
-	err := qe.QueryRow(ctx, query, email).Scan(&account.ID, &account.Username, &account.Email, &account.PasswordHash, &account.CreatedAt)
+	err := row.Scan(
+		&account.ID,
+		&account.Username,
+		&account.Email,
+		&account.PasswordHash,
+		&account.CreatedAt,
+		&account.LockedAt,
+		&account.LockReason,
+		&account.PasswordResetRequired,
+		&account.DeletedAt,
+		&account.UsernameChangedAt,
+	)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return nil, ErrNotFound
 		}
 		return nil, fmt.Errorf("repo: get user failed: %w", err)
 	}
+
 	return &account, nil
 }
+
+func execAccountUpdate(ctx context.Context, qe db.QueryExecutor, query string, args ...any) error {
+	tag, err := qe.Exec(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("repo: update user failed: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+func execUniqueUpdate(ctx context.Context, qe db.QueryExecutor, query string, args ...any) error {
+	err := execAccountUpdate(ctx, qe, query, args...)
+
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+		return ErrDuplicate
+	}
+
+	return err
+}
+
+// escapeLike escapes the wildcards of a LIKE pattern.
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
@@ -0,0 +1,26 @@
+package repository
+
+import "testing"
+
+func TestEscapeLike(t *testing.T) {
+	testCases := []struct {
+		name   string
+		value  string
+		expect string
+	}{
+		{"Plain", "alice", "alice"},
+		{"Percent", "100%", `100\%`},
+		{"Underscore", "al_ce", `al\_ce`},
+		{"Backslash", `al\ce`, `al\\ce`},
+		{"Escaped wildcard", `\%`, `\\\%`},
+		{"Empty", "", ""},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := escapeLike(tc.value); got != tc.expect {
+				t.Errorf("Wrong escaped value: got %q, want %q", got, tc.expect)
+			}
+		})
+	}
+}
@@ -0,0 +1,52 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/SkySock/lode/services/user-service/internal/db"
+	"github.com/SkySock/lode/services/user-service/internal/entity"
+	"github.com/google/uuid"
+)
+
+type auditRepository struct{}
+
+func NewAuditRepository() AuditRepository {
+	return &auditRepository{}
+}
+
+func (r *auditRepository) Create(ctx context.Context, qe db.QueryExecutor, entry *entity.AuditEntry) error {
+	query := `
+		INSERT INTO audit_log (id, actor_id, action, target_id, details)
+			VALUES ($1, $2, $3, $4, $5)
+	`
+
+	id, err := uuid.NewV7()
+	if err != nil {
+		return err
+	}
+
+	details := entry.Details
+	if details == nil {
+		details = map[string]any{}
+	}
+
+	if _, err := qe.Exec(ctx, query, id, entry.ActorID, entry.Action, entry.TargetID, details); err != nil {
+		return fmt.Errorf("repo: create audit entry failed: %w", err)
+	}
+	entry.ID = id
+
+	return nil
+}
+
+// ScrubTargets removes the details of entries about the given targets, which
+// may hold personal data such as old profile fields.
+func (r *auditRepository) ScrubTargets(ctx context.Context, qe db.QueryExecutor, targetIDs []uuid.UUID) error {
+	query := `UPDATE audit_log SET details = '{}' WHERE target_id = ANY($1)`
+
+	if _, err := qe.Exec(ctx, query, targetIDs); err != nil {
+		return fmt.Errorf("repo: scrub audit entries failed: %w", err)
+	}
+
+	return nil
+}
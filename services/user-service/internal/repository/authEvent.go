@@ -0,0 +1,121 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/SkySock/lode/services/user-service/internal/db"
+	"github.com/SkySock/lode/services/user-service/internal/entity"
+	"github.com/google/uuid"
+)
+
+type authEventRepository struct{}
+
+func NewAuthEventRepository() AuthEventRepository {
+	return &authEventRepository{}
+}
+
+func (r *authEventRepository) Create(ctx context.Context, qe db.QueryExecutor, event *entity.AuthEvent) error {
+	query := `
+		INSERT INTO auth_event (id, account_id, event_type, ip, user_agent, outcome, reason)
+			VALUES ($1, $2, $3, $4, $5, $6, $7)
+			RETURNING created_at
+	`
+
+	id, err := uuid.NewV7()
+	if err != nil {
+		return err
+	}
+
+	if err := qe.QueryRow(
+		ctx,
+		query,
+		id,
+		event.AccountID,
+		event.Type,
+		event.IP,
+		event.UserAgent,
+		event.Outcome,
+		event.Reason,
+	).Scan(&event.CreatedAt); err != nil {
+		return fmt.Errorf("repo: create auth event failed: %w", err)
+	}
+	event.ID = id
+
+	return nil
+}
+
+// List returns the events matching filter, newest first. Ids are UUIDv7, so
+// ordering by id orders by time and lets Before work as a cursor.
+func (r *authEventRepository) List(ctx context.Context, qe db.QueryExecutor, filter AuthEventFilter, limit int) ([]*entity.AuthEvent, error) {
+	var (
+		conds []string
+		args  []any
+	)
+	where := func(cond string, arg any) {
+		args = append(args, arg)
+		conds = append(conds, fmt.Sprintf(cond, len(args)))
+	}
+
+	if filter.AccountID != nil {
+		where("account_id = $%d", *filter.AccountID)
+	}
+	if filter.Type != "" {
+		where("event_type = $%d", filter.Type)
+	}
+	if filter.Outcome != "" {
+		where("outcome = $%d", filter.Outcome)
+	}
+	if filter.IP != "" {
+		where("ip = $%d", filter.IP)
+	}
+	if !filter.From.IsZero() {
+		where("created_at >= $%d", filter.From)
+	}
+	if !filter.To.IsZero() {
+		where("created_at < $%d", filter.To)
+	}
+	if filter.Before != nil {
+		where("id < $%d", *filter.Before)
+	}
+
+	query := `SELECT id, account_id, event_type, ip, user_agent, outcome, reason, created_at FROM auth_event`
+	if len(conds) > 0 {
+		query += " WHERE " + strings.Join(conds, " AND ")
+	}
+	args = append(args, limit)
+	query += fmt.Sprintf(" ORDER BY id DESC LIMIT $%d", len(args))
+
+	rows, err := qe.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("repo: query execution failed: %w", err)
+	}
+	defer rows.Close()
+
+	var events []*entity.AuthEvent
+	for rows.Next() {
+		event := new(entity.AuthEvent)
+
+		if err := rows.Scan(
+			&event.ID,
+			&event.AccountID,
+			&event.Type,
+			&event.IP,
+			&event.UserAgent,
+			&event.Outcome,
+			&event.Reason,
+			&event.CreatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("repo: scan auth event failed: %w", err)
+		}
+
+		events = append(events, event)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("repo: rows iteration error: %w", err)
+	}
+
+	return events, nil
+}
@@ -0,0 +1,46 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/valkey-io/valkey-go"
+)
+
+const denylistPrefix = "access_denylist"
+
+type tokenDenylist struct {
+	client valkey.Client
+}
+
+func NewTokenDenylist(client valkey.Client) TokenDenylist {
+	return &tokenDenylist{
+		client: client,
+	}
+}
+
+// Add denies the token for ttl, which should cover the rest of its lifetime;
+// after that the token is rejected as expired anyway.
+func (d *tokenDenylist) Add(ctx context.Context, jti string, ttl time.Duration) error {
+	if ttl <= 0 {
+		return nil
+	}
+
+	key := fmt.Sprintf("%s:%s", denylistPrefix, jti)
+	cmd := d.client.B().Set().Key(key).Value("1").Ex(ttl).Build()
+
+	return d.client.Do(ctx, cmd).Error()
+}
+
+func (d *tokenDenylist) Contains(ctx context.Context, jti string) (bool, error) {
+	key := fmt.Sprintf("%s:%s", denylistPrefix, jti)
+	cmd := d.client.B().Exists().Key(key).Build()
+
+	n, err := d.client.Do(ctx, cmd).AsInt64()
+	if err != nil {
+		return false, err
+	}
+
+	return n > 0, nil
+}
@@ -0,0 +1,57 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/SkySock/lode/services/user-service/internal/entity"
+	"github.com/valkey-io/valkey-go"
+)
+
+const emailChangePrefix = "email_change"
+
+type emailChangeRepository struct {
+	client valkey.Client
+}
+
+func NewEmailChangeRepository(client valkey.Client) EmailChangeRepository {
+	return &emailChangeRepository{
+		client: client,
+	}
+}
+
+// Save keeps the change under the hash of its confirmation token for ttl.
+func (r *emailChangeRepository) Save(ctx context.Context, tokenHash string, change *entity.EmailChange, ttl time.Duration) error {
+	bytes, err := json.Marshal(change)
+	if err != nil {
+		return err
+	}
+
+	key := fmt.Sprintf("%s:%s", emailChangePrefix, tokenHash)
+	cmd := r.client.B().Set().Key(key).Value(string(bytes)).Ex(ttl).Build()
+
+	return r.client.Do(ctx, cmd).Error()
+}
+
+// Take returns the change and removes it, so that a token can be used once.
+func (r *emailChangeRepository) Take(ctx context.Context, tokenHash string) (*entity.EmailChange, error) {
+	key := fmt.Sprintf("%s:%s", emailChangePrefix, tokenHash)
+	cmd := r.client.B().Getdel().Key(key).Build()
+
+	data, err := r.client.Do(ctx, cmd).ToString()
+	if err != nil {
+		if valkey.IsValkeyNil(err) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+
+	var change entity.EmailChange
+	if err := json.Unmarshal([]byte(data), &change); err != nil {
+		return nil, fmt.Errorf("repo: decode email change failed: %w", err)
+	}
+
+	return &change, nil
+}
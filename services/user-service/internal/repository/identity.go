@@ -0,0 +1,122 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/SkySock/lode/services/user-service/internal/db"
+	"github.com/SkySock/lode/services/user-service/internal/entity"
+	"github.com/google/uuid"
+)
+
+type identityRepository struct{}
+
+func NewIdentityRepository() IdentityRepository {
+	return &identityRepository{}
+}
+
+// IsReserved reports whether the username is reserved for an account other
+// than accountID.
+func (r *identityRepository) IsReserved(ctx context.Context, qe db.QueryExecutor, username string, accountID uuid.UUID) (bool, error) {
+	query := `
+		SELECT EXISTS (
+			SELECT 1 FROM username_reservation
+				WHERE username = $1 AND account_id <> $2 AND reserved_until > now()
+		)
+	`
+
+	var reserved bool
+	if err := qe.QueryRow(ctx, query, username, accountID).Scan(&reserved); err != nil {
+		return false, fmt.Errorf("repo: check username reservation failed: %w", err)
+	}
+
+	return reserved, nil
+}
+
+// Reserve keeps the username for the account until the given time, taking
+// over an expired reservation of another account.
+func (r *identityRepository) Reserve(ctx context.Context, qe db.QueryExecutor, username string, accountID uuid.UUID, until time.Time) error {
+	query := `
+		INSERT INTO username_reservation (username, account_id, reserved_until)
+			VALUES ($1, $2, $3)
+			ON CONFLICT (username) DO UPDATE
+				SET account_id = EXCLUDED.account_id, reserved_until = EXCLUDED.reserved_until
+	`
+
+	if _, err := qe.Exec(ctx, query, username, accountID, until); err != nil {
+		return fmt.Errorf("repo: reserve username failed: %w", err)
+	}
+
+	return nil
+}
+
+func (r *identityRepository) Release(ctx context.Context, qe db.QueryExecutor, username string) error {
+	query := `DELETE FROM username_reservation WHERE username = $1`
+
+	if _, err := qe.Exec(ctx, query, username); err != nil {
+		return fmt.Errorf("repo: release username failed: %w", err)
+	}
+
+	return nil
+}
+
+func (r *identityRepository) AddChange(ctx context.Context, qe db.QueryExecutor, change *entity.IdentityChange) error {
+	query := `
+		INSERT INTO identity_change (id, account_id, kind, old_value, new_value)
+			VALUES ($1, $2, $3, $4, $5)
+			RETURNING changed_at
+	`
+
+	id, err := uuid.NewV7()
+	if err != nil {
+		return err
+	}
+
+	if err := qe.QueryRow(ctx, query, id, change.AccountID, change.Kind, change.OldValue, change.NewValue).
+		Scan(&change.ChangedAt); err != nil {
+		return fmt.Errorf("repo: create identity change failed: %w", err)
+	}
+	change.ID = id
+
+	return nil
+}
+
+// ListChanges returns the identity changes of the account, newest first.
+func (r *identityRepository) ListChanges(ctx context.Context, qe db.QueryExecutor, accountID uuid.UUID) ([]*entity.IdentityChange, error) {
+	query := `
+		SELECT id, account_id, kind, old_value, new_value, changed_at
+			FROM identity_change
+			WHERE account_id = $1
+			ORDER BY id DESC
+	`
+
+	rows, err := qe.Query(ctx, query, accountID)
+	if err != nil {
+		return nil, fmt.Errorf("repo: query execution failed: %w", err)
+	}
+	defer rows.Close()
+
+	var changes []*entity.IdentityChange
+	for rows.Next() {
+		change := new(entity.IdentityChange)
+		if err := rows.Scan(
+			&change.ID,
+			&change.AccountID,
+			&change.Kind,
+			&change.OldValue,
+			&change.NewValue,
+			&change.ChangedAt,
+		); err != nil {
+			return nil, fmt.Errorf("repo: scan identity change failed: %w", err)
+		}
+
+		changes = append(changes, change)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("repo: rows iteration error: %w", err)
+	}
+
+	return changes, nil
+}
@@ -0,0 +1,91 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/SkySock/lode/services/user-service/internal/db"
+	"github.com/SkySock/lode/services/user-service/internal/entity"
+	"github.com/google/uuid"
+)
+
+type outboxRepository struct{}
+
+func NewOutboxRepository() OutboxRepository {
+	return &outboxRepository{}
+}
+
+func (r *outboxRepository) Add(ctx context.Context, qe db.QueryExecutor, msg *entity.OutboxMessage) error {
+	query := `
+		INSERT INTO outbox (id, event_type, event_key, payload)
+			VALUES ($1, $2, $3, $4)
+			RETURNING created_at
+	`
+
+	id, err := uuid.NewV7()
+	if err != nil {
+		return err
+	}
+
+	if err := qe.QueryRow(ctx, query, id, msg.Type, msg.Key, msg.Payload).Scan(&msg.CreatedAt); err != nil {
+		return fmt.Errorf("repo: create outbox message failed: %w", err)
+	}
+	msg.ID = id
+
+	return nil
+}
+
+// LockPending locks up to limit of the oldest messages. Messages locked by
+// another transaction are skipped, so that several relays can run at once.
+func (r *outboxRepository) LockPending(ctx context.Context, qe db.QueryExecutor, limit int) ([]*entity.OutboxMessage, error) {
+	query := `
+		SELECT id, event_type, event_key, payload, created_at
+			FROM outbox
+			ORDER BY id
+			LIMIT $1
+			FOR UPDATE SKIP LOCKED
+	`
+
+	rows, err := qe.Query(ctx, query, limit)
+	if err != nil {
+		return nil, fmt.Errorf("repo: query execution failed: %w", err)
+	}
+	defer rows.Close()
+
+	var messages []*entity.OutboxMessage
+	for rows.Next() {
+		msg := new(entity.OutboxMessage)
+		if err := rows.Scan(&msg.ID, &msg.Type, &msg.Key, &msg.Payload, &msg.CreatedAt); err != nil {
+			return nil, fmt.Errorf("repo: scan outbox message failed: %w", err)
+		}
+
+		messages = append(messages, msg)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("repo: rows iteration error: %w", err)
+	}
+
+	return messages, nil
+}
+
+func (r *outboxRepository) Delete(ctx context.Context, qe db.QueryExecutor, ids []uuid.UUID) error {
+	query := `DELETE FROM outbox WHERE id = ANY($1)`
+
+	if _, err := qe.Exec(ctx, query, ids); err != nil {
+		return fmt.Errorf("repo: delete outbox messages failed: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteByKeys removes the pending messages about the entities with the keys.
+func (r *outboxRepository) DeleteByKeys(ctx context.Context, qe db.QueryExecutor, keys []string) error {
+	query := `DELETE FROM outbox WHERE event_key = ANY($1)`
+
+	if _, err := qe.Exec(ctx, query, keys); err != nil {
+		return fmt.Errorf("repo: delete outbox messages failed: %w", err)
+	}
+
+	return nil
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strings"
 
 	"github.com/SkySock/lode/services/user-service/internal/db"
 	"github.com/SkySock/lode/services/user-service/internal/entity"
@@ -20,8 +21,8 @@ func NewProfileRepository() ProfileRepository {
 
 func (r *profileRepository) Create(ctx context.Context, qe db.QueryExecutor, profile *entity.Profile) (uuid.UUID, error) {
 	query := `
-		INSERT INTO profile(id, user_id, profile_name, display_name, bio, avatar) 
-			VALUES ($1, $2, $3, $4, $5, $6)
+		INSERT INTO profile(id, user_id, profile_name, display_name, bio, avatar, is_default)
+			VALUES ($1, $2, $3, $4, $5, $6, $7)
 	`
 
 	newId, err := uuid.NewV7()
@@ -38,6 +39,7 @@ func (r *profileRepository) Create(ctx context.Context, qe db.QueryExecutor, pro
 		profile.DisplayName,
 		profile.Bio,
 		profile.Avatar,
+		profile.IsDefault,
 	); err != nil {
 		var pgErr *pgconn.PgError
 		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
@@ -51,65 +53,68 @@ func (r *profileRepository) Create(ctx context.Context, qe db.QueryExecutor, pro
 
 func (r *profileRepository) GetByID(ctx context.Context, qe db.QueryExecutor, id uuid.UUID) (*entity.Profile, error) {
 	query := `
-		SELECT id, user_id, profile_name, display_name, bio, avatar, created_at
-			FROM profile 
-			WHERE id = $1
+		SELECT ` + profileColumns + `
+			FROM profile p
+			JOIN account a ON a.id = p.user_id AND a.deleted_at IS NULL
+			WHERE p.id = $1
 	`
 
-	var profile entity.Profile
-	err := qe.QueryRow(ctx, query, id).
-		Scan(
-			&profile.ID,
-			&profile.UserID,
-			&profile.ProfileName,
-			&profile.DisplayName,
-			&profile.Bio,
-			&profile.Avatar,
-			&profile.CreatedAt,
-		)
-	if err != nil {
-		if errors.Is(err, pgx.ErrNoRows) {
-			return nil, ErrNotFound
-		}
-		return nil, fmt.Errorf("repo: get user profile failed: %w", err)
-	}
-
-	return &profile, nil
+	return scanProfile(qe.QueryRow(ctx, query, id))
 }
 
 func (r *profileRepository) GetByProfileName(ctx context.Context, qe db.QueryExecutor, name string) (*entity.Profile, error) {
 	query := `
-		SELECT id, user_id, profile_name, display_name, bio, avatar, created_at
-			FROM profile 
-			WHERE profile_name = $1
+		SELECT ` + profileColumns + `
+			FROM profile p
+			JOIN account a ON a.id = p.user_id AND a.deleted_at IS NULL
+			WHERE p.profile_name = $1
 	`
 
-	var profile entity.Profile
+	return scanProfile(qe.QueryRow(ctx, query, name))
+}
 
-	if err := qe.QueryRow(ctx, query, name).
-		Scan(
-			&profile.ID,
-			&profile.UserID,
-			&profile.ProfileName,
-			&profile.DisplayName,
-			&profile.Bio,
-			&profile.Avatar,
-			&profile.CreatedAt,
-		); err != nil {
-		if errors.Is(err, pgx.ErrNoRows) {
-			return nil, ErrNotFound
+// GetDefault returns the profile the account signs in with.
+func (r *profileRepository) GetDefault(ctx context.Context, qe db.QueryExecutor, userID uuid.UUID) (*entity.Profile, error) {
+	query := `SELECT ` + profileColumns + ` FROM profile p WHERE p.user_id = $1 AND p.is_default`
+
+	return scanProfile(qe.QueryRow(ctx, query, userID))
+}
+
+func (r *profileRepository) GetByIDs(ctx context.Context, qe db.QueryExecutor, ids []uuid.UUID) ([]*entity.Profile, error) {
+	query := `
+		SELECT ` + profileColumns + `
+			FROM profile p
+			JOIN account a ON a.id = p.user_id AND a.deleted_at IS NULL
+			WHERE p.id = ANY($1)
+	`
+	rows, err := qe.Query(ctx, query, ids)
+	if err != nil {
+		return nil, fmt.Errorf("repo: query execution failed: %w", err)
+	}
+	defer rows.Close()
+
+	profiles := make([]*entity.Profile, 0, len(ids))
+	for rows.Next() {
+		profile, err := scanProfile(rows)
+		if err != nil {
+			return nil, err
 		}
-		return nil, fmt.Errorf("repo: get user profile failed: %w", err)
+
+		profiles = append(profiles, profile)
 	}
 
-	return &profile, nil
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("repo: rows iteration error: %w", err)
+	}
+
+	return profiles, nil
 }
 
 func (r *profileRepository) GetAllByUserID(ctx context.Context, qe db.QueryExecutor, userID uuid.UUID) ([]*entity.Profile, error) {
 	query := `
-		SELECT id, user_id, profile_name, display_name, bio, avatar, created_at
-			FROM profile
-			WHERE user_id = $1
+		SELECT ` + profileColumns + `
+			FROM profile p
+			WHERE p.user_id = $1
 	`
 	rows, err := qe.Query(ctx, query, userID)
 	if err != nil {
@@ -119,18 +124,9 @@ func (r *profileRepository) GetAllByUserID(ctx context.Context, qe db.QueryExecu
 
 	var profiles []*entity.Profile
 	for rows.Next() {
-		profile := new(entity.Profile)
-
-		if err := rows.Scan(
-			&profile.ID,
-			&profile.UserID,
-			&profile.ProfileName,
-			&profile.DisplayName,
-			&profile.Bio,
-			&profile.Avatar,
-			&profile.CreatedAt,
-		); err != nil {
-			return nil, fmt.Errorf("repo: scan failed for user %s: %w", userID, err)
+		profile, err := scanProfile(rows)
+		if err != nil {
+			return nil, err
 		}
 
 		profiles = append(profiles, profile)
@@ -142,3 +138,142 @@ func (r *profileRepository) GetAllByUserID(ctx context.Context, qe db.QueryExecu
 
 	return profiles, nil
 }
+
+// Search returns discoverable profiles whose name or display name starts with
+// term or is similar to it, ordered by ID and starting after the ID after. An
+// empty term lists all of them. When viewerID is set, profiles it blocks or is
+// blocked by are left out.
+func (r *profileRepository) Search(ctx context.Context, qe db.QueryExecutor, term string, viewerID, after *uuid.UUID, limit int) ([]*entity.Profile, error) {
+	var (
+		conds = []string{"p.discoverable"}
+		args  []any
+	)
+
+	if term != "" {
+		args = append(args, escapeLike(term)+"%", term)
+		conds = append(conds, `(p.profile_name LIKE $1 OR lower(p.display_name) LIKE $1
+			OR p.profile_name % $2 OR lower(p.display_name) % $2)`)
+	}
+	if viewerID != nil {
+		args = append(args, *viewerID)
+		conds = append(conds, fmt.Sprintf(`NOT EXISTS (
+			SELECT 1 FROM profile_block b
+				WHERE (b.profile_id = $%[1]d AND b.target_id = p.id)
+					OR (b.profile_id = p.id AND b.target_id = $%[1]d)
+		)`, len(args)))
+	}
+	if after != nil {
+		args = append(args, *after)
+		conds = append(conds, fmt.Sprintf("p.id > $%d", len(args)))
+	}
+
+	query := `
+		SELECT ` + profileColumns + `
+			FROM profile p
+			JOIN account a ON a.id = p.user_id AND a.deleted_at IS NULL
+			WHERE ` + strings.Join(conds, " AND ")
+	args = append(args, limit)
+	query += fmt.Sprintf(" ORDER BY p.id LIMIT $%d", len(args))
+
+	rows, err := qe.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("repo: query execution failed: %w", err)
+	}
+	defer rows.Close()
+
+	var profiles []*entity.Profile
+	for rows.Next() {
+		profile, err := scanProfile(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		profiles = append(profiles, profile)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("repo: rows iteration error: %w", err)
+	}
+
+	return profiles, nil
+}
+
+// Update saves the display name, bio and avatar of the profile.
+func (r *profileRepository) Update(ctx context.Context, qe db.QueryExecutor, profile *entity.Profile) error {
+	query := `
+		UPDATE profile
+			SET display_name = $2, bio = $3, avatar = $4, avatar_key = $5, avatar_thumbnails = $6
+			WHERE id = $1
+	`
+
+	thumbnails := profile.AvatarThumbnails
+	if thumbnails == nil {
+		thumbnails = map[int]string{}
+	}
+
+	tag, err := qe.Exec(ctx, query, profile.ID, profile.DisplayName, profile.Bio, profile.Avatar, profile.AvatarKey, thumbnails)
+	if err != nil {
+		return fmt.Errorf("repo: update user profile failed: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+func (r *profileRepository) UpdatePrivacy(ctx context.Context, qe db.QueryExecutor, id uuid.UUID, privacy entity.ProfilePrivacy) error {
+	query := `
+		UPDATE profile
+			SET is_private = $2, bio_visibility = $3, avatar_visibility = $4, discoverable = $5
+			WHERE id = $1
+	`
+
+	tag, err := qe.Exec(ctx, query, id, privacy.Private, privacy.BioVisibility, privacy.AvatarVisibility, privacy.Discoverable)
+	if err != nil {
+		return fmt.Errorf("repo: update profile privacy failed: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+const profileColumns = `p.id, p.user_id, p.profile_name, p.display_name, p.bio, p.avatar, p.created_at, p.is_default,
+	p.avatar_key, p.avatar_thumbnails, p.followers_count, p.following_count,
+	p.is_private, p.bio_visibility, p.avatar_visibility, p.discoverable`
+
+func profileFields(profile *entity.Profile) []any {
+	return []any{
+		&profile.ID,
+		&profile.UserID,
+		&profile.ProfileName,
+		&profile.DisplayName,
+		&profile.Bio,
+		&profile.Avatar,
+		&profile.CreatedAt,
+		&profile.IsDefault,
+		&profile.AvatarKey,
+		&profile.AvatarThumbnails,
+		&profile.FollowersCount,
+		&profile.FollowingCount,
+		&profile.Privacy.Private,
+		&profile.Privacy.BioVisibility,
+		&profile.Privacy.AvatarVisibility,
+		&profile.Privacy.Discoverable,
+	}
+}
+
+func scanProfile(row pgx.Row) (*entity.Profile, error) {
+	var profile entity.Profile
+
+	if err := row.Scan(profileFields(&profile)...); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("repo: get user profile failed: %w", err)
+	}
+
+	return &profile, nil
+}
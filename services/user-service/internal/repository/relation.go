@@ -0,0 +1,325 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/SkySock/lode/services/user-service/internal/db"
+	"github.com/SkySock/lode/services/user-service/internal/entity"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// Tables of the relations that have no counts.
+const (
+	blockTable = "profile_block"
+	muteTable  = "profile_mute"
+)
+
+type relationRepository struct{}
+
+func NewRelationRepository() RelationRepository {
+	return &relationRepository{}
+}
+
+// updateFollowCounts ends a statement whose changed CTE returns added or
+// removed follows: it adds them to the counts of their profiles, or subtracts
+// them with the - operator.
+const updateFollowCounts = `
+	, counts AS (
+		SELECT id, sum(followers) AS followers, sum(following) AS following
+			FROM (
+				SELECT followee_id AS id, 1 AS followers, 0 AS following FROM changed
+				UNION ALL
+				SELECT follower_id, 0, 1 FROM changed
+			) c
+			GROUP BY id
+	)
+	UPDATE profile p
+		SET followers_count = p.followers_count %[1]s counts.followers,
+			following_count = p.following_count %[1]s counts.following
+		FROM counts
+		WHERE p.id = counts.id
+`
+
+// Follow reports false when the follow already exists.
+func (r *relationRepository) Follow(ctx context.Context, qe db.QueryExecutor, followerID, followeeID uuid.UUID) (bool, error) {
+	query := `
+		WITH changed AS (
+			INSERT INTO follow(id, follower_id, followee_id) VALUES ($1, $2, $3)
+				ON CONFLICT (follower_id, followee_id) DO NOTHING
+				RETURNING follower_id, followee_id
+		)
+	` + fmt.Sprintf(updateFollowCounts, "+")
+
+	id, err := uuid.NewV7()
+	if err != nil {
+		return false, err
+	}
+
+	tag, err := qe.Exec(ctx, query, id, followerID, followeeID)
+	if err != nil {
+		return false, relationError("follow", err)
+	}
+
+	return tag.RowsAffected() > 0, nil
+}
+
+// Unfollow reports false when there was no follow to remove.
+func (r *relationRepository) Unfollow(ctx context.Context, qe db.QueryExecutor, followerID, followeeID uuid.UUID) (bool, error) {
+	query := `
+		WITH changed AS (
+			DELETE FROM follow WHERE follower_id = $1 AND followee_id = $2
+				RETURNING follower_id, followee_id
+		)
+	` + fmt.Sprintf(updateFollowCounts, "-")
+
+	tag, err := qe.Exec(ctx, query, followerID, followeeID)
+	if err != nil {
+		return false, fmt.Errorf("repo: unfollow failed: %w", err)
+	}
+
+	return tag.RowsAffected() > 0, nil
+}
+
+func (r *relationRepository) IsFollowing(ctx context.Context, qe db.QueryExecutor, followerID, followeeID uuid.UUID) (bool, error) {
+	query := `SELECT EXISTS (SELECT 1 FROM follow WHERE follower_id = $1 AND followee_id = $2)`
+
+	var following bool
+	if err := qe.QueryRow(ctx, query, followerID, followeeID).Scan(&following); err != nil {
+		return false, fmt.Errorf("repo: check follow failed: %w", err)
+	}
+
+	return following, nil
+}
+
+// DeleteFollows removes all follows of and to the profiles. It is meant to run
+// before the profiles are deleted, since the follows cascading with them
+// would not be subtracted from the counts of the profiles on their other side.
+func (r *relationRepository) DeleteFollows(ctx context.Context, qe db.QueryExecutor, profileIDs []uuid.UUID) error {
+	query := `
+		WITH changed AS (
+			DELETE FROM follow WHERE follower_id = ANY($1) OR followee_id = ANY($1)
+				RETURNING follower_id, followee_id
+		)
+	` + fmt.Sprintf(updateFollowCounts, "-")
+
+	if _, err := qe.Exec(ctx, query, profileIDs); err != nil {
+		return fmt.Errorf("repo: delete follows failed: %w", err)
+	}
+
+	return nil
+}
+
+// RequestFollow keeps an existing request as it is.
+func (r *relationRepository) RequestFollow(ctx context.Context, qe db.QueryExecutor, followerID, followeeID uuid.UUID) error {
+	query := `
+		INSERT INTO follow_request(id, follower_id, followee_id) VALUES ($1, $2, $3)
+			ON CONFLICT (follower_id, followee_id) DO NOTHING
+	`
+
+	id, err := uuid.NewV7()
+	if err != nil {
+		return err
+	}
+
+	if _, err := qe.Exec(ctx, query, id, followerID, followeeID); err != nil {
+		return relationError("request follow", err)
+	}
+
+	return nil
+}
+
+// RemoveFollowRequest reports false when there was no request to remove.
+func (r *relationRepository) RemoveFollowRequest(ctx context.Context, qe db.QueryExecutor, followerID, followeeID uuid.UUID) (bool, error) {
+	query := `DELETE FROM follow_request WHERE follower_id = $1 AND followee_id = $2`
+
+	tag, err := qe.Exec(ctx, query, followerID, followeeID)
+	if err != nil {
+		return false, fmt.Errorf("repo: remove follow request failed: %w", err)
+	}
+
+	return tag.RowsAffected() > 0, nil
+}
+
+// ListFollowRequests returns the profiles waiting to follow the profile,
+// newest first.
+func (r *relationRepository) ListFollowRequests(ctx context.Context, qe db.QueryExecutor, profileID uuid.UUID, before *uuid.UUID, limit int) ([]*entity.Relation, error) {
+	query := `
+		SELECT r.id, r.created_at, ` + profileColumns + `
+			FROM follow_request r
+			JOIN profile p ON p.id = r.follower_id
+			JOIN account a ON a.id = p.user_id AND a.deleted_at IS NULL
+			WHERE r.followee_id = $1 AND ($2::uuid IS NULL OR r.id < $2)
+			ORDER BY r.id DESC
+			LIMIT $3
+	`
+
+	rows, err := qe.Query(ctx, query, profileID, before, limit)
+	if err != nil {
+		return nil, fmt.Errorf("repo: query execution failed: %w", err)
+	}
+
+	return scanRelations(rows)
+}
+
+// ListFollowers returns the followers of the profile, newest first. When
+// viewerID is set, profiles it blocks, mutes or is blocked by are left out.
+func (r *relationRepository) ListFollowers(ctx context.Context, qe db.QueryExecutor, profileID uuid.UUID, viewerID, before *uuid.UUID, limit int) ([]*entity.Relation, error) {
+	return r.listFollows(ctx, qe, "follower_id", "followee_id", profileID, viewerID, before, limit)
+}
+
+// ListFollowing returns the profiles the profile follows, like ListFollowers.
+func (r *relationRepository) ListFollowing(ctx context.Context, qe db.QueryExecutor, profileID uuid.UUID, viewerID, before *uuid.UUID, limit int) ([]*entity.Relation, error) {
+	return r.listFollows(ctx, qe, "followee_id", "follower_id", profileID, viewerID, before, limit)
+}
+
+func (r *relationRepository) listFollows(ctx context.Context, qe db.QueryExecutor, otherColumn, ownColumn string, profileID uuid.UUID, viewerID, before *uuid.UUID, limit int) ([]*entity.Relation, error) {
+	query := `
+		SELECT r.id, r.created_at, ` + profileColumns + `
+			FROM follow r
+			JOIN profile p ON p.id = r.` + otherColumn + `
+			JOIN account a ON a.id = p.user_id AND a.deleted_at IS NULL
+			WHERE r.` + ownColumn + ` = $1
+				AND ($2::uuid IS NULL OR r.id < $2)
+				AND ($3::uuid IS NULL OR (
+					NOT EXISTS (
+						SELECT 1 FROM profile_block b
+							WHERE (b.profile_id = $3 AND b.target_id = p.id)
+								OR (b.profile_id = p.id AND b.target_id = $3)
+					)
+					AND NOT EXISTS (
+						SELECT 1 FROM profile_mute m WHERE m.profile_id = $3 AND m.target_id = p.id
+					)
+				))
+			ORDER BY r.id DESC
+			LIMIT $4
+	`
+
+	rows, err := qe.Query(ctx, query, profileID, before, viewerID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("repo: query execution failed: %w", err)
+	}
+
+	return scanRelations(rows)
+}
+
+func (r *relationRepository) Block(ctx context.Context, qe db.QueryExecutor, profileID, targetID uuid.UUID) error {
+	return addRelation(ctx, qe, blockTable, profileID, targetID)
+}
+
+func (r *relationRepository) Unblock(ctx context.Context, qe db.QueryExecutor, profileID, targetID uuid.UUID) error {
+	return removeRelation(ctx, qe, blockTable, profileID, targetID)
+}
+
+// IsBlocked reports whether either of the profiles blocks the other.
+func (r *relationRepository) IsBlocked(ctx context.Context, qe db.QueryExecutor, profileID, otherID uuid.UUID) (bool, error) {
+	query := `
+		SELECT EXISTS (
+			SELECT 1 FROM profile_block
+				WHERE (profile_id = $1 AND target_id = $2) OR (profile_id = $2 AND target_id = $1)
+		)
+	`
+
+	var blocked bool
+	if err := qe.QueryRow(ctx, query, profileID, otherID).Scan(&blocked); err != nil {
+		return false, fmt.Errorf("repo: check block failed: %w", err)
+	}
+
+	return blocked, nil
+}
+
+func (r *relationRepository) ListBlocked(ctx context.Context, qe db.QueryExecutor, profileID uuid.UUID, before *uuid.UUID, limit int) ([]*entity.Relation, error) {
+	return listRelations(ctx, qe, blockTable, profileID, before, limit)
+}
+
+func (r *relationRepository) Mute(ctx context.Context, qe db.QueryExecutor, profileID, targetID uuid.UUID) error {
+	return addRelation(ctx, qe, muteTable, profileID, targetID)
+}
+
+func (r *relationRepository) Unmute(ctx context.Context, qe db.QueryExecutor, profileID, targetID uuid.UUID) error {
+	return removeRelation(ctx, qe, muteTable, profileID, targetID)
+}
+
+func (r *relationRepository) ListMuted(ctx context.Context, qe db.QueryExecutor, profileID uuid.UUID, before *uuid.UUID, limit int) ([]*entity.Relation, error) {
+	return listRelations(ctx, qe, muteTable, profileID, before, limit)
+}
+
+// addRelation keeps an existing relation as it is.
+func addRelation(ctx context.Context, qe db.QueryExecutor, table string, profileID, targetID uuid.UUID) error {
+	query := `
+		INSERT INTO ` + table + `(id, profile_id, target_id) VALUES ($1, $2, $3)
+			ON CONFLICT (profile_id, target_id) DO NOTHING
+	`
+
+	id, err := uuid.NewV7()
+	if err != nil {
+		return err
+	}
+
+	if _, err := qe.Exec(ctx, query, id, profileID, targetID); err != nil {
+		return relationError("add "+table, err)
+	}
+
+	return nil
+}
+
+func removeRelation(ctx context.Context, qe db.QueryExecutor, table string, profileID, targetID uuid.UUID) error {
+	query := `DELETE FROM ` + table + ` WHERE profile_id = $1 AND target_id = $2`
+
+	if _, err := qe.Exec(ctx, query, profileID, targetID); err != nil {
+		return fmt.Errorf("repo: remove %s failed: %w", table, err)
+	}
+
+	return nil
+}
+
+func listRelations(ctx context.Context, qe db.QueryExecutor, table string, profileID uuid.UUID, before *uuid.UUID, limit int) ([]*entity.Relation, error) {
+	query := `
+		SELECT r.id, r.created_at, ` + profileColumns + `
+			FROM ` + table + ` r
+			JOIN profile p ON p.id = r.target_id
+			JOIN account a ON a.id = p.user_id AND a.deleted_at IS NULL
+			WHERE r.profile_id = $1 AND ($2::uuid IS NULL OR r.id < $2)
+			ORDER BY r.id DESC
+			LIMIT $3
+	`
+
+	rows, err := qe.Query(ctx, query, profileID, before, limit)
+	if err != nil {
+		return nil, fmt.Errorf("repo: query execution failed: %w", err)
+	}
+
+	return scanRelations(rows)
+}
+
+func scanRelations(rows pgx.Rows) ([]*entity.Relation, error) {
+	defer rows.Close()
+
+	var relations []*entity.Relation
+	for rows.Next() {
+		relation := &entity.Relation{Profile: new(entity.Profile)}
+		if err := rows.Scan(append([]any{&relation.ID, &relation.CreatedAt}, profileFields(relation.Profile)...)...); err != nil {
+			return nil, fmt.Errorf("repo: scan relation failed: %w", err)
+		}
+
+		relations = append(relations, relation)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("repo: rows iteration error: %w", err)
+	}
+
+	return relations, nil
+}
+
+// relationError returns ErrNotFound when one of the profiles does not exist.
+func relationError(op string, err error) error {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == "23503" {
+		return ErrNotFound
+	}
+	return fmt.Errorf("repo: %s failed: %w", op, err)
+}
@@ -3,6 +3,7 @@ package repository
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/SkySock/lode/services/user-service/internal/db"
 	"github.com/SkySock/lode/services/user-service/internal/entity"
@@ -19,12 +20,57 @@ type AccountRepository interface {
 	GetByUsername(ctx context.Context, qe db.QueryExecutor, username string) (*entity.Account, error)
 	GetByEmail(ctx context.Context, qe db.QueryExecutor, email string) (*entity.Account, error)
 	Create(ctx context.Context, qe db.QueryExecutor, account *entity.Account) (uuid.UUID, error)
+	Search(ctx context.Context, qe db.QueryExecutor, prefix, after string, limit int) ([]*entity.Account, error)
+	SetLock(ctx context.Context, qe db.QueryExecutor, id uuid.UUID, lockedAt *time.Time, reason string) error
+	SetPasswordResetRequired(ctx context.Context, qe db.QueryExecutor, id uuid.UUID, required bool) error
+	UpdatePassword(ctx context.Context, qe db.QueryExecutor, id uuid.UUID, passwordHash string) error
+	SetDeleted(ctx context.Context, qe db.QueryExecutor, id uuid.UUID, deletedAt *time.Time) error
+	LockDeleted(ctx context.Context, qe db.QueryExecutor, before time.Time, limit int) ([]uuid.UUID, error)
+	Delete(ctx context.Context, qe db.QueryExecutor, id uuid.UUID) error
+	UpdateUsername(ctx context.Context, qe db.QueryExecutor, id uuid.UUID, username string, changedAt time.Time) error
+	UpdateEmail(ctx context.Context, qe db.QueryExecutor, id uuid.UUID, email string) error
 }
 
 type ProfileRepository interface {
 	Create(ctx context.Context, qe db.QueryExecutor, profile *entity.Profile) (uuid.UUID, error)
 	GetByID(ctx context.Context, qe db.QueryExecutor, id uuid.UUID) (*entity.Profile, error)
 	GetByProfileName(ctx context.Context, qe db.QueryExecutor, name string) (*entity.Profile, error)
+	GetDefault(ctx context.Context, qe db.QueryExecutor, userID uuid.UUID) (*entity.Profile, error)
+	GetByIDs(ctx context.Context, qe db.QueryExecutor, ids []uuid.UUID) ([]*entity.Profile, error)
+	GetAllByUserID(ctx context.Context, qe db.QueryExecutor, userID uuid.UUID) ([]*entity.Profile, error)
+	Search(ctx context.Context, qe db.QueryExecutor, term string, viewerID, after *uuid.UUID, limit int) ([]*entity.Profile, error)
+	Update(ctx context.Context, qe db.QueryExecutor, profile *entity.Profile) error
+	UpdatePrivacy(ctx context.Context, qe db.QueryExecutor, id uuid.UUID, privacy entity.ProfilePrivacy) error
+}
+
+// RelationRepository keeps follows, blocks and mutes between profiles. Follows
+// are counted on both of their profiles.
+type RelationRepository interface {
+	Follow(ctx context.Context, qe db.QueryExecutor, followerID, followeeID uuid.UUID) (bool, error)
+	Unfollow(ctx context.Context, qe db.QueryExecutor, followerID, followeeID uuid.UUID) (bool, error)
+	IsFollowing(ctx context.Context, qe db.QueryExecutor, followerID, followeeID uuid.UUID) (bool, error)
+	DeleteFollows(ctx context.Context, qe db.QueryExecutor, profileIDs []uuid.UUID) error
+	RequestFollow(ctx context.Context, qe db.QueryExecutor, followerID, followeeID uuid.UUID) error
+	RemoveFollowRequest(ctx context.Context, qe db.QueryExecutor, followerID, followeeID uuid.UUID) (bool, error)
+	ListFollowRequests(ctx context.Context, qe db.QueryExecutor, profileID uuid.UUID, before *uuid.UUID, limit int) ([]*entity.Relation, error)
+	ListFollowers(ctx context.Context, qe db.QueryExecutor, profileID uuid.UUID, viewerID, before *uuid.UUID, limit int) ([]*entity.Relation, error)
+	ListFollowing(ctx context.Context, qe db.QueryExecutor, profileID uuid.UUID, viewerID, before *uuid.UUID, limit int) ([]*entity.Relation, error)
+	Block(ctx context.Context, qe db.QueryExecutor, profileID, targetID uuid.UUID) error
+	Unblock(ctx context.Context, qe db.QueryExecutor, profileID, targetID uuid.UUID) error
+	IsBlocked(ctx context.Context, qe db.QueryExecutor, profileID, otherID uuid.UUID) (bool, error)
+	ListBlocked(ctx context.Context, qe db.QueryExecutor, profileID uuid.UUID, before *uuid.UUID, limit int) ([]*entity.Relation, error)
+	Mute(ctx context.Context, qe db.QueryExecutor, profileID, targetID uuid.UUID) error
+	Unmute(ctx context.Context, qe db.QueryExecutor, profileID, targetID uuid.UUID) error
+	ListMuted(ctx context.Context, qe db.QueryExecutor, profileID uuid.UUID, before *uuid.UUID, limit int) ([]*entity.Relation, error)
+}
+
+type RoleRepository interface {
+	List(ctx context.Context, qe db.QueryExecutor) ([]*entity.Role, error)
+	GetByAccount(ctx context.Context, qe db.QueryExecutor, accountID uuid.UUID) ([]*entity.Role, error)
+	GetAccountRoles(ctx context.Context, qe db.QueryExecutor, accountID uuid.UUID) ([]*entity.AccountRole, error)
+	Grant(ctx context.Context, qe db.QueryExecutor, accountID uuid.UUID, role string, grantedBy *uuid.UUID) error
+	Revoke(ctx context.Context, qe db.QueryExecutor, accountID uuid.UUID, role string) error
+	LockHolders(ctx context.Context, qe db.QueryExecutor, role string) ([]uuid.UUID, error)
 }
 
 type SessionRepository interface {
@@ -33,4 +79,72 @@ type SessionRepository interface {
 	Revoke(ctx context.Context, token string) error
 	Delete(ctx context.Context, token string) error
 	GetUserSessions(ctx context.Context, userID string) ([]string, error)
+	DeleteUserSessions(ctx context.Context, userID string) (int, error)
+}
+
+type AuditRepository interface {
+	Create(ctx context.Context, qe db.QueryExecutor, entry *entity.AuditEntry) error
+	ScrubTargets(ctx context.Context, qe db.QueryExecutor, targetIDs []uuid.UUID) error
+}
+
+type AuthEventRepository interface {
+	Create(ctx context.Context, qe db.QueryExecutor, event *entity.AuthEvent) error
+	List(ctx context.Context, qe db.QueryExecutor, filter AuthEventFilter, limit int) ([]*entity.AuthEvent, error)
+}
+
+// IdentityRepository keeps the history of username and email changes and the
+// reservations of old usernames.
+type IdentityRepository interface {
+	IsReserved(ctx context.Context, qe db.QueryExecutor, username string, accountID uuid.UUID) (bool, error)
+	Reserve(ctx context.Context, qe db.QueryExecutor, username string, accountID uuid.UUID, until time.Time) error
+	Release(ctx context.Context, qe db.QueryExecutor, username string) error
+	AddChange(ctx context.Context, qe db.QueryExecutor, change *entity.IdentityChange) error
+	ListChanges(ctx context.Context, qe db.QueryExecutor, accountID uuid.UUID) ([]*entity.IdentityChange, error)
+}
+
+// EmailChangeRepository holds pending email changes until their confirmation.
+type EmailChangeRepository interface {
+	Save(ctx context.Context, tokenHash string, change *entity.EmailChange, ttl time.Duration) error
+	Take(ctx context.Context, tokenHash string) (*entity.EmailChange, error)
+}
+
+// OutboxRepository holds domain events until they are published.
+type OutboxRepository interface {
+	Add(ctx context.Context, qe db.QueryExecutor, msg *entity.OutboxMessage) error
+	LockPending(ctx context.Context, qe db.QueryExecutor, limit int) ([]*entity.OutboxMessage, error)
+	Delete(ctx context.Context, qe db.QueryExecutor, ids []uuid.UUID) error
+	DeleteByKeys(ctx context.Context, qe db.QueryExecutor, keys []string) error
+}
+
+type WebhookRepository interface {
+	CreateSubscription(ctx context.Context, qe db.QueryExecutor, sub *entity.WebhookSubscription) error
+	GetSubscription(ctx context.Context, qe db.QueryExecutor, id uuid.UUID) (*entity.WebhookSubscription, error)
+	ListSubscriptions(ctx context.Context, qe db.QueryExecutor) ([]*entity.WebhookSubscription, error)
+	SubscriptionsFor(ctx context.Context, qe db.QueryExecutor, eventType string) ([]*entity.WebhookSubscription, error)
+	DeleteSubscription(ctx context.Context, qe db.QueryExecutor, id uuid.UUID) error
+	AddDelivery(ctx context.Context, qe db.QueryExecutor, d *entity.WebhookDelivery) error
+	ClaimDue(ctx context.Context, qe db.QueryExecutor, now, leaseUntil time.Time, limit int) ([]*entity.WebhookDelivery, error)
+	SaveAttempt(ctx context.Context, qe db.QueryExecutor, d *entity.WebhookDelivery) error
+	ListDeliveries(ctx context.Context, qe db.QueryExecutor, subscriptionID uuid.UUID, status string, before *uuid.UUID, limit int) ([]*entity.WebhookDelivery, error)
+	Replay(ctx context.Context, qe db.QueryExecutor, subscriptionID, id uuid.UUID, at time.Time) error
+	DeleteAccountDeliveries(ctx context.Context, qe db.QueryExecutor, accountID uuid.UUID) error
+}
+
+// AuthEventFilter narrows down auth events; zero fields are not applied.
+type AuthEventFilter struct {
+	AccountID *uuid.UUID
+	Type      string
+	Outcome   string
+	IP        string
+	From      time.Time
+	To        time.Time
+	// Before is the id of the last event of the previous page.
+	Before *uuid.UUID
+}
+
+// TokenDenylist holds ids of access tokens that were revoked before they
+// expired.
+type TokenDenylist interface {
+	Add(ctx context.Context, jti string, ttl time.Duration) error
+	Contains(ctx context.Context, jti string) (bool, error)
 }
@@ -0,0 +1,149 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/SkySock/lode/services/user-service/internal/db"
+	"github.com/SkySock/lode/services/user-service/internal/entity"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+type roleRepository struct{}
+
+func NewRoleRepository() RoleRepository {
+	return &roleRepository{}
+}
+
+func (r *roleRepository) List(ctx context.Context, qe db.QueryExecutor) ([]*entity.Role, error) {
+	query := `SELECT name, description, permissions FROM role ORDER BY name`
+
+	return r.queryRoles(ctx, qe, query)
+}
+
+func (r *roleRepository) GetByAccount(ctx context.Context, qe db.QueryExecutor, accountID uuid.UUID) ([]*entity.Role, error) {
+	query := `
+		SELECT r.name, r.description, r.permissions
+			FROM role r
+			JOIN account_role ar ON ar.role = r.name
+			WHERE ar.account_id = $1
+			ORDER BY r.name
+	`
+
+	return r.queryRoles(ctx, qe, query, accountID)
+}
+
+func (r *roleRepository) GetAccountRoles(ctx context.Context, qe db.QueryExecutor, accountID uuid.UUID) ([]*entity.AccountRole, error) {
+	query := `SELECT role, granted_by, granted_at FROM account_role WHERE account_id = $1 ORDER BY role`
+
+	rows, err := qe.Query(ctx, query, accountID)
+	if err != nil {
+		return nil, fmt.Errorf("repo: query execution failed: %w", err)
+	}
+	defer rows.Close()
+
+	var roles []*entity.AccountRole
+	for rows.Next() {
+		role := new(entity.AccountRole)
+
+		if err := rows.Scan(&role.Role, &role.GrantedBy, &role.GrantedAt); err != nil {
+			return nil, fmt.Errorf("repo: scan account role failed: %w", err)
+		}
+
+		roles = append(roles, role)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("repo: rows iteration error: %w", err)
+	}
+
+	return roles, nil
+}
+
+// Grant is idempotent. It returns ErrNotFound if the account or the role does
+// not exist.
+func (r *roleRepository) Grant(ctx context.Context, qe db.QueryExecutor, accountID uuid.UUID, role string, grantedBy *uuid.UUID) error {
+	query := `
+		INSERT INTO account_role (account_id, role, granted_by)
+			VALUES ($1, $2, $3)
+			ON CONFLICT DO NOTHING
+	`
+
+	if _, err := qe.Exec(ctx, query, accountID, role, grantedBy); err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23503" {
+			return ErrNotFound
+		}
+		return fmt.Errorf("repo: grant role failed: %w", err)
+	}
+
+	return nil
+}
+
+func (r *roleRepository) Revoke(ctx context.Context, qe db.QueryExecutor, accountID uuid.UUID, role string) error {
+	query := `DELETE FROM account_role WHERE account_id = $1 AND role = $2`
+
+	tag, err := qe.Exec(ctx, query, accountID, role)
+	if err != nil {
+		return fmt.Errorf("repo: revoke role failed: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+// LockHolders returns the accounts holding the role and locks their grants
+// until the end of the transaction.
+func (r *roleRepository) LockHolders(ctx context.Context, qe db.QueryExecutor, role string) ([]uuid.UUID, error) {
+	query := `SELECT account_id FROM account_role WHERE role = $1 FOR UPDATE`
+
+	rows, err := qe.Query(ctx, query, role)
+	if err != nil {
+		return nil, fmt.Errorf("repo: query execution failed: %w", err)
+	}
+	defer rows.Close()
+
+	var ids []uuid.UUID
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("repo: scan role holder failed: %w", err)
+		}
+		ids = append(ids, id)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("repo: rows iteration error: %w", err)
+	}
+
+	return ids, nil
+}
+
+func (r *roleRepository) queryRoles(ctx context.Context, qe db.QueryExecutor, query string, args ...any) ([]*entity.Role, error) {
+	rows, err := qe.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("repo: query execution failed: %w", err)
+	}
+	defer rows.Close()
+
+	var roles []*entity.Role
+	for rows.Next() {
+		role := new(entity.Role)
+
+		if err := rows.Scan(&role.Name, &role.Description, &role.Permissions); err != nil {
+			return nil, fmt.Errorf("repo: scan role failed: %w", err)
+		}
+
+		roles = append(roles, role)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("repo: rows iteration error: %w", err)
+	}
+
+	return roles, nil
+}
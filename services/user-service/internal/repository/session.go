@@ -121,7 +121,7 @@ func (r *sessionRepository) Revoke(ctx context.Context, token string) error {
 func (r *sessionRepository) Delete(ctx context.Context, token string) error {
 	session, err := r.Get(ctx, token)
 	if err != nil {
-		if valkey.IsValkeyNil(err) {
+		if errors.Is(err, ErrNotFound) {
 			return nil
 		}
 		return err
@@ -170,3 +170,27 @@ func (r *sessionRepository) GetUserSessions(ctx context.Context, userID string)
 
 	return result.AsStrSlice()
 }
+
+// DeleteUserSessions deletes all sessions of the user together with the
+// user_session index and returns how many there were.
+func (r *sessionRepository) DeleteUserSessions(ctx context.Context, userID string) (int, error) {
+	sessionKey := fmt.Sprintf("%s:%s", sessionPrefix, userID)
+
+	script := `
+	local tokens = redis.call('SMEMBERS', KEYS[1])
+	for _, token in ipairs(tokens) do
+		redis.call('DEL', ARGV[1] .. token)
+	end
+	redis.call('DEL', KEYS[1])
+	return #tokens
+	`
+
+	vscript := valkey.NewLuaScript(script)
+	resp := vscript.Exec(ctx, r.client, []string{sessionKey}, []string{tokenPrefix + ":"})
+	n, err := resp.AsInt64()
+	if err != nil {
+		return 0, err
+	}
+
+	return int(n), nil
+}
@@ -0,0 +1,301 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/SkySock/lode/services/user-service/internal/db"
+	"github.com/SkySock/lode/services/user-service/internal/entity"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+type webhookRepository struct{}
+
+func NewWebhookRepository() WebhookRepository {
+	return &webhookRepository{}
+}
+
+func (r *webhookRepository) CreateSubscription(ctx context.Context, qe db.QueryExecutor, sub *entity.WebhookSubscription) error {
+	query := `
+		INSERT INTO webhook_subscription (id, url, secret, event_types, description, created_by)
+			VALUES ($1, $2, $3, $4, $5, $6)
+			RETURNING created_at
+	`
+
+	id, err := uuid.NewV7()
+	if err != nil {
+		return err
+	}
+
+	if err := qe.QueryRow(ctx, query, id, sub.URL, sub.Secret, sub.EventTypes, sub.Description, sub.CreatedBy).
+		Scan(&sub.CreatedAt); err != nil {
+		return fmt.Errorf("repo: create webhook subscription failed: %w", err)
+	}
+	sub.ID = id
+
+	return nil
+}
+
+func (r *webhookRepository) GetSubscription(ctx context.Context, qe db.QueryExecutor, id uuid.UUID) (*entity.WebhookSubscription, error) {
+	query := `SELECT ` + subscriptionColumns + ` FROM webhook_subscription WHERE id = $1`
+
+	sub, err := scanSubscription(qe.QueryRow(ctx, query, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("repo: get webhook subscription failed: %w", err)
+	}
+
+	return sub, nil
+}
+
+func (r *webhookRepository) ListSubscriptions(ctx context.Context, qe db.QueryExecutor) ([]*entity.WebhookSubscription, error) {
+	query := `SELECT ` + subscriptionColumns + ` FROM webhook_subscription ORDER BY id`
+
+	return querySubscriptions(ctx, qe, query)
+}
+
+// SubscriptionsFor returns the subscriptions to the event type.
+func (r *webhookRepository) SubscriptionsFor(ctx context.Context, qe db.QueryExecutor, eventType string) ([]*entity.WebhookSubscription, error) {
+	query := `SELECT ` + subscriptionColumns + ` FROM webhook_subscription WHERE $1 = ANY(event_types)`
+
+	return querySubscriptions(ctx, qe, query, eventType)
+}
+
+// DeleteSubscription removes the subscription with its deliveries.
+func (r *webhookRepository) DeleteSubscription(ctx context.Context, qe db.QueryExecutor, id uuid.UUID) error {
+	query := `DELETE FROM webhook_subscription WHERE id = $1`
+
+	tag, err := qe.Exec(ctx, query, id)
+	if err != nil {
+		return fmt.Errorf("repo: delete webhook subscription failed: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+// AddDelivery schedules the delivery. An event already scheduled for the
+// subscription is skipped.
+func (r *webhookRepository) AddDelivery(ctx context.Context, qe db.QueryExecutor, d *entity.WebhookDelivery) error {
+	query := `
+		INSERT INTO webhook_delivery (id, subscription_id, event_id, event_type, payload, occurred_at, next_attempt_at)
+			VALUES ($1, $2, $3, $4, $5, $6, $7)
+			ON CONFLICT (subscription_id, event_id) DO NOTHING
+	`
+
+	id, err := uuid.NewV7()
+	if err != nil {
+		return err
+	}
+
+	if _, err := qe.Exec(ctx, query, id, d.SubscriptionID, d.EventID, d.EventType, d.Payload, d.OccurredAt, d.NextAttemptAt); err != nil {
+		return fmt.Errorf("repo: create webhook delivery failed: %w", err)
+	}
+	d.ID = id
+
+	return nil
+}
+
+// ClaimDue returns up to limit pending deliveries due at now and postpones
+// them until leaseUntil, so that no other instance sends them meanwhile. A
+// delivery whose sender crashed is retried after the lease.
+func (r *webhookRepository) ClaimDue(ctx context.Context, qe db.QueryExecutor, now, leaseUntil time.Time, limit int) ([]*entity.WebhookDelivery, error) {
+	query := `
+		WITH due AS (
+			SELECT id
+				FROM webhook_delivery
+				WHERE status = 'pending' AND next_attempt_at <= $1
+				ORDER BY next_attempt_at
+				LIMIT $3
+				FOR UPDATE SKIP LOCKED
+		)
+		UPDATE webhook_delivery d
+			SET next_attempt_at = $2
+			FROM due, webhook_subscription s
+			WHERE d.id = due.id AND s.id = d.subscription_id
+			RETURNING ` + deliveryColumns + `, s.url, s.secret
+	`
+
+	rows, err := qe.Query(ctx, query, now, leaseUntil, limit)
+	if err != nil {
+		return nil, fmt.Errorf("repo: query execution failed: %w", err)
+	}
+	defer rows.Close()
+
+	var deliveries []*entity.WebhookDelivery
+	for rows.Next() {
+		d := new(entity.WebhookDelivery)
+		if err := rows.Scan(append(deliveryFields(d), &d.URL, &d.Secret)...); err != nil {
+			return nil, fmt.Errorf("repo: scan webhook delivery failed: %w", err)
+		}
+
+		deliveries = append(deliveries, d)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("repo: rows iteration error: %w", err)
+	}
+
+	return deliveries, nil
+}
+
+// SaveAttempt stores the outcome of the last attempt of the delivery.
+func (r *webhookRepository) SaveAttempt(ctx context.Context, qe db.QueryExecutor, d *entity.WebhookDelivery) error {
+	query := `
+		UPDATE webhook_delivery
+			SET status = $2, attempts = $3, next_attempt_at = $4, last_status_code = $5, last_error = $6, delivered_at = $7
+			WHERE id = $1
+	`
+
+	tag, err := qe.Exec(ctx, query, d.ID, d.Status, d.Attempts, d.NextAttemptAt, d.LastStatusCode, d.LastError, d.DeliveredAt)
+	if err != nil {
+		return fmt.Errorf("repo: update webhook delivery failed: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+// ListDeliveries returns deliveries of the subscription, newest first. Empty
+// status matches all of them; before is the id of the last delivery of the
+// previous page.
+func (r *webhookRepository) ListDeliveries(ctx context.Context, qe db.QueryExecutor, subscriptionID uuid.UUID, status string, before *uuid.UUID, limit int) ([]*entity.WebhookDelivery, error) {
+	query := `
+		SELECT ` + deliveryColumns + `
+			FROM webhook_delivery d
+			WHERE d.subscription_id = $1
+				AND ($2 = '' OR d.status = $2)
+				AND ($3::uuid IS NULL OR d.id < $3)
+			ORDER BY d.id DESC
+			LIMIT $4
+	`
+
+	rows, err := qe.Query(ctx, query, subscriptionID, status, before, limit)
+	if err != nil {
+		return nil, fmt.Errorf("repo: query execution failed: %w", err)
+	}
+	defer rows.Close()
+
+	var deliveries []*entity.WebhookDelivery
+	for rows.Next() {
+		d := new(entity.WebhookDelivery)
+		if err := rows.Scan(deliveryFields(d)...); err != nil {
+			return nil, fmt.Errorf("repo: scan webhook delivery failed: %w", err)
+		}
+
+		deliveries = append(deliveries, d)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("repo: rows iteration error: %w", err)
+	}
+
+	return deliveries, nil
+}
+
+// Replay schedules the delivery of the subscription again at the given time
+// with a fresh set of attempts, whatever its status.
+func (r *webhookRepository) Replay(ctx context.Context, qe db.QueryExecutor, subscriptionID, id uuid.UUID, at time.Time) error {
+	query := `
+		UPDATE webhook_delivery
+			SET status = 'pending', attempts = 0, next_attempt_at = $3, last_error = '', delivered_at = NULL
+			WHERE subscription_id = $1 AND id = $2
+	`
+
+	tag, err := qe.Exec(ctx, query, subscriptionID, id, at)
+	if err != nil {
+		return fmt.Errorf("repo: replay webhook delivery failed: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+const subscriptionColumns = `id, url, secret, event_types, description, created_by, created_at`
+
+func scanSubscription(row pgx.Row) (*entity.WebhookSubscription, error) {
+	var sub entity.WebhookSubscription
+
+	err := row.Scan(
+		&sub.ID,
+		&sub.URL,
+		&sub.Secret,
+		&sub.EventTypes,
+		&sub.Description,
+		&sub.CreatedBy,
+		&sub.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &sub, nil
+}
+
+func querySubscriptions(ctx context.Context, qe db.QueryExecutor, query string, args ...any) ([]*entity.WebhookSubscription, error) {
+	rows, err := qe.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("repo: query execution failed: %w", err)
+	}
+	defer rows.Close()
+
+	var subs []*entity.WebhookSubscription
+	for rows.Next() {
+		sub, err := scanSubscription(rows)
+		if err != nil {
+			return nil, fmt.Errorf("repo: scan webhook subscription failed: %w", err)
+		}
+
+		subs = append(subs, sub)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("repo: rows iteration error: %w", err)
+	}
+
+	return subs, nil
+}
+
+const deliveryColumns = `d.id, d.subscription_id, d.event_id, d.event_type, d.payload, d.occurred_at, d.status, d.attempts,
+	d.next_attempt_at, d.last_status_code, d.last_error, d.created_at, d.delivered_at`
+
+func deliveryFields(d *entity.WebhookDelivery) []any {
+	return []any{
+		&d.ID,
+		&d.SubscriptionID,
+		&d.EventID,
+		&d.EventType,
+		&d.Payload,
+		&d.OccurredAt,
+		&d.Status,
+		&d.Attempts,
+		&d.NextAttemptAt,
+		&d.LastStatusCode,
+		&d.LastError,
+		&d.CreatedAt,
+		&d.DeliveredAt,
+	}
+}
+
+// DeleteAccountDeliveries removes the deliveries of events about the account,
+// whatever their status. Every event sent to webhooks names its account.
+func (r *webhookRepository) DeleteAccountDeliveries(ctx context.Context, qe db.QueryExecutor, accountID uuid.UUID) error {
+	query := `DELETE FROM webhook_delivery WHERE payload->>'accountId' = $1`
+
+	if _, err := qe.Exec(ctx, query, accountID.String()); err != nil {
+		return fmt.Errorf("repo: delete webhook deliveries failed: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,118 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Local keeps blobs as files in a directory. It also serves them, at the path
+// of its base URL.
+type Local struct {
+	dir     string
+	baseURL string
+	prefix  string
+	files   http.Handler
+}
+
+var _ http.Handler = (*Local)(nil)
+
+func NewLocal(dir, baseURL string) (*Local, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("storage: create dir failed: %w", err)
+	}
+	u, err := url.Parse(strings.TrimSuffix(baseURL, "/"))
+	if err != nil {
+		return nil, fmt.Errorf("storage: invalid base url %q: %w", baseURL, err)
+	}
+
+	return &Local{
+		dir:     dir,
+		baseURL: u.String(),
+		prefix:  u.Path + "/",
+		files:   http.StripPrefix(u.Path, http.FileServer(http.Dir(dir))),
+	}, nil
+}
+
+// Prefix is the path the blobs are served under.
+func (s *Local) Prefix() string {
+	return s.prefix
+}
+
+// ServeHTTP serves blobs, but not listings of the directory.
+func (s *Local) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if strings.HasSuffix(r.URL.Path, "/") {
+		http.NotFound(w, r)
+		return
+	}
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	s.files.ServeHTTP(w, r)
+}
+
+// Put replaces the file atomically, so that it is never served half written.
+func (s *Local) Put(ctx context.Context, key, contentType string, data []byte) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("storage: create dir failed: %w", err)
+	}
+
+	f, err := os.CreateTemp(filepath.Dir(path), ".upload-*")
+	if err != nil {
+		return fmt.Errorf("storage: create file failed: %w", err)
+	}
+	defer os.Remove(f.Name())
+
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return fmt.Errorf("storage: write file failed: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("storage: write file failed: %w", err)
+	}
+	if err := os.Chmod(f.Name(), 0o644); err != nil {
+		return fmt.Errorf("storage: chmod failed: %w", err)
+	}
+
+	if err := os.Rename(f.Name(), path); err != nil {
+		return fmt.Errorf("storage: rename failed: %w", err)
+	}
+
+	return nil
+}
+
+func (s *Local) Delete(ctx context.Context, key string) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+
+	if err := os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("storage: remove file failed: %w", err)
+	}
+
+	return nil
+}
+
+func (s *Local) URL(key string) string {
+	return s.baseURL + "/" + key
+}
+
+// path maps key to a file in the directory, rejecting keys that would point
+// outside of it.
+func (s *Local) path(key string) (string, error) {
+	name := filepath.FromSlash(key)
+	if !filepath.IsLocal(name) {
+		return "", fmt.Errorf("storage: invalid key %q", key)
+	}
+
+	return filepath.Join(s.dir, name), nil
+}
@@ -0,0 +1,30 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/SkySock/lode/services/user-service/internal/config"
+)
+
+// BlobStore keeps uploaded files under slash-separated keys and serves them at
+// public URLs.
+type BlobStore interface {
+	Put(ctx context.Context, key, contentType string, data []byte) error
+	// Delete succeeds when there is no blob with the key.
+	Delete(ctx context.Context, key string) error
+	URL(key string) string
+}
+
+// New returns the configured blob store.
+func New(cfg config.StorageConfig) (BlobStore, error) {
+	switch cfg.Backend {
+	case "local":
+		return NewLocal(cfg.Local.Dir, cfg.Local.BaseURL)
+	case "s3":
+		return NewS3(cfg.S3, &http.Client{Timeout: cfg.S3.Timeout})
+	default:
+		return nil, fmt.Errorf("storage: unknown backend %q", cfg.Backend)
+	}
+}
@@ -0,0 +1,285 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	events "github.com/SkySock/lode/libs/shared-dto/user/events/v1"
+	"github.com/SkySock/lode/libs/utils/argon2id"
+	"github.com/SkySock/lode/services/user-service/internal/config"
+	"github.com/SkySock/lode/services/user-service/internal/db"
+	"github.com/SkySock/lode/services/user-service/internal/entity"
+	"github.com/SkySock/lode/services/user-service/internal/metrics"
+	"github.com/SkySock/lode/services/user-service/internal/repository"
+	"github.com/SkySock/lode/services/user-service/internal/storage"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+var ErrAccountNotFound = errors.New("account not found")
+
+const (
+	purgeBatchSize  = 100
+	exportBatchSize = 500
+)
+
+type accountUsecase struct {
+	pgPool       *pgxpool.Pool
+	repo         repository.AccountRepository
+	profileRepo  repository.ProfileRepository
+	eventRepo    repository.AuthEventRepository
+	auditRepo    repository.AuditRepository
+	identityRepo repository.IdentityRepository
+	relationRepo repository.RelationRepository
+	outboxRepo   repository.OutboxRepository
+	webhookRepo  repository.WebhookRepository
+	avatarStore  storage.BlobStore
+	sessions     accountSessions
+	deletion     config.DeletionConfig
+}
+
+func NewAccountUsecase(
+	pgPool *pgxpool.Pool,
+	repo repository.AccountRepository,
+	profileRepo repository.ProfileRepository,
+	eventRepo repository.AuthEventRepository,
+	auditRepo repository.AuditRepository,
+	identityRepo repository.IdentityRepository,
+	relationRepo repository.RelationRepository,
+	outboxRepo repository.OutboxRepository,
+	webhookRepo repository.WebhookRepository,
+	avatarStore storage.BlobStore,
+	sessions accountSessions,
+	deletion config.DeletionConfig,
+) AccountUsecase {
+	return &accountUsecase{
+		pgPool:       pgPool,
+		repo:         repo,
+		profileRepo:  profileRepo,
+		eventRepo:    eventRepo,
+		auditRepo:    auditRepo,
+		identityRepo: identityRepo,
+		relationRepo: relationRepo,
+		outboxRepo:   outboxRepo,
+		webhookRepo:  webhookRepo,
+		avatarStore:  avatarStore,
+		sessions:     sessions,
+		deletion:     deletion,
+	}
+}
+
+// GetAccount treats accounts waiting for their purge as gone.
+func (uc *accountUsecase) GetAccount(ctx context.Context, id uuid.UUID) (*entity.Account, error) {
+	account, err := uc.repo.GetById(ctx, uc.pgPool, id)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrAccountNotFound
+		}
+		return nil, err
+	}
+	if account.Deleted() {
+		return nil, ErrAccountNotFound
+	}
+
+	return account, nil
+}
+
+// DeleteAccount marks the account as deleted and signs it out everywhere. The
+// account can be restored until the returned purge time.
+func (uc *accountUsecase) DeleteAccount(ctx context.Context, id uuid.UUID, password string) (time.Time, error) {
+	account, err := uc.GetAccount(ctx, id)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	if err := checkPassword(ctx, uc.pgPool, uc.eventRepo, account, password, entity.AuthEventDeletionRequested); err != nil {
+		return time.Time{}, err
+	}
+
+	now := time.Now().UTC()
+	purgeAfter := now.Add(uc.deletion.GracePeriod)
+
+	err = db.WithTx(ctx, uc.pgPool, db.TxOptions{}, func(tx db.QueryExecutor) error {
+		if err := uc.repo.SetDeleted(ctx, tx, id, &now); err != nil {
+			return err
+		}
+		if err := uc.eventRepo.Create(ctx, tx, newAuthEvent(ctx, &id, entity.AuthEventDeletionRequested)); err != nil {
+			return err
+		}
+		deleted := events.AccountDeleted{AccountID: id.String(), PurgeAfter: purgeAfter}
+		return addEvent(ctx, tx, uc.outboxRepo, events.TypeAccountDeleted, id, deleted)
+	})
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	if _, err := uc.sessions.TerminateSessions(ctx, id); err != nil {
+		return time.Time{}, fmt.Errorf("account deleted, but sessions were not terminated: %w", err)
+	}
+
+	return purgeAfter, nil
+}
+
+// ExportData collects everything the service stores about the account.
+func (uc *accountUsecase) ExportData(ctx context.Context, id uuid.UUID) (*AccountExport, error) {
+	account, err := uc.GetAccount(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	profiles, err := uc.profileRepo.GetAllByUserID(ctx, uc.pgPool, id)
+	if err != nil {
+		return nil, err
+	}
+
+	sessions, err := uc.sessions.ActiveSessions(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	identityChanges, err := uc.identityRepo.ListChanges(ctx, uc.pgPool, id)
+	if err != nil {
+		return nil, err
+	}
+
+	var events []*entity.AuthEvent
+	filter := repository.AuthEventFilter{AccountID: &id}
+	for {
+		batch, err := uc.eventRepo.List(ctx, uc.pgPool, filter, exportBatchSize)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, batch...)
+
+		if len(batch) < exportBatchSize {
+			break
+		}
+		filter.Before = &batch[len(batch)-1].ID
+	}
+
+	if err := uc.eventRepo.Create(ctx, uc.pgPool, newAuthEvent(ctx, &id, entity.AuthEventDataExported)); err != nil {
+		return nil, err
+	}
+
+	return &AccountExport{
+		Account:         account,
+		Profiles:        profiles,
+		Sessions:        sessions,
+		AuthEvents:      events,
+		IdentityChanges: identityChanges,
+	}, nil
+}
+
+// PurgeDeletedAccounts removes accounts whose grace period is over, together
+// with their profiles, avatars, roles, auth events, sessions, unpublished
+// events and webhook deliveries, and scrubs the audit log entries about them.
+// It returns the number of purged accounts.
+func (uc *accountUsecase) PurgeDeletedAccounts(ctx context.Context) (int, error) {
+	var (
+		ids      []uuid.UUID
+		profiles []*entity.Profile
+	)
+	err := db.WithTx(ctx, uc.pgPool, db.TxOptions{}, func(tx db.QueryExecutor) error {
+		var err error
+		ids, err = uc.repo.LockDeleted(ctx, tx, time.Now().UTC().Add(-uc.deletion.GracePeriod), purgeBatchSize)
+		if err != nil {
+			return err
+		}
+
+		profiles = nil
+		for _, id := range ids {
+			purged, err := uc.purge(ctx, tx, id)
+			if err != nil {
+				return err
+			}
+			profiles = append(profiles, purged...)
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	// Sessions were terminated at deletion; this catches any left behind.
+	for _, id := range ids {
+		if _, err := uc.sessions.TerminateSessions(ctx, id); err != nil {
+			return len(ids), fmt.Errorf("account %s purged, but its sessions were not: %w", id, err)
+		}
+	}
+	for _, profile := range profiles {
+		if err := removeAvatarFiles(ctx, uc.avatarStore, profile.AvatarKey, profile.AvatarThumbnails); err != nil {
+			return len(ids), fmt.Errorf("profile %s purged, but its avatar was not: %w", profile.ID, err)
+		}
+	}
+
+	return len(ids), nil
+}
+
+// purge removes the account and scrubs the audit log entries about it and its
+// profiles. It returns the removed profiles.
+func (uc *accountUsecase) purge(ctx context.Context, tx db.QueryExecutor, id uuid.UUID) ([]*entity.Profile, error) {
+	profiles, err := uc.profileRepo.GetAllByUserID(ctx, tx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	profileIDs := make([]uuid.UUID, 0, len(profiles))
+	purged := events.AccountPurged{AccountID: id.String(), ProfileIDs: make([]string, 0, len(profiles))}
+	for _, profile := range profiles {
+		profileIDs = append(profileIDs, profile.ID)
+		purged.ProfileIDs = append(purged.ProfileIDs, profile.ID.String())
+	}
+	if err := uc.auditRepo.ScrubTargets(ctx, tx, append([]uuid.UUID{id}, profileIDs...)); err != nil {
+		return nil, err
+	}
+	// Follows would cascade with the profiles, leaving the counts of the
+	// profiles on their other side behind.
+	if err := uc.relationRepo.DeleteFollows(ctx, tx, profileIDs); err != nil {
+		return nil, err
+	}
+	// Events not yet published or delivered would carry the data of the
+	// account past its purge.
+	if err := uc.outboxRepo.DeleteByKeys(ctx, tx, append([]string{id.String()}, purged.ProfileIDs...)); err != nil {
+		return nil, err
+	}
+	if err := uc.webhookRepo.DeleteAccountDeliveries(ctx, tx, id); err != nil {
+		return nil, err
+	}
+
+	if err := uc.repo.Delete(ctx, tx, id); err != nil {
+		return nil, err
+	}
+
+	entry := &entity.AuditEntry{Action: entity.AuditAccountPurged, TargetID: &id}
+	if err := uc.auditRepo.Create(ctx, tx, entry); err != nil {
+		return nil, err
+	}
+	if err := addEvent(ctx, tx, uc.outboxRepo, events.TypeAccountPurged, id, purged); err != nil {
+		return nil, err
+	}
+
+	return profiles, nil
+}
+
+// checkPassword confirms an operation of a signed in account with its
+// password. A wrong password is recorded as a failed event of the operation.
+func checkPassword(ctx context.Context, qe db.QueryExecutor, eventRepo repository.AuthEventRepository, account *entity.Account, password, eventType string) error {
+	check, err := argon2id.VerifyPassword([]byte(password), account.PasswordHash)
+	if err != nil {
+		return err
+	}
+	if check {
+		return nil
+	}
+
+	event := newAuthEvent(ctx, &account.ID, eventType)
+	event.Outcome = entity.AuthOutcomeFailure
+	event.Reason = metrics.SignInReasonIncorrectPassword
+	if err := eventRepo.Create(ctx, qe, event); err != nil {
+		return fmt.Errorf("failed to record auth event: %w", err)
+	}
+
+	return ErrIncorrectPassword
+}
@@ -0,0 +1,287 @@
+package usecase
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	events "github.com/SkySock/lode/libs/shared-dto/user/events/v1"
+	"github.com/SkySock/lode/services/user-service/internal/db"
+	"github.com/SkySock/lode/services/user-service/internal/entity"
+	repo "github.com/SkySock/lode/services/user-service/internal/repository"
+	"github.com/SkySock/lode/services/user-service/internal/storage"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+type accountSessions interface {
+	ActiveSessions(ctx context.Context, accountID uuid.UUID) ([]*entity.Session, error)
+	TerminateSessions(ctx context.Context, accountID uuid.UUID) (int, error)
+}
+
+type adminUsecase struct {
+	pgPool      *pgxpool.Pool
+	accountRepo repo.AccountRepository
+	profileRepo repo.ProfileRepository
+	auditRepo   repo.AuditRepository
+	eventRepo   repo.AuthEventRepository
+	outboxRepo  repo.OutboxRepository
+	avatarStore storage.BlobStore
+	sessions    accountSessions
+}
+
+func NewAdminUsecase(
+	pool *pgxpool.Pool,
+	accountRepo repo.AccountRepository,
+	profileRepo repo.ProfileRepository,
+	auditRepo repo.AuditRepository,
+	eventRepo repo.AuthEventRepository,
+	outboxRepo repo.OutboxRepository,
+	avatarStore storage.BlobStore,
+	sessions accountSessions,
+) AdminUsecase {
+	return &adminUsecase{
+		pgPool:      pool,
+		accountRepo: accountRepo,
+		profileRepo: profileRepo,
+		auditRepo:   auditRepo,
+		eventRepo:   eventRepo,
+		outboxRepo:  outboxRepo,
+		avatarStore: avatarStore,
+		sessions:    sessions,
+	}
+}
+
+// SearchAccounts pages through accounts whose username or email starts with
+// query. The cursor is opaque to callers; it encodes the last username seen.
+func (u *adminUsecase) SearchAccounts(ctx context.Context, query, cursor string, limit int) (*AccountPage, error) {
+	var after string
+	if cursor != "" {
+		b, err := base64.RawURLEncoding.DecodeString(cursor)
+		if err != nil {
+			return nil, ErrInvalidCursor
+		}
+		after = string(b)
+	}
+
+	// One extra row tells whether there is a next page.
+	accounts, err := u.accountRepo.Search(ctx, u.pgPool, strings.ToLower(query), after, limit+1)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &AccountPage{Accounts: accounts}
+	if len(accounts) > limit {
+		page.Accounts = accounts[:limit]
+		page.NextCursor = base64.RawURLEncoding.EncodeToString([]byte(accounts[limit-1].Username))
+	}
+
+	return page, nil
+}
+
+func (u *adminUsecase) GetAccountDetails(ctx context.Context, accountID uuid.UUID) (*AccountDetails, error) {
+	account, err := u.getAccount(ctx, accountID)
+	if err != nil {
+		return nil, err
+	}
+
+	profiles, err := u.profileRepo.GetAllByUserID(ctx, u.pgPool, accountID)
+	if err != nil {
+		return nil, err
+	}
+
+	sessions, err := u.sessions.ActiveSessions(ctx, accountID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &AccountDetails{
+		Account:  account,
+		Profiles: profiles,
+		Sessions: sessions,
+	}, nil
+}
+
+// LockAccount blocks sign-in and signs the account out everywhere.
+func (u *adminUsecase) LockAccount(ctx context.Context, actorID, accountID uuid.UUID, reason string) error {
+	now := time.Now().UTC()
+	details := map[string]any{"reason": reason}
+
+	if err := u.updateAccount(ctx, actorID, accountID, entity.AuditAccountLocked, details, func(qe db.QueryExecutor) error {
+		if err := u.accountRepo.SetLock(ctx, qe, accountID, &now, reason); err != nil {
+			return err
+		}
+		locked := events.AccountLocked{AccountID: accountID.String()}
+		return addEvent(ctx, qe, u.outboxRepo, events.TypeAccountLocked, accountID, locked)
+	}); err != nil {
+		return err
+	}
+
+	if _, err := u.sessions.TerminateSessions(ctx, accountID); err != nil {
+		return fmt.Errorf("account locked, but sessions were not terminated: %w", err)
+	}
+
+	return nil
+}
+
+func (u *adminUsecase) UnlockAccount(ctx context.Context, actorID, accountID uuid.UUID) error {
+	return u.updateAccount(ctx, actorID, accountID, entity.AuditAccountUnlocked, nil, func(qe db.QueryExecutor) error {
+		if err := u.accountRepo.SetLock(ctx, qe, accountID, nil, ""); err != nil {
+			return err
+		}
+		unlocked := events.AccountUnlocked{AccountID: accountID.String()}
+		return addEvent(ctx, qe, u.outboxRepo, events.TypeAccountUnlocked, accountID, unlocked)
+	})
+}
+
+// ForcePasswordReset makes the account change its password before it can
+// sign in again, and signs it out everywhere.
+func (u *adminUsecase) ForcePasswordReset(ctx context.Context, actorID, accountID uuid.UUID) error {
+	if err := u.updateAccount(ctx, actorID, accountID, entity.AuditPasswordResetForced, nil, func(qe db.QueryExecutor) error {
+		if err := u.accountRepo.SetPasswordResetRequired(ctx, qe, accountID, true); err != nil {
+			return err
+		}
+		return u.eventRepo.Create(ctx, qe, staffAuthEvent(accountID, entity.AuthEventPasswordResetForced))
+	}); err != nil {
+		return err
+	}
+
+	if _, err := u.sessions.TerminateSessions(ctx, accountID); err != nil {
+		return fmt.Errorf("password reset forced, but sessions were not terminated: %w", err)
+	}
+
+	return nil
+}
+
+// ForceLogout terminates all sessions of the account and returns their number.
+func (u *adminUsecase) ForceLogout(ctx context.Context, actorID, accountID uuid.UUID) (int, error) {
+	if _, err := u.getAccount(ctx, accountID); err != nil {
+		return 0, err
+	}
+
+	n, err := u.sessions.TerminateSessions(ctx, accountID)
+	if err != nil {
+		return 0, err
+	}
+
+	entry := newAuditEntry(actorID, entity.AuditSessionsTerminated, accountID, map[string]any{"sessions": n})
+	if err := u.auditRepo.Create(ctx, u.pgPool, entry); err != nil {
+		return 0, err
+	}
+	if err := u.eventRepo.Create(ctx, u.pgPool, staffAuthEvent(accountID, entity.AuthEventSessionsTerminated)); err != nil {
+		return 0, err
+	}
+
+	return n, nil
+}
+
+func (u *adminUsecase) UpdateProfile(ctx context.Context, actorID, profileID uuid.UUID, update ProfileUpdate) (*entity.Profile, error) {
+	var (
+		profile       *entity.Profile
+		oldAvatarKey  string
+		oldThumbnails map[int]string
+	)
+	err := db.WithTx(ctx, u.pgPool, db.TxOptions{}, func(tx db.QueryExecutor) error {
+		var err error
+		profile, err = u.profileRepo.GetByID(ctx, tx, profileID)
+		if err != nil {
+			if errors.Is(err, repo.ErrNotFound) {
+				return ErrProfileNotFound
+			}
+			return err
+		}
+
+		changes := map[string]any{}
+		if update.DisplayName != nil {
+			changes["display_name"] = map[string]string{"old": profile.DisplayName, "new": *update.DisplayName}
+			profile.DisplayName = *update.DisplayName
+		}
+		if update.Bio != nil {
+			changes["bio"] = map[string]string{"old": profile.Bio, "new": *update.Bio}
+			profile.Bio = *update.Bio
+		}
+		if update.Avatar != nil {
+			changes["avatar"] = map[string]string{"old": profile.Avatar, "new": *update.Avatar}
+			oldAvatarKey, oldThumbnails = profile.AvatarKey, profile.AvatarThumbnails
+			profile.Avatar = *update.Avatar
+			profile.AvatarKey = ""
+			profile.AvatarThumbnails = nil
+		}
+
+		if err := u.profileRepo.Update(ctx, tx, profile); err != nil {
+			return err
+		}
+
+		if err := u.auditRepo.Create(ctx, tx, newAuditEntry(actorID, entity.AuditProfileUpdated, profileID, changes)); err != nil {
+			return err
+		}
+		return addEvent(ctx, tx, u.outboxRepo, events.TypeProfileUpdated, profile.ID, profileUpdatedEvent(profile))
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// An uploaded avatar replaced by staff, e.g. in moderation, is removed
+	// best effort, like one replaced by its owner.
+	_ = removeAvatarFiles(ctx, u.avatarStore, oldAvatarKey, oldThumbnails)
+
+	return profile, nil
+}
+
+// updateAccount applies update to the account and records the action in the
+// same transaction.
+func (u *adminUsecase) updateAccount(
+	ctx context.Context,
+	actorID, accountID uuid.UUID,
+	action string,
+	details map[string]any,
+	update func(qe db.QueryExecutor) error,
+) error {
+	return db.WithTx(ctx, u.pgPool, db.TxOptions{}, func(tx db.QueryExecutor) error {
+		if err := update(tx); err != nil {
+			if errors.Is(err, repo.ErrNotFound) {
+				return ErrAccountNotFound
+			}
+			return err
+		}
+
+		return u.auditRepo.Create(ctx, tx, newAuditEntry(actorID, action, accountID, details))
+	})
+}
+
+func (u *adminUsecase) getAccount(ctx context.Context, accountID uuid.UUID) (*entity.Account, error) {
+	account, err := u.accountRepo.GetById(ctx, u.pgPool, accountID)
+	if err != nil {
+		if errors.Is(err, repo.ErrNotFound) {
+			return nil, ErrAccountNotFound
+		}
+		return nil, err
+	}
+
+	return account, nil
+}
+
+func newAuditEntry(actorID uuid.UUID, action string, targetID uuid.UUID, details map[string]any) *entity.AuditEntry {
+	return &entity.AuditEntry{
+		ActorID:  &actorID,
+		Action:   action,
+		TargetID: &targetID,
+		Details:  details,
+	}
+}
+
+// staffAuthEvent is an auth event caused by staff. It carries no client info:
+// the account owner sees it in their security activity, and the staff member
+// is recorded in the audit log instead.
+func staffAuthEvent(accountID uuid.UUID, eventType string) *entity.AuthEvent {
+	return &entity.AuthEvent{
+		AccountID: &accountID,
+		Type:      eventType,
+		Outcome:   entity.AuthOutcomeSuccess,
+	}
+}
@@ -6,14 +6,19 @@ import (
 	"encoding/hex"
 	"errors"
 	"fmt"
+	"slices"
 	"strings"
 	"time"
 
+	events "github.com/SkySock/lode/libs/shared-dto/user/events/v1"
 	"github.com/SkySock/lode/libs/utils/argon2id"
+	"github.com/SkySock/lode/libs/utils/jwt"
 	"github.com/SkySock/lode/services/user-service/internal/config"
+	"github.com/SkySock/lode/services/user-service/internal/db"
 	"github.com/SkySock/lode/services/user-service/internal/entity"
+	"github.com/SkySock/lode/services/user-service/internal/metrics"
 	repo "github.com/SkySock/lode/services/user-service/internal/repository"
-	"github.com/golang-jwt/jwt"
+	gojwt "github.com/golang-jwt/jwt/v5"
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
@@ -23,14 +28,46 @@ var (
 	ErrIncorrectPassword            = errors.New("incorrect password")
 	ErrUsernameNotFound             = errors.New("username not found")
 	ErrEmailNotFound                = errors.New("email not found")
+	ErrSessionNotFound              = errors.New("session not found")
+	ErrSessionRevoked               = errors.New("session revoked")
+	ErrSessionExpired               = errors.New("session expired")
+	ErrInvalidToken                 = errors.New("invalid token")
+	ErrAccountLocked                = errors.New("account locked")
+	ErrPasswordResetRequired        = errors.New("password reset required")
+	ErrAccountDeleted               = errors.New("account deleted")
+	ErrAccountNotDeleted            = errors.New("account not deleted")
+)
+
+var passwordParams = &argon2id.Params{
+	Memory:      46 * 1024,
+	Iterations:  2,
+	Parallelism: 1,
+	KeyLength:   64,
+	SaltLength:  16,
+}
+
+const tokenIssuer = "user-service"
+
+// Token type hints of RFC 7009 and RFC 7662.
+const (
+	TokenTypeAccess  = "access_token"
+	TokenTypeRefresh = "refresh_token"
 )
 
 type authUsecase struct {
-	pgPool      *pgxpool.Pool
-	accountRepo repo.AccountRepository
-	sessionRepo repo.SessionRepository
-	profileRepo repo.ProfileRepository
-	authConfig  config.AuthConfig
+	pgPool       *pgxpool.Pool
+	accountRepo  repo.AccountRepository
+	sessionRepo  repo.SessionRepository
+	profileRepo  repo.ProfileRepository
+	roleRepo     repo.RoleRepository
+	denylist     repo.TokenDenylist
+	eventRepo    repo.AuthEventRepository
+	identityRepo repo.IdentityRepository
+	outboxRepo   repo.OutboxRepository
+	authConfig   config.AuthConfig
+	signer       *jwt.Signer
+	verifier     *jwt.Verifier
+	metrics      *metrics.AuthMetrics
 }
 
 func NewAuthUsecase(
@@ -38,110 +75,467 @@ func NewAuthUsecase(
 	accountRepo repo.AccountRepository,
 	sessionRepo repo.SessionRepository,
 	profileRepo repo.ProfileRepository,
+	roleRepo repo.RoleRepository,
+	denylist repo.TokenDenylist,
+	eventRepo repo.AuthEventRepository,
+	identityRepo repo.IdentityRepository,
+	outboxRepo repo.OutboxRepository,
 	authConfig config.AuthConfig,
+	metrics *metrics.AuthMetrics,
 ) AuthUsecase {
 	return &authUsecase{
-		pgPool:      pool,
-		accountRepo: accountRepo,
-		sessionRepo: sessionRepo,
-		profileRepo: profileRepo,
-		authConfig:  authConfig,
+		pgPool:       pool,
+		accountRepo:  accountRepo,
+		sessionRepo:  sessionRepo,
+		profileRepo:  profileRepo,
+		roleRepo:     roleRepo,
+		denylist:     denylist,
+		eventRepo:    eventRepo,
+		identityRepo: identityRepo,
+		outboxRepo:   outboxRepo,
+		authConfig:   authConfig,
+		signer:       jwt.NewSigner([]byte(authConfig.AccessSecretKey)),
+		// The service accepts tokens of every audience it issues for.
+		verifier: jwt.NewVerifier([]byte(authConfig.AccessSecretKey),
+			jwt.WithIssuer(tokenIssuer),
+			jwt.WithLeeway(authConfig.ClockSkew),
+		),
+		metrics: metrics,
 	}
 }
 
 func (u *authUsecase) RegisterUser(ctx context.Context, userData RegistrationInfo) (uuid.UUID, error) {
-	tx, err := u.pgPool.Begin(ctx)
-	if err != nil {
-		return uuid.Nil, fmt.Errorf("Begin tx: %w", err)
-	}
-	defer func() {
-		if r := recover(); r != nil {
-			_ = tx.Rollback(ctx)
-			panic(r)
-		}
-	}()
-
 	account := entity.Account{}
 	account.Email = strings.ToLower(userData.Email)
 	account.Username = strings.ToLower(userData.Username)
 
-	params := &argon2id.Params{
-		Memory:      46 * 1024,
-		Iterations:  2,
-		Parallelism: 1,
-		KeyLength:   64,
-		SaltLength:  16,
-	}
-
-	passwordHash, err := argon2id.HashPassword([]byte(userData.Password), params)
+	passwordHash, err := argon2id.HashPassword([]byte(userData.Password), passwordParams)
 	if err != nil {
-		_ = tx.Rollback(ctx)
 		return uuid.Nil, fmt.Errorf("hashing password error: %w", err)
 	}
 	account.PasswordHash = passwordHash
 
-	accountId, err := u.accountRepo.Create(ctx, tx, &account)
-	if err != nil {
-		_ = tx.Rollback(ctx)
-
-		if errors.Is(err, repo.ErrDuplicate) {
-			return uuid.Nil, ErrEmailOrUsernameAlreadyExists
+	var accountId uuid.UUID
+	err = db.WithTx(ctx, u.pgPool, db.TxOptions{}, func(tx db.QueryExecutor) error {
+		reserved, err := u.identityRepo.IsReserved(ctx, tx, account.Username, uuid.Nil)
+		if err != nil {
+			return err
+		}
+		if reserved {
+			return ErrEmailOrUsernameAlreadyExists
 		}
-		return uuid.Nil, fmt.Errorf("failed to create account: %w", err)
-	}
 
-	profile := entity.Profile{}
-	profile.UserID = accountId
-	profile.ProfileName = account.Username
+		accountId, err = u.accountRepo.Create(ctx, tx, &account)
+		if err != nil {
+			if errors.Is(err, repo.ErrDuplicate) {
+				return ErrEmailOrUsernameAlreadyExists
+			}
+			return fmt.Errorf("failed to create account: %w", err)
+		}
 
-	if _, err := u.profileRepo.Create(ctx, tx, &profile); err != nil {
-		_ = tx.Rollback(ctx)
+		profile := entity.Profile{}
+		profile.UserID = accountId
+		profile.ProfileName = account.Username
+		profile.IsDefault = true
 
-		return uuid.Nil, fmt.Errorf("failed to create profile: %w", err)
-	}
-	if err := tx.Commit(ctx); err != nil {
-		return uuid.Nil, fmt.Errorf("tx.Commit: %w", err)
+		profileID, err := u.profileRepo.Create(ctx, tx, &profile)
+		if err != nil {
+			// The profile name may still be held by an account that has been
+			// renamed since.
+			if errors.Is(err, repo.ErrDuplicate) {
+				return ErrEmailOrUsernameAlreadyExists
+			}
+			return fmt.Errorf("failed to create profile: %w", err)
+		}
+		if err := u.recordEvent(ctx, tx, &accountId, entity.AuthEventSignUp, nil); err != nil {
+			return err
+		}
+		registered := events.UserRegistered{
+			AccountID:   accountId.String(),
+			ProfileID:   profileID.String(),
+			ProfileName: profile.ProfileName,
+		}
+		return addEvent(ctx, tx, u.outboxRepo, events.TypeUserRegistered, accountId, registered)
+	})
+	if err != nil {
+		return uuid.Nil, err
 	}
+	u.metrics.SignUp()
 
 	return accountId, nil
 }
 
 func (u *authUsecase) Login(ctx context.Context, login, password string) (*AuthTokens, error) {
 	account, err := u.checkUserCredentials(ctx, login, password)
+	if err == nil && account.PasswordResetRequired {
+		err = ErrPasswordResetRequired
+	}
 	if err != nil {
+		u.metrics.SignInFailed(signInFailureReason(err))
+		if recErr := u.recordEvent(ctx, u.pgPool, accountIDOf(account), entity.AuthEventSignIn, err); recErr != nil {
+			return nil, recErr
+		}
 		return nil, err
 	}
 
-	profile, err := u.profileRepo.GetByProfileName(ctx, u.pgPool, account.Username)
+	profile, err := u.profileRepo.GetDefault(ctx, u.pgPool, account.ID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get profile by username: %w", err)
+		return nil, fmt.Errorf("failed to get default profile: %w", err)
 	}
 
-	accessJWT, err := u.generateAccessJWT(account.ID, profile.ID)
+	sessionID := uuid.NewString()
+
+	accessJWT, jti, err := u.generateAccessJWT(ctx, account.ID, profile.ID, sessionID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to generate access jwt: %w", err)
 	}
 
-	refresh, err := u.generateRefreshToken(ctx, account.ID, profile.ID)
+	refresh, err := u.generateRefreshToken(ctx, sessionID, account.ID, profile.ID, jti)
 	if err != nil {
 		return nil, fmt.Errorf("failed to generate refresh token: %w", err)
 	}
 
+	u.metrics.SignInSucceeded()
+	if err := u.recordEvent(ctx, u.pgPool, &account.ID, entity.AuthEventSignIn, nil); err != nil {
+		return nil, err
+	}
+
 	return &AuthTokens{
 		AccessToken:  accessJWT,
 		RefreshToken: refresh,
 	}, nil
 }
 
+// ChangePassword sets a new password and signs the account out everywhere. It
+// is also the way to complete a password reset forced by an admin.
+func (u *authUsecase) ChangePassword(ctx context.Context, login, password, newPassword string) error {
+	account, err := u.checkUserCredentials(ctx, login, password)
+	if err != nil {
+		if recErr := u.recordEvent(ctx, u.pgPool, accountIDOf(account), entity.AuthEventPasswordChange, err); recErr != nil {
+			return recErr
+		}
+		return err
+	}
+
+	passwordHash, err := argon2id.HashPassword([]byte(newPassword), passwordParams)
+	if err != nil {
+		return fmt.Errorf("hashing password error: %w", err)
+	}
+
+	if err := u.accountRepo.UpdatePassword(ctx, u.pgPool, account.ID, passwordHash); err != nil {
+		return fmt.Errorf("failed to update password: %w", err)
+	}
+	if err := u.recordEvent(ctx, u.pgPool, &account.ID, entity.AuthEventPasswordChange, nil); err != nil {
+		return err
+	}
+
+	if _, err := u.TerminateSessions(ctx, account.ID); err != nil {
+		return fmt.Errorf("password changed, but sessions were not terminated: %w", err)
+	}
+
+	return nil
+}
+
+// RestoreAccount cancels the deletion of an account within its grace period.
+func (u *authUsecase) RestoreAccount(ctx context.Context, login, password string) error {
+	account, err := u.checkUserCredentials(ctx, login, password)
+	if err == nil {
+		return ErrAccountNotDeleted
+	}
+	if !errors.Is(err, ErrAccountDeleted) {
+		if recErr := u.recordEvent(ctx, u.pgPool, accountIDOf(account), entity.AuthEventDeletionCancelled, err); recErr != nil {
+			return recErr
+		}
+		return err
+	}
+
+	return db.WithTx(ctx, u.pgPool, db.TxOptions{}, func(tx db.QueryExecutor) error {
+		if err := u.accountRepo.SetDeleted(ctx, tx, account.ID, nil); err != nil {
+			if errors.Is(err, repo.ErrNotFound) {
+				return ErrAccountNotFound
+			}
+			return err
+		}
+		if err := u.recordEvent(ctx, tx, &account.ID, entity.AuthEventDeletionCancelled, nil); err != nil {
+			return err
+		}
+		restored := events.AccountRestored{AccountID: account.ID.String()}
+		return addEvent(ctx, tx, u.outboxRepo, events.TypeAccountRestored, account.ID, restored)
+	})
+}
+
 func (u *authUsecase) Logout(ctx context.Context, refreshToken string) error {
-	err := u.sessionRepo.Delete(ctx, refreshToken)
+	session, err := u.sessionRepo.Get(ctx, refreshToken)
 	if err != nil {
+		if errors.Is(err, repo.ErrNotFound) {
+			return nil
+		}
+		return fmt.Errorf("failed to get session: %w", err)
+	}
+
+	if err := u.denySessionAccessToken(ctx, session); err != nil {
+		return err
+	}
+
+	if err := u.sessionRepo.Delete(ctx, refreshToken); err != nil {
 		return fmt.Errorf("failed to delete session: %w", err)
 	}
+	u.metrics.SessionRevoked()
+
+	var accountID *uuid.UUID
+	if id, err := uuid.Parse(session.UserID); err == nil {
+		accountID = &id
+	}
+
+	return u.recordEvent(ctx, u.pgPool, accountID, entity.AuthEventSignOut, nil)
+}
+
+// Refresh rotates the refresh token: the presented session is deleted and a
+// new one is issued together with a fresh access token.
+func (u *authUsecase) Refresh(ctx context.Context, refreshToken string) (*AuthTokens, error) {
+	session, err := u.ValidateSession(ctx, refreshToken)
+	if err != nil {
+		return nil, err
+	}
+
+	userID, err := uuid.Parse(session.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid session user id: %w", err)
+	}
+	profileID, err := uuid.Parse(session.ProfileID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid session profile id: %w", err)
+	}
+
+	if err := u.sessionRepo.Delete(ctx, refreshToken); err != nil {
+		return nil, fmt.Errorf("failed to delete session: %w", err)
+	}
+	u.metrics.SessionRevoked()
+
+	// The session keeps its id across rotations. Sessions created before
+	// the id was split from the refresh token get a new one.
+	sessionID := session.ID
+	if sessionID == "" || sessionID == refreshToken {
+		sessionID = uuid.NewString()
+	}
+
+	accessJWT, jti, err := u.generateAccessJWT(ctx, userID, profileID, sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate access jwt: %w", err)
+	}
+
+	refresh, err := u.generateRefreshToken(ctx, sessionID, userID, profileID, jti)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate refresh token: %w", err)
+	}
+
+	return &AuthTokens{
+		AccessToken:  accessJWT,
+		RefreshToken: refresh,
+	}, nil
+}
+
+func (u *authUsecase) ValidateSession(ctx context.Context, refreshToken string) (*entity.Session, error) {
+	session, err := u.sessionRepo.Get(ctx, refreshToken)
+	if err != nil {
+		if errors.Is(err, repo.ErrNotFound) {
+			return nil, ErrSessionNotFound
+		}
+		return nil, fmt.Errorf("failed to get session: %w", err)
+	}
+
+	if session.Revoked {
+		return nil, ErrSessionRevoked
+	}
+	if !time.Now().Before(session.ExpiresAt) {
+		return nil, ErrSessionExpired
+	}
+
+	return session, nil
+}
+
+// IntrospectAccessToken returns the claims of a valid access token. Revoked
+// tokens are reported as invalid.
+func (u *authUsecase) IntrospectAccessToken(ctx context.Context, accessToken string) (*AccessTokenClaims, error) {
+	claims, err := u.VerifyAccessToken(ctx, accessToken)
+	if err != nil {
+		return nil, err
+	}
+
+	return &AccessTokenClaims{
+		ID:          claims.ID,
+		Subject:     claims.Subject,
+		ProfileID:   claims.ProfileID,
+		SessionID:   claims.SessionID,
+		Issuer:      claims.Issuer,
+		Audience:    claims.Audience,
+		Roles:       claims.Roles,
+		Permissions: claims.Permissions,
+		IssuedAt:    claims.IssuedAt.Time,
+		ExpiresAt:   claims.ExpiresAt.Time,
+	}, nil
+}
+
+// VerifyAccessToken checks the signature, the claims and the denylist.
+func (u *authUsecase) VerifyAccessToken(ctx context.Context, accessToken string) (*jwt.Claims, error) {
+	claims, err := u.verifier.Verify(accessToken)
+	if err != nil {
+		return nil, ErrInvalidToken
+	}
+
+	denied, err := u.denylist.Contains(ctx, claims.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check token denylist: %w", err)
+	}
+	if denied {
+		return nil, ErrInvalidToken
+	}
+
+	return claims, nil
+}
+
+// RevokeAccessTokens denies the access tokens of all sessions of the account,
+// so that its clients have to refresh them and pick up changed claims.
+func (u *authUsecase) RevokeAccessTokens(ctx context.Context, accountID uuid.UUID) error {
+	tokens, err := u.sessionRepo.GetUserSessions(ctx, accountID.String())
+	if err != nil {
+		return fmt.Errorf("failed to get user sessions: %w", err)
+	}
+
+	for _, token := range tokens {
+		session, err := u.sessionRepo.Get(ctx, token)
+		if err != nil {
+			if errors.Is(err, repo.ErrNotFound) {
+				continue
+			}
+			return fmt.Errorf("failed to get session: %w", err)
+		}
+
+		if err := u.denySessionAccessToken(ctx, session); err != nil {
+			return err
+		}
+	}
 
 	return nil
 }
 
+// ActiveSessions returns the sessions of the account that can still be
+// refreshed.
+func (u *authUsecase) ActiveSessions(ctx context.Context, accountID uuid.UUID) ([]*entity.Session, error) {
+	tokens, err := u.sessionRepo.GetUserSessions(ctx, accountID.String())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user sessions: %w", err)
+	}
+
+	var sessions []*entity.Session
+	for _, token := range tokens {
+		session, err := u.ValidateSession(ctx, token)
+		if err != nil {
+			if errors.Is(err, ErrSessionNotFound) || errors.Is(err, ErrSessionRevoked) || errors.Is(err, ErrSessionExpired) {
+				continue
+			}
+			return nil, err
+		}
+		// Legacy sessions use the refresh token as their id.
+		if session.ID == token {
+			session.ID = ""
+		}
+
+		sessions = append(sessions, session)
+	}
+
+	return sessions, nil
+}
+
+// TerminateSessions signs the account out everywhere: access tokens are
+// denied and all sessions are deleted. It returns the number of sessions.
+func (u *authUsecase) TerminateSessions(ctx context.Context, accountID uuid.UUID) (int, error) {
+	if err := u.RevokeAccessTokens(ctx, accountID); err != nil {
+		return 0, err
+	}
+
+	n, err := u.sessionRepo.DeleteUserSessions(ctx, accountID.String())
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete user sessions: %w", err)
+	}
+	for range n {
+		u.metrics.SessionRevoked()
+	}
+
+	return n, nil
+}
+
+// RevokeToken revokes a refresh token together with the access token issued
+// with it, or a single access token. The hint only sets which kind is tried
+// first. Unknown and invalid tokens are ignored, as RFC 7009 requires.
+func (u *authUsecase) RevokeToken(ctx context.Context, token, hint string) error {
+	if hint == TokenTypeAccess {
+		if revoked, err := u.revokeAccessToken(ctx, token); revoked || err != nil {
+			return err
+		}
+		_, err := u.revokeRefreshToken(ctx, token)
+		return err
+	}
+
+	if revoked, err := u.revokeRefreshToken(ctx, token); revoked || err != nil {
+		return err
+	}
+	_, err := u.revokeAccessToken(ctx, token)
+	return err
+}
+
+func (u *authUsecase) revokeRefreshToken(ctx context.Context, refreshToken string) (bool, error) {
+	session, err := u.sessionRepo.Get(ctx, refreshToken)
+	if err != nil {
+		if errors.Is(err, repo.ErrNotFound) {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to get session: %w", err)
+	}
+
+	if err := u.sessionRepo.Revoke(ctx, refreshToken); err != nil {
+		if errors.Is(err, repo.ErrNotFound) {
+			return true, nil
+		}
+		return false, fmt.Errorf("failed to revoke session: %w", err)
+	}
+	if !session.Revoked {
+		u.metrics.SessionRevoked()
+	}
+
+	return true, u.denySessionAccessToken(ctx, session)
+}
+
+func (u *authUsecase) revokeAccessToken(ctx context.Context, accessToken string) (bool, error) {
+	claims, err := u.verifier.Verify(accessToken)
+	if err != nil {
+		return false, nil
+	}
+
+	if err := u.denylist.Add(ctx, claims.ID, time.Until(claims.ExpiresAt.Time)); err != nil {
+		return false, fmt.Errorf("failed to deny access token: %w", err)
+	}
+
+	return true, nil
+}
+
+// denySessionAccessToken denies the access token issued with the session. Its
+// expiry is not stored, so it is denied for a whole access token lifetime.
+func (u *authUsecase) denySessionAccessToken(ctx context.Context, session *entity.Session) error {
+	if session.AccessTokenID == "" {
+		return nil
+	}
+
+	if err := u.denylist.Add(ctx, session.AccessTokenID, u.authConfig.Lifetime.Access); err != nil {
+		return fmt.Errorf("failed to deny access token: %w", err)
+	}
+
+	return nil
+}
+
+// checkUserCredentials also returns the account with ErrIncorrectPassword,
+// ErrAccountLocked and ErrAccountDeleted, so that the failure can be
+// attributed to it.
 func (u *authUsecase) checkUserCredentials(ctx context.Context, login, password string) (*entity.Account, error) {
 	var account *entity.Account
 	var err error
@@ -168,48 +562,127 @@ func (u *authUsecase) checkUserCredentials(ctx context.Context, login, password
 	if err != nil {
 		return nil, err
 	}
+	if !check {
+		return account, ErrIncorrectPassword
+	}
 
-	if check {
-		return account, nil
+	// Checked after the password, so that the lock does not reveal that the
+	// account exists.
+	if account.Locked() {
+		return account, ErrAccountLocked
+	}
+	if account.Deleted() {
+		return account, ErrAccountDeleted
 	}
 
-	return nil, ErrIncorrectPassword
+	return account, nil
 }
 
-func (u *authUsecase) generateAccessJWT(userId, profileId uuid.UUID) (string, error) {
-	claims := jwt.MapClaims{
-		"sub":     userId.String(),
-		"profile": profileId.String(),
-		"exp":     time.Now().Add(u.authConfig.Lifetime.Access).Unix(),
-		"iss":     "user-service",
+func signInFailureReason(err error) string {
+	switch {
+	case errors.Is(err, ErrIncorrectPassword):
+		return metrics.SignInReasonIncorrectPassword
+	case errors.Is(err, ErrUsernameNotFound):
+		return metrics.SignInReasonUsernameNotFound
+	case errors.Is(err, ErrEmailNotFound):
+		return metrics.SignInReasonEmailNotFound
+	case errors.Is(err, ErrAccountLocked):
+		return metrics.SignInReasonAccountLocked
+	case errors.Is(err, ErrPasswordResetRequired):
+		return metrics.SignInReasonPasswordResetRequired
+	case errors.Is(err, ErrAccountDeleted):
+		return metrics.SignInReasonAccountDeleted
+	default:
+		return metrics.SignInReasonInternal
 	}
+}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+// recordEvent writes an auth event with the outcome opErr of an operation.
+// Internal errors are not recorded, they say nothing about the client.
+func (u *authUsecase) recordEvent(ctx context.Context, qe db.QueryExecutor, accountID *uuid.UUID, eventType string, opErr error) error {
+	event := newAuthEvent(ctx, accountID, eventType)
+	if opErr != nil {
+		reason := signInFailureReason(opErr)
+		if reason == metrics.SignInReasonInternal {
+			return nil
+		}
+		event.Outcome = entity.AuthOutcomeFailure
+		event.Reason = reason
+	}
 
-	return token.SignedString([]byte(u.authConfig.AccessSecretKey))
+	if err := u.eventRepo.Create(ctx, qe, event); err != nil {
+		return fmt.Errorf("failed to record auth event: %w", err)
+	}
+
+	return nil
+}
+
+func accountIDOf(account *entity.Account) *uuid.UUID {
+	if account == nil {
+		return nil
+	}
+	return &account.ID
 }
 
-func (u *authUsecase) generateRefreshToken(ctx context.Context, userId, profileId uuid.UUID) (string, error) {
-	token, err := u.generateOpaqueToken()
+func (u *authUsecase) generateAccessJWT(ctx context.Context, userId, profileId uuid.UUID, sessionID string) (string, string, error) {
+	roles, err := u.roleRepo.GetByAccount(ctx, u.pgPool, userId)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to get account roles: %w", err)
+	}
+
+	now := time.Now()
+	claims := &jwt.Claims{
+		RegisteredClaims: gojwt.RegisteredClaims{
+			ID:        uuid.NewString(),
+			Subject:   userId.String(),
+			Issuer:    tokenIssuer,
+			Audience:  u.authConfig.Audience,
+			IssuedAt:  gojwt.NewNumericDate(now),
+			NotBefore: gojwt.NewNumericDate(now),
+			ExpiresAt: gojwt.NewNumericDate(now.Add(u.authConfig.Lifetime.Access)),
+		},
+		ProfileID: profileId.String(),
+		SessionID: sessionID,
+	}
+	for _, role := range roles {
+		claims.Roles = append(claims.Roles, role.Name)
+		claims.Permissions = append(claims.Permissions, role.Permissions...)
+	}
+	slices.Sort(claims.Permissions)
+	claims.Permissions = slices.Compact(claims.Permissions)
+
+	signed, err := u.signer.Sign(claims)
+	if err != nil {
+		return "", "", err
+	}
+
+	return signed, claims.ID, nil
+}
+
+func (u *authUsecase) generateRefreshToken(ctx context.Context, sessionID string, userId, profileId uuid.UUID, accessTokenID string) (string, error) {
+	token, err := generateOpaqueToken()
 	if err != nil {
 		return "", err
 	}
 	session := entity.Session{
-		ID:        token,
+		ID:        sessionID,
 		UserID:    userId.String(),
 		ProfileID: profileId.String(),
 		ExpiresAt: time.Now().Add(u.authConfig.Lifetime.Refresh),
 		Revoked:   false,
+
+		AccessTokenID: accessTokenID,
 	}
 
 	if err = u.sessionRepo.Save(ctx, token, &session); err != nil {
 		return "", err
 	}
+	u.metrics.SessionCreated()
 
 	return token, nil
 }
 
-func (u *authUsecase) generateOpaqueToken() (string, error) {
+func generateOpaqueToken() (string, error) {
 	b := make([]byte, 32)
 	_, err := rand.Read(b)
 	if err != nil {
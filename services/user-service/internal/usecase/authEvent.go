@@ -0,0 +1,52 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/SkySock/lode/libs/utils/clientinfo"
+	"github.com/SkySock/lode/services/user-service/internal/entity"
+	repo "github.com/SkySock/lode/services/user-service/internal/repository"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type authEventUsecase struct {
+	pgPool    *pgxpool.Pool
+	eventRepo repo.AuthEventRepository
+}
+
+func NewAuthEventUsecase(pool *pgxpool.Pool, eventRepo repo.AuthEventRepository) AuthEventUsecase {
+	return &authEventUsecase{
+		pgPool:    pool,
+		eventRepo: eventRepo,
+	}
+}
+
+func (u *authEventUsecase) ListEvents(ctx context.Context, filter repo.AuthEventFilter, limit int) (*AuthEventPage, error) {
+	// One extra row tells whether there is a next page.
+	events, err := u.eventRepo.List(ctx, u.pgPool, filter, limit+1)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &AuthEventPage{Events: events}
+	if len(events) > limit {
+		page.Events = events[:limit]
+		page.NextCursor = events[limit-1].ID.String()
+	}
+
+	return page, nil
+}
+
+// newAuthEvent returns a successful event of the client of ctx.
+func newAuthEvent(ctx context.Context, accountID *uuid.UUID, eventType string) *entity.AuthEvent {
+	client := clientinfo.FromContext(ctx)
+
+	return &entity.AuthEvent{
+		AccountID: accountID,
+		Type:      eventType,
+		IP:        client.IP,
+		UserAgent: client.UserAgent,
+		Outcome:   entity.AuthOutcomeSuccess,
+	}
+}
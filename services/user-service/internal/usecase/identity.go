@@ -0,0 +1,254 @@
+package usecase
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/SkySock/lode/services/user-service/internal/config"
+	"github.com/SkySock/lode/services/user-service/internal/db"
+	"github.com/SkySock/lode/services/user-service/internal/entity"
+	"github.com/SkySock/lode/services/user-service/internal/mailer"
+	repo "github.com/SkySock/lode/services/user-service/internal/repository"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+var (
+	ErrUsernameTaken     = errors.New("username taken")
+	ErrEmailTaken        = errors.New("email taken")
+	ErrUsernameCooldown  = errors.New("username changed too recently")
+	ErrIdentityUnchanged = errors.New("new value equals the current one")
+)
+
+type identityUsecase struct {
+	pgPool       *pgxpool.Pool
+	accountRepo  repo.AccountRepository
+	identityRepo repo.IdentityRepository
+	emailChanges repo.EmailChangeRepository
+	eventRepo    repo.AuthEventRepository
+	mailer       mailer.Mailer
+	cfg          config.IdentityConfig
+}
+
+func NewIdentityUsecase(
+	pool *pgxpool.Pool,
+	accountRepo repo.AccountRepository,
+	identityRepo repo.IdentityRepository,
+	emailChanges repo.EmailChangeRepository,
+	eventRepo repo.AuthEventRepository,
+	mailer mailer.Mailer,
+	cfg config.IdentityConfig,
+) IdentityUsecase {
+	return &identityUsecase{
+		pgPool:       pool,
+		accountRepo:  accountRepo,
+		identityRepo: identityRepo,
+		emailChanges: emailChanges,
+		eventRepo:    eventRepo,
+		mailer:       mailer,
+		cfg:          cfg,
+	}
+}
+
+// ChangeUsername renames the account. The old username stays reserved for the
+// account, which may take it back in the meantime.
+func (u *identityUsecase) ChangeUsername(ctx context.Context, accountID uuid.UUID, password, username string) error {
+	account, err := u.getAccount(ctx, accountID)
+	if err != nil {
+		return err
+	}
+	if err := checkPassword(ctx, u.pgPool, u.eventRepo, account, password, entity.AuthEventUsernameChange); err != nil {
+		return err
+	}
+
+	if username == account.Username {
+		return ErrIdentityUnchanged
+	}
+	now := time.Now().UTC()
+	if account.UsernameChangedAt != nil && now.Before(account.UsernameChangedAt.Add(u.cfg.UsernameCooldown)) {
+		return ErrUsernameCooldown
+	}
+
+	return db.WithTx(ctx, u.pgPool, db.TxOptions{}, func(tx db.QueryExecutor) error {
+		reserved, err := u.identityRepo.IsReserved(ctx, tx, username, accountID)
+		if err != nil {
+			return err
+		}
+		if reserved {
+			return ErrUsernameTaken
+		}
+
+		if err := u.accountRepo.UpdateUsername(ctx, tx, accountID, username, now); err != nil {
+			if errors.Is(err, repo.ErrDuplicate) {
+				return ErrUsernameTaken
+			}
+			return err
+		}
+		if err := u.identityRepo.Release(ctx, tx, username); err != nil {
+			return err
+		}
+		if err := u.identityRepo.Reserve(ctx, tx, account.Username, accountID, now.Add(u.cfg.UsernameReservation)); err != nil {
+			return err
+		}
+
+		change := &entity.IdentityChange{
+			AccountID: accountID,
+			Kind:      entity.IdentityUsername,
+			OldValue:  account.Username,
+			NewValue:  username,
+		}
+		if err := u.identityRepo.AddChange(ctx, tx, change); err != nil {
+			return err
+		}
+		return u.eventRepo.Create(ctx, tx, newAuthEvent(ctx, &accountID, entity.AuthEventUsernameChange))
+	})
+}
+
+// RequestEmailChange sends a confirmation token to the new address. The email
+// is changed once the token is confirmed.
+func (u *identityUsecase) RequestEmailChange(ctx context.Context, accountID uuid.UUID, password, email string) error {
+	account, err := u.getAccount(ctx, accountID)
+	if err != nil {
+		return err
+	}
+	if err := checkPassword(ctx, u.pgPool, u.eventRepo, account, password, entity.AuthEventEmailChangeRequest); err != nil {
+		return err
+	}
+
+	if email == account.Email {
+		return ErrIdentityUnchanged
+	}
+	if _, err := u.accountRepo.GetByEmail(ctx, u.pgPool, email); err == nil {
+		return ErrEmailTaken
+	} else if !errors.Is(err, repo.ErrNotFound) {
+		return err
+	}
+
+	token, err := generateOpaqueToken()
+	if err != nil {
+		return fmt.Errorf("failed to generate token: %w", err)
+	}
+
+	change := &entity.EmailChange{AccountID: accountID, OldEmail: account.Email, NewEmail: email}
+	if err := u.emailChanges.Save(ctx, hashToken(token), change, u.cfg.EmailTokenTTL); err != nil {
+		return fmt.Errorf("failed to save email change: %w", err)
+	}
+
+	link, err := u.confirmLink(token)
+	if err != nil {
+		return err
+	}
+	msg := mailer.Message{
+		To:      email,
+		Subject: "Confirm your new email",
+		Body: fmt.Sprintf("Hi %s,\n\nOpen the link below to use this address for your Lode account:\n\n%s\n\n"+
+			"The link expires in %s. If you did not ask for this, ignore this email.\n",
+			account.Username, link, u.cfg.EmailTokenTTL),
+	}
+	if err := u.mailer.Send(ctx, msg); err != nil {
+		return fmt.Errorf("failed to send confirmation: %w", err)
+	}
+
+	return u.eventRepo.Create(ctx, u.pgPool, newAuthEvent(ctx, &accountID, entity.AuthEventEmailChangeRequest))
+}
+
+// ConfirmEmailChange applies the change of the token and notifies the old
+// address about it.
+func (u *identityUsecase) ConfirmEmailChange(ctx context.Context, token string) error {
+	change, err := u.emailChanges.Take(ctx, hashToken(token))
+	if err != nil {
+		if errors.Is(err, repo.ErrNotFound) {
+			return ErrInvalidToken
+		}
+		return err
+	}
+
+	var account *entity.Account
+	if err := db.WithTx(ctx, u.pgPool, db.TxOptions{}, func(tx db.QueryExecutor) error {
+		// A token issued before another change of the email is stale.
+		var err error
+		account, err = u.accountRepo.GetById(ctx, tx, change.AccountID)
+		if err != nil {
+			if errors.Is(err, repo.ErrNotFound) {
+				return ErrInvalidToken
+			}
+			return err
+		}
+		if account.Deleted() || account.Email != change.OldEmail {
+			return ErrInvalidToken
+		}
+
+		if err := u.accountRepo.UpdateEmail(ctx, tx, account.ID, change.NewEmail); err != nil {
+			if errors.Is(err, repo.ErrDuplicate) {
+				return ErrEmailTaken
+			}
+			return err
+		}
+
+		history := &entity.IdentityChange{
+			AccountID: account.ID,
+			Kind:      entity.IdentityEmail,
+			OldValue:  change.OldEmail,
+			NewValue:  change.NewEmail,
+		}
+		if err := u.identityRepo.AddChange(ctx, tx, history); err != nil {
+			return err
+		}
+		return u.eventRepo.Create(ctx, tx, newAuthEvent(ctx, &account.ID, entity.AuthEventEmailChange))
+	}); err != nil {
+		return err
+	}
+
+	msg := mailer.Message{
+		To:      change.OldEmail,
+		Subject: "Your email was changed",
+		Body: fmt.Sprintf("Hi %s,\n\nThe email of your Lode account was changed to %s. "+
+			"If you did not do this, contact support right away.\n",
+			account.Username, change.NewEmail),
+	}
+	if err := u.mailer.Send(ctx, msg); err != nil {
+		return fmt.Errorf("email changed, but the old address was not notified: %w", err)
+	}
+
+	return nil
+}
+
+func (u *identityUsecase) getAccount(ctx context.Context, id uuid.UUID) (*entity.Account, error) {
+	account, err := u.accountRepo.GetById(ctx, u.pgPool, id)
+	if err != nil {
+		if errors.Is(err, repo.ErrNotFound) {
+			return nil, ErrAccountNotFound
+		}
+		return nil, err
+	}
+	if account.Deleted() {
+		return nil, ErrAccountNotFound
+	}
+
+	return account, nil
+}
+
+func (u *identityUsecase) confirmLink(token string) (string, error) {
+	link, err := url.Parse(u.cfg.EmailConfirmURL)
+	if err != nil {
+		return "", fmt.Errorf("invalid email confirm url: %w", err)
+	}
+
+	query := link.Query()
+	query.Set("token", token)
+	link.RawQuery = query.Encode()
+
+	return link.String(), nil
+}
+
+// hashToken keeps confirmation tokens out of the store, so that reading it
+// does not allow to confirm changes.
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
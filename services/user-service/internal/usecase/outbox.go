@@ -0,0 +1,88 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/SkySock/lode/services/user-service/internal/broker"
+	"github.com/SkySock/lode/services/user-service/internal/db"
+	"github.com/SkySock/lode/services/user-service/internal/entity"
+	repo "github.com/SkySock/lode/services/user-service/internal/repository"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type outboxRelay struct {
+	pool      db.QueryExecutor
+	repo      repo.OutboxRepository
+	publisher broker.Publisher
+	batchSize int
+}
+
+func NewOutboxRelay(pool *pgxpool.Pool, repo repo.OutboxRepository, publisher broker.Publisher, batchSize int) OutboxRelay {
+	return &outboxRelay{
+		pool:      pool,
+		repo:      repo,
+		publisher: publisher,
+		batchSize: batchSize,
+	}
+}
+
+// Relay publishes a batch of pending events in the order they were written
+// and removes them from the outbox. It stops at the first event that fails;
+// that one and the rest are retried by the next call. An event published
+// right before a crash is published again, so delivery is at least once.
+func (r *outboxRelay) Relay(ctx context.Context) (int, error) {
+	var published []uuid.UUID
+	var publishErr error
+	err := db.WithTx(ctx, r.pool, db.TxOptions{}, func(tx db.QueryExecutor) error {
+		messages, err := r.repo.LockPending(ctx, tx, r.batchSize)
+		if err != nil {
+			return err
+		}
+
+		published = make([]uuid.UUID, 0, len(messages))
+		publishErr = nil
+		for _, msg := range messages {
+			err := r.publisher.Publish(ctx, broker.Message{
+				ID:         msg.ID.String(),
+				Type:       msg.Type,
+				Key:        msg.Key,
+				OccurredAt: msg.CreatedAt,
+				Payload:    msg.Payload,
+			})
+			if err != nil {
+				publishErr = fmt.Errorf("failed to publish event %s: %w", msg.ID, err)
+				break
+			}
+			published = append(published, msg.ID)
+		}
+
+		if len(published) == 0 {
+			return nil
+		}
+		return r.repo.Delete(ctx, tx, published)
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return len(published), publishErr
+}
+
+// addEvent writes a domain event to the outbox. Written in the transaction of
+// the change it is about, it is published if and only if the change commits.
+func addEvent(ctx context.Context, qe db.QueryExecutor, outbox repo.OutboxRepository, eventType string, key uuid.UUID, event any) error {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to encode %s event: %w", eventType, err)
+	}
+
+	msg := &entity.OutboxMessage{Type: eventType, Key: key.String(), Payload: payload}
+	if err := outbox.Add(ctx, qe, msg); err != nil {
+		return fmt.Errorf("failed to add %s event: %w", eventType, err)
+	}
+
+	return nil
+}
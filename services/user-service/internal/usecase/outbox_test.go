@@ -0,0 +1,135 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/SkySock/lode/services/user-service/internal/broker"
+	"github.com/SkySock/lode/services/user-service/internal/db"
+	"github.com/SkySock/lode/services/user-service/internal/entity"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+type fakeTx struct {
+	pgx.Tx
+}
+
+func (tx *fakeTx) Commit(ctx context.Context) error   { return nil }
+func (tx *fakeTx) Rollback(ctx context.Context) error { return nil }
+
+type fakePool struct {
+	db.QueryExecutor
+}
+
+func (p *fakePool) BeginTx(ctx context.Context, opts pgx.TxOptions) (pgx.Tx, error) {
+	return &fakeTx{}, nil
+}
+
+type fakeOutboxRepository struct {
+	pending []*entity.OutboxMessage
+}
+
+func (r *fakeOutboxRepository) Add(ctx context.Context, qe db.QueryExecutor, msg *entity.OutboxMessage) error {
+	msg.ID = uuid.New()
+	r.pending = append(r.pending, msg)
+	return nil
+}
+
+func (r *fakeOutboxRepository) LockPending(ctx context.Context, qe db.QueryExecutor, limit int) ([]*entity.OutboxMessage, error) {
+	return r.pending[:min(limit, len(r.pending))], nil
+}
+
+func (r *fakeOutboxRepository) Delete(ctx context.Context, qe db.QueryExecutor, ids []uuid.UUID) error {
+	r.pending = slices.DeleteFunc(r.pending, func(msg *entity.OutboxMessage) bool {
+		return slices.Contains(ids, msg.ID)
+	})
+	return nil
+}
+
+func (r *fakeOutboxRepository) DeleteByKeys(ctx context.Context, qe db.QueryExecutor, keys []string) error {
+	r.pending = slices.DeleteFunc(r.pending, func(msg *entity.OutboxMessage) bool {
+		return slices.Contains(keys, msg.Key)
+	})
+	return nil
+}
+
+type publisherFunc func(ctx context.Context, msg broker.Message) error
+
+func (f publisherFunc) Publish(ctx context.Context, msg broker.Message) error {
+	return f(ctx, msg)
+}
+
+func TestOutboxRelay(t *testing.T) {
+	ctx := context.Background()
+	errBroker := errors.New("broker unavailable")
+
+	outbox := &fakeOutboxRepository{}
+	for _, key := range []string{"1", "2", "3", "4", "5"} {
+		if err := addEvent(ctx, nil, outbox, "profile.updated", uuid.New(), map[string]string{"key": key}); err != nil {
+			t.Fatalf("addEvent failed: %v", err)
+		}
+	}
+	want := messageIDs(outbox.pending)
+
+	// The broker goes down after the third message.
+	memory := broker.NewMemory()
+	wentDown := false
+	publisher := publisherFunc(func(ctx context.Context, msg broker.Message) error {
+		if !wentDown && len(memory.Messages()) == 3 {
+			wentDown = true
+			memory.Err = errBroker
+		}
+		return memory.Publish(ctx, msg)
+	})
+	relay := &outboxRelay{pool: &fakePool{}, repo: outbox, publisher: publisher, batchSize: 2}
+
+	runs := []struct {
+		expectCount   int
+		expectErr     error
+		expectPending []uuid.UUID
+	}{
+		{2, nil, want[2:]},
+		{1, errBroker, want[3:]},
+		{0, errBroker, want[3:]},
+	}
+	for i, run := range runs {
+		n, err := relay.Relay(ctx)
+		if n != run.expectCount || !errors.Is(err, run.expectErr) {
+			t.Fatalf("Run %d: got (%d, %v), want (%d, %v)", i+1, n, err, run.expectCount, run.expectErr)
+		}
+		if got := messageIDs(outbox.pending); !slices.Equal(got, run.expectPending) {
+			t.Fatalf("Run %d: wrong pending messages: got %v, want %v", i+1, got, run.expectPending)
+		}
+	}
+
+	// The failed message and the ones after it are published once the broker
+	// is back.
+	memory.Err = nil
+	for range 2 {
+		if _, err := relay.Relay(ctx); err != nil {
+			t.Fatalf("Relay failed: %v", err)
+		}
+	}
+	if len(outbox.pending) != 0 {
+		t.Errorf("Outbox should be empty, %d messages left", len(outbox.pending))
+	}
+
+	var got []uuid.UUID
+	for _, msg := range memory.Messages() {
+		got = append(got, uuid.MustParse(msg.ID))
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("Wrong published messages: got %v, want %v", got, want)
+	}
+}
+
+func messageIDs(messages []*entity.OutboxMessage) []uuid.UUID {
+	ids := make([]uuid.UUID, 0, len(messages))
+	for _, msg := range messages {
+		ids = append(ids, msg.ID)
+	}
+	return ids
+}
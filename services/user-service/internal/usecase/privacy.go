@@ -0,0 +1,135 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"maps"
+
+	"github.com/SkySock/lode/services/user-service/internal/db"
+	"github.com/SkySock/lode/services/user-service/internal/entity"
+	"github.com/SkySock/lode/services/user-service/internal/repository"
+	"github.com/google/uuid"
+)
+
+var ErrProfilePrivate = errors.New("profile is private")
+
+// ProfileView is a profile as its audience sees it.
+type ProfileView struct {
+	Profile  *entity.Profile
+	Audience entity.Audience
+}
+
+// PrivacyUpdate changes only the settings that are set.
+type PrivacyUpdate struct {
+	Private          *bool
+	BioVisibility    *string
+	AvatarVisibility *string
+	Discoverable     *bool
+}
+
+func (u PrivacyUpdate) apply(privacy *entity.ProfilePrivacy) {
+	if u.Private != nil {
+		privacy.Private = *u.Private
+	}
+	if u.BioVisibility != nil {
+		privacy.BioVisibility = *u.BioVisibility
+	}
+	if u.AvatarVisibility != nil {
+		privacy.AvatarVisibility = *u.AvatarVisibility
+	}
+	if u.Discoverable != nil {
+		privacy.Discoverable = *u.Discoverable
+	}
+}
+
+// profileAudience tells who the viewer is to the profile, after checking that
+// the viewer profile belongs to the viewer. The owning account sees it as the
+// owner; a viewer profile sees it as a follower when it follows the profile.
+// A profile blocking the viewer profile, or blocked by it, is not found.
+func profileAudience(
+	ctx context.Context,
+	qe db.QueryExecutor,
+	profileRepo repository.ProfileRepository,
+	relationRepo repository.RelationRepository,
+	profile *entity.Profile,
+	viewer *Viewer,
+) (entity.Audience, error) {
+	if viewer == nil {
+		return entity.AudienceAnonymous, nil
+	}
+
+	if viewer.ProfileID != uuid.Nil {
+		viewerProfile, err := profileRepo.GetByID(ctx, qe, viewer.ProfileID)
+		if err != nil {
+			if errors.Is(err, repository.ErrNotFound) {
+				return 0, ErrProfileNotFound
+			}
+			return 0, err
+		}
+		if viewerProfile.UserID != viewer.AccountID {
+			return 0, ErrNotProfileOwner
+		}
+	}
+
+	if viewer.AccountID == profile.UserID {
+		return entity.AudienceOwner, nil
+	}
+	if viewer.ProfileID == uuid.Nil {
+		return entity.AudienceAnonymous, nil
+	}
+
+	blocked, err := relationRepo.IsBlocked(ctx, qe, profile.ID, viewer.ProfileID)
+	if err != nil {
+		return 0, err
+	}
+	if blocked {
+		return 0, ErrProfileNotFound
+	}
+
+	following, err := relationRepo.IsFollowing(ctx, qe, viewer.ProfileID, profile.ID)
+	if err != nil {
+		return 0, err
+	}
+	if following {
+		return entity.AudienceFollower, nil
+	}
+	return entity.AudienceAnonymous, nil
+}
+
+// visibleProfile returns a copy of the profile without the fields the
+// audience may not see.
+func visibleProfile(profile *entity.Profile, audience entity.Audience) *entity.Profile {
+	visible := *profile
+	visible.AvatarThumbnails = maps.Clone(profile.AvatarThumbnails)
+
+	if !shows(profile.Privacy, profile.Privacy.BioVisibility, audience) {
+		visible.Bio = ""
+	}
+	if !shows(profile.Privacy, profile.Privacy.AvatarVisibility, audience) {
+		visible.Avatar = ""
+		visible.AvatarKey = ""
+		visible.AvatarThumbnails = nil
+	}
+
+	return &visible
+}
+
+// showsFollows reports whether the audience sees who the profile follows and
+// is followed by.
+func showsFollows(privacy entity.ProfilePrivacy, audience entity.Audience) bool {
+	return !privacy.Private || audience >= entity.AudienceFollower
+}
+
+// shows reports whether the audience sees a field of the given visibility.
+func shows(privacy entity.ProfilePrivacy, visibility string, audience entity.Audience) bool {
+	switch {
+	case audience == entity.AudienceOwner:
+		return true
+	case visibility == entity.VisibilityNobody:
+		return false
+	case visibility == entity.VisibilityFollowers || privacy.Private:
+		return audience == entity.AudienceFollower
+	default:
+		return true
+	}
+}
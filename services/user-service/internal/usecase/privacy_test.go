@@ -0,0 +1,162 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/SkySock/lode/services/user-service/internal/entity"
+	"github.com/google/uuid"
+)
+
+func TestProfileAudience(t *testing.T) {
+	profile := newTestAccount("alice", entity.ProfilePrivacy{})
+	ownerProfile := &entity.Profile{ID: uuid.New(), UserID: profile.UserID, ProfileName: "alice-work"}
+	follower := newTestAccount("bob", entity.ProfilePrivacy{})
+	stranger := newTestAccount("carol", entity.ProfilePrivacy{})
+	blocked := newTestAccount("dave", entity.ProfilePrivacy{})
+	profiles := &fakeProfileRepository{profiles: []*entity.Profile{profile, ownerProfile, follower, stranger, blocked}}
+
+	relations := newFakeRelationRepository(profiles)
+	relations.follows[profilePair{follower.ID, profile.ID}] = true
+	relations.follows[profilePair{blocked.ID, profile.ID}] = true
+	relations.blocks[profilePair{profile.ID, blocked.ID}] = true
+
+	testCases := []struct {
+		name           string
+		viewer         *Viewer
+		expectAudience entity.Audience
+		expectErr      error
+	}{
+		{"Anonymous", nil, entity.AudienceAnonymous, nil},
+		{"Owner without profile", &Viewer{AccountID: profile.UserID}, entity.AudienceOwner, nil},
+		{"Owner as the profile", &Viewer{AccountID: profile.UserID, ProfileID: profile.ID}, entity.AudienceOwner, nil},
+		{"Owner as another profile", &Viewer{AccountID: profile.UserID, ProfileID: ownerProfile.ID}, entity.AudienceOwner, nil},
+		{"Signed in without profile", &Viewer{AccountID: follower.UserID}, entity.AudienceAnonymous, nil},
+		{"Follower", &Viewer{AccountID: follower.UserID, ProfileID: follower.ID}, entity.AudienceFollower, nil},
+		{"Not a follower", &Viewer{AccountID: stranger.UserID, ProfileID: stranger.ID}, entity.AudienceAnonymous, nil},
+		{"Blocked follower", &Viewer{AccountID: blocked.UserID, ProfileID: blocked.ID}, 0, ErrProfileNotFound},
+		{"Profile of another account", &Viewer{AccountID: stranger.UserID, ProfileID: follower.ID}, 0, ErrNotProfileOwner},
+		{"Owner as a profile of another account", &Viewer{AccountID: profile.UserID, ProfileID: follower.ID}, 0, ErrNotProfileOwner},
+		{"Unknown profile", &Viewer{AccountID: stranger.UserID, ProfileID: uuid.New()}, 0, ErrProfileNotFound},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			audience, err := profileAudience(context.Background(), nil, profiles, relations, profile, tc.viewer)
+			if !errors.Is(err, tc.expectErr) {
+				t.Fatalf("Wrong error: got %v, want %v", err, tc.expectErr)
+			}
+			if audience != tc.expectAudience {
+				t.Errorf("Wrong audience: got %v, want %v", audience, tc.expectAudience)
+			}
+		})
+	}
+}
+
+func TestShows(t *testing.T) {
+	public := entity.ProfilePrivacy{}
+	private := entity.ProfilePrivacy{Private: true}
+
+	testCases := []struct {
+		name       string
+		privacy    entity.ProfilePrivacy
+		visibility string
+		expect     [3]bool // anonymous, follower, owner
+	}{
+		{"Everyone", public, entity.VisibilityEveryone, [3]bool{true, true, true}},
+		{"Followers", public, entity.VisibilityFollowers, [3]bool{false, true, true}},
+		{"Nobody", public, entity.VisibilityNobody, [3]bool{false, false, true}},
+		{"Everyone of private profile", private, entity.VisibilityEveryone, [3]bool{false, true, true}},
+		{"Followers of private profile", private, entity.VisibilityFollowers, [3]bool{false, true, true}},
+		{"Nobody of private profile", private, entity.VisibilityNobody, [3]bool{false, false, true}},
+	}
+
+	audiences := [3]entity.Audience{entity.AudienceAnonymous, entity.AudienceFollower, entity.AudienceOwner}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			for i, audience := range audiences {
+				if got := shows(tc.privacy, tc.visibility, audience); got != tc.expect[i] {
+					t.Errorf("Wrong visibility for audience %v: got %v, want %v", audience, got, tc.expect[i])
+				}
+			}
+		})
+	}
+}
+
+func TestVisibleProfile(t *testing.T) {
+	profile := &entity.Profile{
+		ID:               uuid.New(),
+		ProfileName:      "alice",
+		DisplayName:      "Alice",
+		Bio:              "Hello",
+		Avatar:           "https://media.example.com/avatars/alice/256.jpg",
+		AvatarKey:        "avatars/alice",
+		AvatarThumbnails: map[int]string{64: "https://media.example.com/avatars/alice/64.jpg"},
+		Privacy: entity.ProfilePrivacy{
+			BioVisibility:    entity.VisibilityFollowers,
+			AvatarVisibility: entity.VisibilityEveryone,
+		},
+	}
+
+	testCases := []struct {
+		name         string
+		audience     entity.Audience
+		expectBio    bool
+		expectAvatar bool
+	}{
+		{"Anonymous", entity.AudienceAnonymous, false, true},
+		{"Follower", entity.AudienceFollower, true, true},
+		{"Owner", entity.AudienceOwner, true, true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			visible := visibleProfile(profile, tc.audience)
+
+			if (visible.Bio != "") != tc.expectBio {
+				t.Errorf("Wrong bio: got %q, shown %v", visible.Bio, tc.expectBio)
+			}
+			if (visible.Avatar != "" && visible.AvatarKey != "" && len(visible.AvatarThumbnails) > 0) != tc.expectAvatar {
+				t.Errorf("Wrong avatar: got %q, shown %v", visible.Avatar, tc.expectAvatar)
+			}
+			if visible.ProfileName != profile.ProfileName || visible.DisplayName != profile.DisplayName {
+				t.Errorf("Names should always be visible: got %q and %q", visible.ProfileName, visible.DisplayName)
+			}
+		})
+	}
+
+	t.Run("Hidden avatar", func(t *testing.T) {
+		hidden := *profile
+		hidden.Privacy.AvatarVisibility = entity.VisibilityNobody
+
+		visible := visibleProfile(&hidden, entity.AudienceFollower)
+		if visible.Avatar != "" || visible.AvatarKey != "" || visible.AvatarThumbnails != nil {
+			t.Errorf("Avatar should be hidden: got %q, %q, %v", visible.Avatar, visible.AvatarKey, visible.AvatarThumbnails)
+		}
+	})
+
+	t.Run("Original unchanged", func(t *testing.T) {
+		visible := visibleProfile(profile, entity.AudienceAnonymous)
+		visible.AvatarThumbnails[128] = "https://media.example.com/avatars/alice/128.jpg"
+
+		if profile.Bio != "Hello" || len(profile.AvatarThumbnails) != 1 {
+			t.Errorf("visibleProfile changed the profile: bio %q, thumbnails %v", profile.Bio, profile.AvatarThumbnails)
+		}
+	})
+}
+
+func TestProfileUpdatedEventIsRedacted(t *testing.T) {
+	profile := newTestAccount("alice", entity.ProfilePrivacy{
+		BioVisibility:    entity.VisibilityFollowers,
+		AvatarVisibility: entity.VisibilityNobody,
+	})
+
+	event := profileUpdatedEvent(profile)
+	if event.Bio != "" || event.Avatar != "" {
+		t.Errorf("Event carries hidden bio %q and avatar %q", event.Bio, event.Avatar)
+	}
+	if event.ProfileName != profile.ProfileName {
+		t.Errorf("Wrong profile name: got %q, want %q", event.ProfileName, profile.ProfileName)
+	}
+}
@@ -2,30 +2,286 @@ package usecase
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
 
+	events "github.com/SkySock/lode/libs/shared-dto/user/events/v1"
+	"github.com/SkySock/lode/services/user-service/internal/avatar"
+	"github.com/SkySock/lode/services/user-service/internal/db"
 	"github.com/SkySock/lode/services/user-service/internal/entity"
 	"github.com/SkySock/lode/services/user-service/internal/repository"
+	"github.com/SkySock/lode/services/user-service/internal/storage"
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
+var (
+	ErrProfileNotFound = errors.New("profile not found")
+	ErrNotProfileOwner = errors.New("profile belongs to another account")
+)
+
 type profileUsecase struct {
-	pgPool *pgxpool.Pool
-	repo   repository.ProfileRepository
+	pgPool       db.QueryExecutor
+	repo         repository.ProfileRepository
+	relationRepo repository.RelationRepository
+	outboxRepo   repository.OutboxRepository
+	avatarStore  storage.BlobStore
+	avatars      *avatar.Processor
 }
 
-func NewProfileUsecase(pgPool *pgxpool.Pool, repo repository.ProfileRepository) ProfileUsecase {
+func NewProfileUsecase(
+	pgPool *pgxpool.Pool,
+	repo repository.ProfileRepository,
+	relationRepo repository.RelationRepository,
+	outboxRepo repository.OutboxRepository,
+	avatarStore storage.BlobStore,
+	avatars *avatar.Processor,
+) ProfileUsecase {
 	return &profileUsecase{
-		pgPool: pgPool,
-		repo:   repo,
+		pgPool:       pgPool,
+		repo:         repo,
+		relationRepo: relationRepo,
+		outboxRepo:   outboxRepo,
+		avatarStore:  avatarStore,
+		avatars:      avatars,
 	}
 }
 
 func (uc *profileUsecase) GetUserProfile(ctx context.Context, id uuid.UUID) (*entity.Profile, error) {
 	profile, err := uc.repo.GetByID(ctx, uc.pgPool, id)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrProfileNotFound
+		}
+		return nil, err
+	}
+
+	return profile, nil
+}
+
+func (uc *profileUsecase) GetProfileByName(ctx context.Context, name string) (*entity.Profile, error) {
+	profile, err := uc.repo.GetByProfileName(ctx, uc.pgPool, strings.ToLower(name))
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrProfileNotFound
+		}
+		return nil, err
+	}
+
+	return profile, nil
+}
+
+// ViewProfile returns the profile without the fields the viewer may not see.
+// A nil viewer is anonymous.
+func (uc *profileUsecase) ViewProfile(ctx context.Context, name string, viewer *Viewer) (*ProfileView, error) {
+	profile, err := uc.GetProfileByName(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	audience, err := profileAudience(ctx, uc.pgPool, uc.repo, uc.relationRepo, profile, viewer)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ProfileView{Profile: visibleProfile(profile, audience), Audience: audience}, nil
+}
+
+func (uc *profileUsecase) GetProfilesByIDs(ctx context.Context, ids []uuid.UUID) ([]*entity.Profile, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	return uc.repo.GetByIDs(ctx, uc.pgPool, ids)
+}
+
+// SearchProfiles pages through discoverable profiles matching query by prefix
+// or similarity, in the order they were created, as anyone sees them. The
+// cursor is the ID of the last profile seen. With a viewer profile, profiles
+// it blocks or is blocked by are left out.
+func (uc *profileUsecase) SearchProfiles(ctx context.Context, query string, viewer *Viewer, cursor string, limit int) (*ProfilePage, error) {
+	var after *uuid.UUID
+	if cursor != "" {
+		id, err := uuid.Parse(cursor)
+		if err != nil {
+			return nil, ErrInvalidCursor
+		}
+		after = &id
+	}
+
+	var viewerID *uuid.UUID
+	if viewer != nil && viewer.ProfileID != uuid.Nil {
+		if _, err := uc.ownProfile(ctx, viewer.AccountID, viewer.ProfileID); err != nil {
+			return nil, err
+		}
+		viewerID = &viewer.ProfileID
+	}
+
+	// One extra row tells whether there is a next page.
+	profiles, err := uc.repo.Search(ctx, uc.pgPool, strings.ToLower(strings.TrimSpace(query)), viewerID, after, limit+1)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &ProfilePage{Profiles: profiles}
+	if len(profiles) > limit {
+		page.Profiles = profiles[:limit]
+		page.NextCursor = profiles[limit-1].ID.String()
+	}
+	for i, profile := range page.Profiles {
+		page.Profiles[i] = visibleProfile(profile, entity.AudienceAnonymous)
+	}
+
+	return page, nil
+}
+
+// UpdateAvatar replaces the avatar of a profile of the account with thumbnails
+// of image. Image errors of the avatar package are returned as they are.
+func (uc *profileUsecase) UpdateAvatar(ctx context.Context, accountID, profileID uuid.UUID, image []byte) (*entity.Profile, error) {
+	profile, err := uc.ownProfile(ctx, accountID, profileID)
+	if err != nil {
+		return nil, err
+	}
+
+	thumbnails, err := uc.avatars.Thumbnails(image)
+	if err != nil {
+		return nil, err
+	}
+
+	// Every upload gets new keys, so that caches never serve a replaced avatar.
+	version, err := uuid.NewV7()
 	if err != nil {
 		return nil, err
 	}
+	key := "avatars/" + profileID.String() + "/" + version.String()
+
+	urls := make(map[int]string, len(thumbnails))
+	for size, data := range thumbnails {
+		blobKey := avatarBlobKey(key, size)
+		if err := uc.avatarStore.Put(ctx, blobKey, avatar.ContentType, data); err != nil {
+			_ = removeAvatarFiles(ctx, uc.avatarStore, key, urls)
+			return nil, fmt.Errorf("failed to store avatar: %w", err)
+		}
+		urls[size] = uc.avatarStore.URL(blobKey)
+	}
+
+	oldKey, oldThumbnails := profile.AvatarKey, profile.AvatarThumbnails
+	profile.Avatar = urls[slices.Max(uc.avatars.Sizes())]
+	profile.AvatarKey = key
+	profile.AvatarThumbnails = urls
+
+	if err := uc.saveProfile(ctx, profile); err != nil {
+		_ = removeAvatarFiles(ctx, uc.avatarStore, key, urls)
+		return nil, err
+	}
+
+	// Files of the replaced avatar are no longer referenced; failing to remove
+	// them only wastes space.
+	_ = removeAvatarFiles(ctx, uc.avatarStore, oldKey, oldThumbnails)
 
 	return profile, nil
 }
+
+func (uc *profileUsecase) DeleteAvatar(ctx context.Context, accountID, profileID uuid.UUID) error {
+	profile, err := uc.ownProfile(ctx, accountID, profileID)
+	if err != nil {
+		return err
+	}
+
+	oldKey, oldThumbnails := profile.AvatarKey, profile.AvatarThumbnails
+	profile.Avatar = ""
+	profile.AvatarKey = ""
+	profile.AvatarThumbnails = nil
+
+	if err := uc.saveProfile(ctx, profile); err != nil {
+		return err
+	}
+
+	_ = removeAvatarFiles(ctx, uc.avatarStore, oldKey, oldThumbnails)
+
+	return nil
+}
+
+func (uc *profileUsecase) UpdatePrivacy(ctx context.Context, accountID, profileID uuid.UUID, update PrivacyUpdate) (*entity.Profile, error) {
+	profile, err := uc.ownProfile(ctx, accountID, profileID)
+	if err != nil {
+		return nil, err
+	}
+
+	update.apply(&profile.Privacy)
+	err = db.WithTx(ctx, uc.pgPool, db.TxOptions{}, func(tx db.QueryExecutor) error {
+		if err := uc.repo.UpdatePrivacy(ctx, tx, profile.ID, profile.Privacy); err != nil {
+			if errors.Is(err, repository.ErrNotFound) {
+				return ErrProfileNotFound
+			}
+			return err
+		}
+		// Consumers learn which fields are no longer public.
+		return addEvent(ctx, tx, uc.outboxRepo, events.TypeProfileUpdated, profile.ID, profileUpdatedEvent(profile))
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return profile, nil
+}
+
+func (uc *profileUsecase) ownProfile(ctx context.Context, accountID, profileID uuid.UUID) (*entity.Profile, error) {
+	profile, err := uc.GetUserProfile(ctx, profileID)
+	if err != nil {
+		return nil, err
+	}
+	if profile.UserID != accountID {
+		return nil, ErrNotProfileOwner
+	}
+
+	return profile, nil
+}
+
+func (uc *profileUsecase) saveProfile(ctx context.Context, profile *entity.Profile) error {
+	return db.WithTx(ctx, uc.pgPool, db.TxOptions{}, func(tx db.QueryExecutor) error {
+		if err := uc.repo.Update(ctx, tx, profile); err != nil {
+			if errors.Is(err, repository.ErrNotFound) {
+				return ErrProfileNotFound
+			}
+			return err
+		}
+		return addEvent(ctx, tx, uc.outboxRepo, events.TypeProfileUpdated, profile.ID, profileUpdatedEvent(profile))
+	})
+}
+
+// profileUpdatedEvent carries the profile as anyone sees it, since the event
+// reaches partners through webhooks.
+func profileUpdatedEvent(profile *entity.Profile) events.ProfileUpdated {
+	profile = visibleProfile(profile, entity.AudienceAnonymous)
+	return events.ProfileUpdated{
+		ProfileID:   profile.ID.String(),
+		AccountID:   profile.UserID.String(),
+		ProfileName: profile.ProfileName,
+		DisplayName: profile.DisplayName,
+		Bio:         profile.Bio,
+		Avatar:      profile.Avatar,
+	}
+}
+
+func avatarBlobKey(key string, size int) string {
+	return fmt.Sprintf("%s/%d.jpg", key, size)
+}
+
+// removeAvatarFiles removes the thumbnails of an uploaded avatar. There is
+// nothing to remove when key is empty.
+func removeAvatarFiles(ctx context.Context, store storage.BlobStore, key string, thumbnails map[int]string) error {
+	if key == "" {
+		return nil
+	}
+
+	var errs []error
+	for size := range thumbnails {
+		if err := store.Delete(ctx, avatarBlobKey(key, size)); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,211 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/SkySock/lode/services/user-service/internal/db"
+	"github.com/SkySock/lode/services/user-service/internal/entity"
+	"github.com/SkySock/lode/services/user-service/internal/repository"
+	"github.com/google/uuid"
+)
+
+type fakeProfileRepository struct {
+	repository.ProfileRepository
+	profiles []*entity.Profile
+	// blocks lists the profiles blocked with a profile either way.
+	blocks map[uuid.UUID][]uuid.UUID
+	terms  []string
+}
+
+func (r *fakeProfileRepository) GetByID(ctx context.Context, qe db.QueryExecutor, id uuid.UUID) (*entity.Profile, error) {
+	for _, profile := range r.profiles {
+		if profile.ID == id {
+			return profile, nil
+		}
+	}
+	return nil, repository.ErrNotFound
+}
+
+func (r *fakeProfileRepository) GetByProfileName(ctx context.Context, qe db.QueryExecutor, name string) (*entity.Profile, error) {
+	for _, profile := range r.profiles {
+		if profile.ProfileName == name {
+			return profile, nil
+		}
+	}
+	return nil, repository.ErrNotFound
+}
+
+// Search matches profiles by name prefix, ordered by ID like the real query.
+func (r *fakeProfileRepository) Search(ctx context.Context, qe db.QueryExecutor, term string, viewerID, after *uuid.UUID, limit int) ([]*entity.Profile, error) {
+	r.terms = append(r.terms, term)
+
+	var found []*entity.Profile
+	for _, profile := range r.profiles {
+		if !strings.HasPrefix(profile.ProfileName, term) {
+			continue
+		}
+		if viewerID != nil && slices.Contains(r.blocks[*viewerID], profile.ID) {
+			continue
+		}
+		if after != nil && bytes.Compare(profile.ID[:], after[:]) <= 0 {
+			continue
+		}
+		found = append(found, profile)
+	}
+	slices.SortFunc(found, func(a, b *entity.Profile) int {
+		return bytes.Compare(a.ID[:], b.ID[:])
+	})
+	return found[:min(limit, len(found))], nil
+}
+
+func newTestProfiles(names ...string) []*entity.Profile {
+	profiles := make([]*entity.Profile, 0, len(names))
+	for i, name := range names {
+		profiles = append(profiles, &entity.Profile{
+			ID:          uuid.MustParse(fmt.Sprintf("00000000-0000-0000-0000-%012d", i+1)),
+			ProfileName: name,
+		})
+	}
+	return profiles
+}
+
+func TestSearchProfiles(t *testing.T) {
+	profiles := newTestProfiles("alice", "alex", "bob", "alina", "albert")
+	accountID := uuid.New()
+	profiles[2].UserID = accountID
+	blocks := map[uuid.UUID][]uuid.UUID{profiles[2].ID: {profiles[0].ID, profiles[3].ID}}
+
+	testCases := []struct {
+		name         string
+		query        string
+		viewer       *Viewer
+		cursor       string
+		limit        int
+		expectNames  []string
+		expectCursor string
+		expectErr    error
+	}{
+		{
+			name:         "First page",
+			query:        "al",
+			limit:        2,
+			expectNames:  []string{"alice", "alex"},
+			expectCursor: profiles[1].ID.String(),
+		},
+		{
+			name:        "Last page",
+			query:       "al",
+			cursor:      profiles[1].ID.String(),
+			limit:       3,
+			expectNames: []string{"alina", "albert"},
+		},
+		{
+			name:        "Exact page",
+			query:       "al",
+			limit:       4,
+			expectNames: []string{"alice", "alex", "alina", "albert"},
+		},
+		{
+			name:        "Query is normalized",
+			query:       "  BO ",
+			limit:       2,
+			expectNames: []string{"bob"},
+		},
+		{
+			name:        "Nothing found",
+			query:       "carol",
+			limit:       2,
+			expectNames: []string{},
+		},
+		{
+			name:        "Blocked profiles left out",
+			query:       "al",
+			viewer:      &Viewer{AccountID: accountID, ProfileID: profiles[2].ID},
+			limit:       3,
+			expectNames: []string{"alex", "albert"},
+		},
+		{
+			name:      "Viewer of another account",
+			query:     "al",
+			viewer:    &Viewer{AccountID: uuid.New(), ProfileID: profiles[2].ID},
+			limit:     2,
+			expectErr: ErrNotProfileOwner,
+		},
+		{
+			name:      "Viewer not found",
+			query:     "al",
+			viewer:    &Viewer{AccountID: accountID, ProfileID: uuid.New()},
+			limit:     2,
+			expectErr: ErrProfileNotFound,
+		},
+		{
+			name:      "Invalid cursor",
+			query:     "al",
+			cursor:    "not-a-cursor",
+			limit:     2,
+			expectErr: ErrInvalidCursor,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			uc := &profileUsecase{repo: &fakeProfileRepository{profiles: profiles, blocks: blocks}}
+
+			page, err := uc.SearchProfiles(context.Background(), tc.query, tc.viewer, tc.cursor, tc.limit)
+			if !errors.Is(err, tc.expectErr) {
+				t.Fatalf("Wrong error: got %v, want %v", err, tc.expectErr)
+			}
+			if tc.expectErr != nil {
+				return
+			}
+
+			names := make([]string, 0, len(page.Profiles))
+			for _, profile := range page.Profiles {
+				names = append(names, profile.ProfileName)
+			}
+			if !slices.Equal(names, tc.expectNames) {
+				t.Errorf("Wrong profiles: got %v, want %v", names, tc.expectNames)
+			}
+			if page.NextCursor != tc.expectCursor {
+				t.Errorf("Wrong next cursor: got %q, want %q", page.NextCursor, tc.expectCursor)
+			}
+		})
+	}
+}
+
+func TestSearchProfilesFollowsCursor(t *testing.T) {
+	repo := &fakeProfileRepository{profiles: newTestProfiles("alice", "alex", "bob", "alina", "albert")}
+	uc := &profileUsecase{repo: repo}
+
+	var (
+		names  []string
+		cursor string
+	)
+	for {
+		page, err := uc.SearchProfiles(context.Background(), "al", nil, cursor, 2)
+		if err != nil {
+			t.Fatalf("SearchProfiles failed: %v", err)
+		}
+		for _, profile := range page.Profiles {
+			names = append(names, profile.ProfileName)
+		}
+		if page.NextCursor == "" {
+			break
+		}
+		cursor = page.NextCursor
+	}
+
+	want := []string{"alice", "alex", "alina", "albert"}
+	if !slices.Equal(names, want) {
+		t.Errorf("Wrong profiles: got %v, want %v", names, want)
+	}
+	if len(repo.terms) != 2 {
+		t.Errorf("Wrong number of queries: got %d, want 2", len(repo.terms))
+	}
+}
@@ -0,0 +1,387 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+
+	events "github.com/SkySock/lode/libs/shared-dto/user/events/v1"
+	"github.com/SkySock/lode/services/user-service/internal/db"
+	"github.com/SkySock/lode/services/user-service/internal/entity"
+	"github.com/SkySock/lode/services/user-service/internal/repository"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+var (
+	ErrSelfRelation = errors.New("profile can not follow, block or mute itself")
+	ErrBlocked      = errors.New("profiles block each other")
+
+	ErrFollowRequestNotFound = errors.New("follow request not found")
+)
+
+// Follows and blocks are serializable, so that a follow can not slip past a
+// concurrent block of its profiles.
+var relationTxOptions = db.TxOptions{IsoLevel: pgx.Serializable}
+
+type relationUsecase struct {
+	pgPool      db.QueryExecutor
+	repo        repository.RelationRepository
+	profileRepo repository.ProfileRepository
+	outboxRepo  repository.OutboxRepository
+}
+
+func NewRelationUsecase(
+	pgPool *pgxpool.Pool,
+	repo repository.RelationRepository,
+	profileRepo repository.ProfileRepository,
+	outboxRepo repository.OutboxRepository,
+) RelationUsecase {
+	return &relationUsecase{
+		pgPool:      pgPool,
+		repo:        repo,
+		profileRepo: profileRepo,
+		outboxRepo:  outboxRepo,
+	}
+}
+
+// Follow makes the profile of the account follow the target. A private target
+// is only requested to be followed, until its owner approves the request;
+// requested reports whether it was. Following twice is not an error.
+func (uc *relationUsecase) Follow(ctx context.Context, accountID, profileID, targetID uuid.UUID) (requested bool, err error) {
+	if err := uc.checkTarget(ctx, accountID, profileID, targetID); err != nil {
+		return false, err
+	}
+
+	err = db.WithTx(ctx, uc.pgPool, relationTxOptions, func(tx db.QueryExecutor) error {
+		requested = false
+
+		// The target is read again, so that the follow can not slip past a
+		// concurrent change of its privacy.
+		target, err := uc.profileRepo.GetByID(ctx, tx, targetID)
+		if err != nil {
+			if errors.Is(err, repository.ErrNotFound) {
+				return ErrProfileNotFound
+			}
+			return err
+		}
+		if !target.Privacy.Private {
+			if _, err := uc.repo.RemoveFollowRequest(ctx, tx, profileID, targetID); err != nil {
+				return err
+			}
+			return uc.follow(ctx, tx, profileID, targetID)
+		}
+
+		following, err := uc.repo.IsFollowing(ctx, tx, profileID, targetID)
+		if err != nil || following {
+			return err
+		}
+		blocked, err := uc.repo.IsBlocked(ctx, tx, profileID, targetID)
+		if err != nil {
+			return err
+		}
+		if blocked {
+			return ErrBlocked
+		}
+
+		if err := uc.repo.RequestFollow(ctx, tx, profileID, targetID); err != nil {
+			if errors.Is(err, repository.ErrNotFound) {
+				return ErrProfileNotFound
+			}
+			return err
+		}
+		requested = true
+		return nil
+	})
+	return requested, err
+}
+
+// Unfollow also withdraws a request to follow the target.
+func (uc *relationUsecase) Unfollow(ctx context.Context, accountID, profileID, targetID uuid.UUID) error {
+	if _, err := uc.ownProfile(ctx, accountID, profileID); err != nil {
+		return err
+	}
+
+	return db.WithTx(ctx, uc.pgPool, db.TxOptions{}, func(tx db.QueryExecutor) error {
+		if _, err := uc.repo.RemoveFollowRequest(ctx, tx, profileID, targetID); err != nil {
+			return err
+		}
+		return uc.unfollow(ctx, tx, profileID, targetID)
+	})
+}
+
+// ApproveFollowRequest makes the requester follow the profile of the account.
+func (uc *relationUsecase) ApproveFollowRequest(ctx context.Context, accountID, profileID, requesterID uuid.UUID) error {
+	if _, err := uc.ownProfile(ctx, accountID, profileID); err != nil {
+		return err
+	}
+
+	return db.WithTx(ctx, uc.pgPool, relationTxOptions, func(tx db.QueryExecutor) error {
+		removed, err := uc.repo.RemoveFollowRequest(ctx, tx, requesterID, profileID)
+		if err != nil {
+			return err
+		}
+		if !removed {
+			return ErrFollowRequestNotFound
+		}
+
+		return uc.follow(ctx, tx, requesterID, profileID)
+	})
+}
+
+// DeclineFollowRequest is not an error when there is no request to decline.
+func (uc *relationUsecase) DeclineFollowRequest(ctx context.Context, accountID, profileID, requesterID uuid.UUID) error {
+	if _, err := uc.ownProfile(ctx, accountID, profileID); err != nil {
+		return err
+	}
+
+	_, err := uc.repo.RemoveFollowRequest(ctx, uc.pgPool, requesterID, profileID)
+	return err
+}
+
+// Block removes the follows and follow requests between the profile of the
+// account and the target and keeps them from following each other until it is
+// unblocked.
+func (uc *relationUsecase) Block(ctx context.Context, accountID, profileID, targetID uuid.UUID) error {
+	if err := uc.checkTarget(ctx, accountID, profileID, targetID); err != nil {
+		return err
+	}
+
+	return db.WithTx(ctx, uc.pgPool, relationTxOptions, func(tx db.QueryExecutor) error {
+		if err := uc.repo.Block(ctx, tx, profileID, targetID); err != nil {
+			if errors.Is(err, repository.ErrNotFound) {
+				return ErrProfileNotFound
+			}
+			return err
+		}
+
+		for _, pair := range [][2]uuid.UUID{{profileID, targetID}, {targetID, profileID}} {
+			if _, err := uc.repo.RemoveFollowRequest(ctx, tx, pair[0], pair[1]); err != nil {
+				return err
+			}
+			if err := uc.unfollow(ctx, tx, pair[0], pair[1]); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (uc *relationUsecase) Unblock(ctx context.Context, accountID, profileID, targetID uuid.UUID) error {
+	if _, err := uc.ownProfile(ctx, accountID, profileID); err != nil {
+		return err
+	}
+
+	return uc.repo.Unblock(ctx, uc.pgPool, profileID, targetID)
+}
+
+// Mute hides the target from the lists the profile of the account views,
+// without affecting follows.
+func (uc *relationUsecase) Mute(ctx context.Context, accountID, profileID, targetID uuid.UUID) error {
+	if err := uc.checkTarget(ctx, accountID, profileID, targetID); err != nil {
+		return err
+	}
+
+	if err := uc.repo.Mute(ctx, uc.pgPool, profileID, targetID); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return ErrProfileNotFound
+		}
+		return err
+	}
+
+	return nil
+}
+
+func (uc *relationUsecase) Unmute(ctx context.Context, accountID, profileID, targetID uuid.UUID) error {
+	if _, err := uc.ownProfile(ctx, accountID, profileID); err != nil {
+		return err
+	}
+
+	return uc.repo.Unmute(ctx, uc.pgPool, profileID, targetID)
+}
+
+// ListFollowers pages through the followers of the profile, newest first. The
+// cursor is the ID of the last follow seen. The follows of a private profile
+// are seen by its followers only. With a viewer profile, the list leaves out
+// profiles the viewer blocks, mutes or is blocked by, and a profile blocking
+// the viewer or blocked by it is not found.
+func (uc *relationUsecase) ListFollowers(ctx context.Context, profileID uuid.UUID, viewer *Viewer, cursor string, limit int) (*RelationPage, error) {
+	return uc.listFollows(ctx, uc.repo.ListFollowers, profileID, viewer, cursor, limit)
+}
+
+// ListFollowing pages through the profiles the profile follows, like
+// ListFollowers.
+func (uc *relationUsecase) ListFollowing(ctx context.Context, profileID uuid.UUID, viewer *Viewer, cursor string, limit int) (*RelationPage, error) {
+	return uc.listFollows(ctx, uc.repo.ListFollowing, profileID, viewer, cursor, limit)
+}
+
+func (uc *relationUsecase) ListBlocked(ctx context.Context, accountID, profileID uuid.UUID, cursor string, limit int) (*RelationPage, error) {
+	return uc.listOwn(ctx, uc.repo.ListBlocked, accountID, profileID, cursor, limit)
+}
+
+func (uc *relationUsecase) ListMuted(ctx context.Context, accountID, profileID uuid.UUID, cursor string, limit int) (*RelationPage, error) {
+	return uc.listOwn(ctx, uc.repo.ListMuted, accountID, profileID, cursor, limit)
+}
+
+// ListFollowRequests pages through the profiles waiting to follow the profile
+// of the account, newest first.
+func (uc *relationUsecase) ListFollowRequests(ctx context.Context, accountID, profileID uuid.UUID, cursor string, limit int) (*RelationPage, error) {
+	return uc.listOwn(ctx, uc.repo.ListFollowRequests, accountID, profileID, cursor, limit)
+}
+
+type listFollowsFunc func(ctx context.Context, qe db.QueryExecutor, profileID uuid.UUID, viewerID, before *uuid.UUID, limit int) ([]*entity.Relation, error)
+
+func (uc *relationUsecase) listFollows(ctx context.Context, list listFollowsFunc, profileID uuid.UUID, viewer *Viewer, cursor string, limit int) (*RelationPage, error) {
+	before, err := parseRelationCursor(cursor)
+	if err != nil {
+		return nil, err
+	}
+
+	profile, err := uc.getProfile(ctx, profileID)
+	if err != nil {
+		return nil, err
+	}
+
+	audience, err := profileAudience(ctx, uc.pgPool, uc.profileRepo, uc.repo, profile, viewer)
+	if err != nil {
+		return nil, err
+	}
+	if !showsFollows(profile.Privacy, audience) {
+		return nil, ErrProfilePrivate
+	}
+
+	var viewerID *uuid.UUID
+	if viewer != nil && viewer.ProfileID != uuid.Nil {
+		viewerID = &viewer.ProfileID
+	}
+
+	relations, err := list(ctx, uc.pgPool, profileID, viewerID, before, limit+1)
+	if err != nil {
+		return nil, err
+	}
+
+	return relationPage(relations, limit), nil
+}
+
+type listRelationsFunc func(ctx context.Context, qe db.QueryExecutor, profileID uuid.UUID, before *uuid.UUID, limit int) ([]*entity.Relation, error)
+
+func (uc *relationUsecase) listOwn(ctx context.Context, list listRelationsFunc, accountID, profileID uuid.UUID, cursor string, limit int) (*RelationPage, error) {
+	before, err := parseRelationCursor(cursor)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := uc.ownProfile(ctx, accountID, profileID); err != nil {
+		return nil, err
+	}
+
+	relations, err := list(ctx, uc.pgPool, profileID, before, limit+1)
+	if err != nil {
+		return nil, err
+	}
+
+	return relationPage(relations, limit), nil
+}
+
+// follow publishes an event when there was no follow yet. It fails with
+// ErrBlocked when either profile blocks the other.
+func (uc *relationUsecase) follow(ctx context.Context, tx db.QueryExecutor, followerID, followeeID uuid.UUID) error {
+	blocked, err := uc.repo.IsBlocked(ctx, tx, followerID, followeeID)
+	if err != nil {
+		return err
+	}
+	if blocked {
+		return ErrBlocked
+	}
+
+	added, err := uc.repo.Follow(ctx, tx, followerID, followeeID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return ErrProfileNotFound
+		}
+		return err
+	}
+	if !added {
+		return nil
+	}
+
+	followed := events.ProfileFollowed{FollowerID: followerID.String(), FolloweeID: followeeID.String()}
+	return addEvent(ctx, tx, uc.outboxRepo, events.TypeProfileFollowed, followerID, followed)
+}
+
+// unfollow publishes an event when there was a follow to remove.
+func (uc *relationUsecase) unfollow(ctx context.Context, tx db.QueryExecutor, followerID, followeeID uuid.UUID) error {
+	removed, err := uc.repo.Unfollow(ctx, tx, followerID, followeeID)
+	if err != nil || !removed {
+		return err
+	}
+
+	unfollowed := events.ProfileUnfollowed{FollowerID: followerID.String(), FolloweeID: followeeID.String()}
+	return addEvent(ctx, tx, uc.outboxRepo, events.TypeProfileUnfollowed, followerID, unfollowed)
+}
+
+// checkTarget checks that the profile belongs to the account and the target
+// is another existing profile.
+func (uc *relationUsecase) checkTarget(ctx context.Context, accountID, profileID, targetID uuid.UUID) error {
+	if profileID == targetID {
+		return ErrSelfRelation
+	}
+
+	if _, err := uc.ownProfile(ctx, accountID, profileID); err != nil {
+		return err
+	}
+
+	_, err := uc.getProfile(ctx, targetID)
+	return err
+}
+
+func (uc *relationUsecase) ownProfile(ctx context.Context, accountID, profileID uuid.UUID) (*entity.Profile, error) {
+	profile, err := uc.getProfile(ctx, profileID)
+	if err != nil {
+		return nil, err
+	}
+	if profile.UserID != accountID {
+		return nil, ErrNotProfileOwner
+	}
+
+	return profile, nil
+}
+
+func (uc *relationUsecase) getProfile(ctx context.Context, id uuid.UUID) (*entity.Profile, error) {
+	profile, err := uc.profileRepo.GetByID(ctx, uc.pgPool, id)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrProfileNotFound
+		}
+		return nil, err
+	}
+
+	return profile, nil
+}
+
+func parseRelationCursor(cursor string) (*uuid.UUID, error) {
+	if cursor == "" {
+		return nil, nil
+	}
+
+	id, err := uuid.Parse(cursor)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	return &id, nil
+}
+
+// relationPage cuts relations, fetched with one extra row that tells whether
+// there is a next page, to limit. Their profiles are shown as anyone sees them.
+func relationPage(relations []*entity.Relation, limit int) *RelationPage {
+	page := &RelationPage{Relations: relations}
+	if len(relations) > limit {
+		page.Relations = relations[:limit]
+		page.NextCursor = relations[limit-1].ID.String()
+	}
+	for _, relation := range page.Relations {
+		relation.Profile = visibleProfile(relation.Profile, entity.AudienceAnonymous)
+	}
+
+	return page
+}
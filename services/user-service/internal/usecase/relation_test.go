@@ -0,0 +1,256 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	events "github.com/SkySock/lode/libs/shared-dto/user/events/v1"
+	"github.com/SkySock/lode/services/user-service/internal/db"
+	"github.com/SkySock/lode/services/user-service/internal/entity"
+	"github.com/SkySock/lode/services/user-service/internal/repository"
+	"github.com/google/uuid"
+)
+
+type profilePair [2]uuid.UUID
+
+type fakeRelationRepository struct {
+	repository.RelationRepository
+	profiles *fakeProfileRepository
+	follows  map[profilePair]bool
+	requests map[profilePair]bool
+	blocks   map[profilePair]bool
+}
+
+func newFakeRelationRepository(profiles *fakeProfileRepository) *fakeRelationRepository {
+	return &fakeRelationRepository{
+		profiles: profiles,
+		follows:  make(map[profilePair]bool),
+		requests: make(map[profilePair]bool),
+		blocks:   make(map[profilePair]bool),
+	}
+}
+
+func (r *fakeRelationRepository) Follow(ctx context.Context, qe db.QueryExecutor, followerID, followeeID uuid.UUID) (bool, error) {
+	added := !r.follows[profilePair{followerID, followeeID}]
+	r.follows[profilePair{followerID, followeeID}] = true
+	return added, nil
+}
+
+func (r *fakeRelationRepository) Unfollow(ctx context.Context, qe db.QueryExecutor, followerID, followeeID uuid.UUID) (bool, error) {
+	removed := r.follows[profilePair{followerID, followeeID}]
+	delete(r.follows, profilePair{followerID, followeeID})
+	return removed, nil
+}
+
+func (r *fakeRelationRepository) IsFollowing(ctx context.Context, qe db.QueryExecutor, followerID, followeeID uuid.UUID) (bool, error) {
+	return r.follows[profilePair{followerID, followeeID}], nil
+}
+
+func (r *fakeRelationRepository) RequestFollow(ctx context.Context, qe db.QueryExecutor, followerID, followeeID uuid.UUID) error {
+	r.requests[profilePair{followerID, followeeID}] = true
+	return nil
+}
+
+func (r *fakeRelationRepository) RemoveFollowRequest(ctx context.Context, qe db.QueryExecutor, followerID, followeeID uuid.UUID) (bool, error) {
+	removed := r.requests[profilePair{followerID, followeeID}]
+	delete(r.requests, profilePair{followerID, followeeID})
+	return removed, nil
+}
+
+func (r *fakeRelationRepository) ListFollowRequests(ctx context.Context, qe db.QueryExecutor, profileID uuid.UUID, before *uuid.UUID, limit int) ([]*entity.Relation, error) {
+	return r.list(ctx, r.requests, 1, profileID)
+}
+
+func (r *fakeRelationRepository) ListFollowers(ctx context.Context, qe db.QueryExecutor, profileID uuid.UUID, viewerID, before *uuid.UUID, limit int) ([]*entity.Relation, error) {
+	return r.list(ctx, r.follows, 1, profileID)
+}
+
+func (r *fakeRelationRepository) ListFollowing(ctx context.Context, qe db.QueryExecutor, profileID uuid.UUID, viewerID, before *uuid.UUID, limit int) ([]*entity.Relation, error) {
+	return r.list(ctx, r.follows, 0, profileID)
+}
+
+func (r *fakeRelationRepository) Block(ctx context.Context, qe db.QueryExecutor, profileID, targetID uuid.UUID) error {
+	r.blocks[profilePair{profileID, targetID}] = true
+	return nil
+}
+
+func (r *fakeRelationRepository) IsBlocked(ctx context.Context, qe db.QueryExecutor, profileID, otherID uuid.UUID) (bool, error) {
+	return r.blocks[profilePair{profileID, otherID}] || r.blocks[profilePair{otherID, profileID}], nil
+}
+
+// list returns the relations whose side own is the profile, with the
+// profiles on their other side.
+func (r *fakeRelationRepository) list(ctx context.Context, pairs map[profilePair]bool, own int, profileID uuid.UUID) ([]*entity.Relation, error) {
+	var relations []*entity.Relation
+	for pair := range pairs {
+		if pair[own] != profileID {
+			continue
+		}
+		profile, err := r.profiles.GetByID(ctx, nil, pair[1-own])
+		if err != nil {
+			return nil, err
+		}
+		relations = append(relations, &entity.Relation{ID: uuid.New(), Profile: profile})
+	}
+	return relations, nil
+}
+
+// newTestAccount returns a profile of a new account.
+func newTestAccount(name string, privacy entity.ProfilePrivacy) *entity.Profile {
+	return &entity.Profile{
+		ID:          uuid.New(),
+		UserID:      uuid.New(),
+		ProfileName: name,
+		Bio:         "Bio of " + name,
+		Avatar:      "https://media.example.com/" + name + ".jpg",
+		Privacy:     privacy,
+	}
+}
+
+func TestFollow(t *testing.T) {
+	public := entity.ProfilePrivacy{BioVisibility: entity.VisibilityEveryone, AvatarVisibility: entity.VisibilityEveryone}
+	private := public
+	private.Private = true
+
+	testCases := []struct {
+		name            string
+		privacy         entity.ProfilePrivacy
+		following       bool
+		blocked         bool
+		expectRequested bool
+		expectFollowing bool
+		expectErr       error
+	}{
+		{name: "Public profile", privacy: public, expectFollowing: true},
+		{name: "Private profile", privacy: private, expectRequested: true},
+		{name: "Private profile followed", privacy: private, following: true, expectFollowing: true},
+		{name: "Blocked public profile", privacy: public, blocked: true, expectErr: ErrBlocked},
+		{name: "Blocked private profile", privacy: private, blocked: true, expectErr: ErrBlocked},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			follower := newTestAccount("follower", public)
+			target := newTestAccount("target", tc.privacy)
+			profiles := &fakeProfileRepository{profiles: []*entity.Profile{follower, target}}
+			relations := newFakeRelationRepository(profiles)
+			relations.follows[profilePair{follower.ID, target.ID}] = tc.following
+			relations.blocks[profilePair{target.ID, follower.ID}] = tc.blocked
+			uc := &relationUsecase{pgPool: &fakePool{}, repo: relations, profileRepo: profiles, outboxRepo: &fakeOutboxRepository{}}
+
+			requested, err := uc.Follow(context.Background(), follower.UserID, follower.ID, target.ID)
+			if !errors.Is(err, tc.expectErr) {
+				t.Fatalf("Wrong error: got %v, want %v", err, tc.expectErr)
+			}
+			if requested != tc.expectRequested {
+				t.Errorf("Wrong requested: got %v, want %v", requested, tc.expectRequested)
+			}
+			if got := relations.follows[profilePair{follower.ID, target.ID}]; got != tc.expectFollowing {
+				t.Errorf("Wrong following: got %v, want %v", got, tc.expectFollowing)
+			}
+			if got := relations.requests[profilePair{follower.ID, target.ID}]; got != tc.expectRequested {
+				t.Errorf("Wrong pending request: got %v, want %v", got, tc.expectRequested)
+			}
+		})
+	}
+}
+
+func TestFollowRequestApproval(t *testing.T) {
+	ctx := context.Background()
+
+	owner := newTestAccount("owner", entity.ProfilePrivacy{
+		Private:          true,
+		BioVisibility:    entity.VisibilityEveryone,
+		AvatarVisibility: entity.VisibilityEveryone,
+	})
+	follower := newTestAccount("follower", entity.ProfilePrivacy{})
+	profiles := &fakeProfileRepository{profiles: []*entity.Profile{owner, follower}}
+	relations := newFakeRelationRepository(profiles)
+	outbox := &fakeOutboxRepository{}
+	profileUC := &profileUsecase{pgPool: &fakePool{}, repo: profiles, relationRepo: relations, outboxRepo: outbox}
+	relationUC := &relationUsecase{pgPool: &fakePool{}, repo: relations, profileRepo: profiles, outboxRepo: outbox}
+	viewer := &Viewer{AccountID: follower.UserID, ProfileID: follower.ID}
+
+	requested, err := relationUC.Follow(ctx, follower.UserID, follower.ID, owner.ID)
+	if err != nil {
+		t.Fatalf("Follow failed: %v", err)
+	}
+	if !requested {
+		t.Fatal("Follow of a private profile should be requested")
+	}
+
+	// Until the owner approves, the follower sees the profile as anyone does.
+	view, err := profileUC.ViewProfile(ctx, owner.ProfileName, viewer)
+	if err != nil {
+		t.Fatalf("ViewProfile failed: %v", err)
+	}
+	if view.Audience != entity.AudienceAnonymous {
+		t.Errorf("Wrong audience: got %v, want %v", view.Audience, entity.AudienceAnonymous)
+	}
+	if view.Profile.Bio != "" || view.Profile.Avatar != "" {
+		t.Errorf("Unapproved follower sees bio %q and avatar %q", view.Profile.Bio, view.Profile.Avatar)
+	}
+	if _, err := relationUC.ListFollowers(ctx, owner.ID, viewer, "", 10); !errors.Is(err, ErrProfilePrivate) {
+		t.Errorf("Wrong error of ListFollowers: got %v, want %v", err, ErrProfilePrivate)
+	}
+	if _, err := relationUC.ListFollowing(ctx, owner.ID, viewer, "", 10); !errors.Is(err, ErrProfilePrivate) {
+		t.Errorf("Wrong error of ListFollowing: got %v, want %v", err, ErrProfilePrivate)
+	}
+
+	if err := relationUC.ApproveFollowRequest(ctx, follower.UserID, owner.ID, follower.ID); !errors.Is(err, ErrNotProfileOwner) {
+		t.Errorf("Wrong error of approval by another account: got %v, want %v", err, ErrNotProfileOwner)
+	}
+
+	page, err := relationUC.ListFollowRequests(ctx, owner.UserID, owner.ID, "", 10)
+	if err != nil {
+		t.Fatalf("ListFollowRequests failed: %v", err)
+	}
+	if len(page.Relations) != 1 || page.Relations[0].Profile.ID != follower.ID {
+		t.Fatalf("Wrong follow requests: got %v, want the follower", page.Relations)
+	}
+
+	if err := relationUC.ApproveFollowRequest(ctx, owner.UserID, owner.ID, follower.ID); err != nil {
+		t.Fatalf("ApproveFollowRequest failed: %v", err)
+	}
+	if err := relationUC.ApproveFollowRequest(ctx, owner.UserID, owner.ID, follower.ID); !errors.Is(err, ErrFollowRequestNotFound) {
+		t.Errorf("Wrong error of second approval: got %v, want %v", err, ErrFollowRequestNotFound)
+	}
+
+	view, err = profileUC.ViewProfile(ctx, owner.ProfileName, viewer)
+	if err != nil {
+		t.Fatalf("ViewProfile failed: %v", err)
+	}
+	if view.Profile.Bio != owner.Bio || view.Profile.Avatar != owner.Avatar {
+		t.Errorf("Approved follower sees bio %q and avatar %q, want %q and %q", view.Profile.Bio, view.Profile.Avatar, owner.Bio, owner.Avatar)
+	}
+	followers, err := relationUC.ListFollowers(ctx, owner.ID, viewer, "", 10)
+	if err != nil {
+		t.Fatalf("ListFollowers failed: %v", err)
+	}
+	if len(followers.Relations) != 1 || followers.Relations[0].Profile.ID != follower.ID {
+		t.Errorf("Wrong followers: got %v, want the follower", followers.Relations)
+	}
+
+	if len(outbox.pending) != 1 || outbox.pending[0].Type != events.TypeProfileFollowed {
+		t.Errorf("Approval should publish one %s event, got %v", events.TypeProfileFollowed, outbox.pending)
+	}
+}
+
+func TestBlockRemovesFollowRequests(t *testing.T) {
+	private := entity.ProfilePrivacy{Private: true}
+	owner := newTestAccount("owner", private)
+	other := newTestAccount("other", private)
+	profiles := &fakeProfileRepository{profiles: []*entity.Profile{owner, other}}
+	relations := newFakeRelationRepository(profiles)
+	relations.requests[profilePair{owner.ID, other.ID}] = true
+	relations.requests[profilePair{other.ID, owner.ID}] = true
+	uc := &relationUsecase{pgPool: &fakePool{}, repo: relations, profileRepo: profiles, outboxRepo: &fakeOutboxRepository{}}
+
+	if err := uc.Block(context.Background(), owner.UserID, owner.ID, other.ID); err != nil {
+		t.Fatalf("Block failed: %v", err)
+	}
+	if len(relations.requests) != 0 {
+		t.Errorf("Follow requests left after block: %v", relations.requests)
+	}
+}
@@ -0,0 +1,131 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/SkySock/lode/services/user-service/internal/db"
+	"github.com/SkySock/lode/services/user-service/internal/entity"
+	repo "github.com/SkySock/lode/services/user-service/internal/repository"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+var (
+	ErrRoleNotFound   = errors.New("role not found")
+	ErrRoleNotGranted = errors.New("role not granted")
+	ErrLastAdmin      = errors.New("last admin cannot lose the admin role")
+)
+
+type accessTokenRevoker interface {
+	RevokeAccessTokens(ctx context.Context, accountID uuid.UUID) error
+}
+
+type roleUsecase struct {
+	pgPool      *pgxpool.Pool
+	roleRepo    repo.RoleRepository
+	accountRepo repo.AccountRepository
+	auditRepo   repo.AuditRepository
+	tokens      accessTokenRevoker
+}
+
+func NewRoleUsecase(
+	pool *pgxpool.Pool,
+	roleRepo repo.RoleRepository,
+	accountRepo repo.AccountRepository,
+	auditRepo repo.AuditRepository,
+	tokens accessTokenRevoker,
+) RoleUsecase {
+	return &roleUsecase{
+		pgPool:      pool,
+		roleRepo:    roleRepo,
+		accountRepo: accountRepo,
+		auditRepo:   auditRepo,
+		tokens:      tokens,
+	}
+}
+
+func (u *roleUsecase) ListRoles(ctx context.Context) ([]*entity.Role, error) {
+	return u.roleRepo.List(ctx, u.pgPool)
+}
+
+func (u *roleUsecase) GetAccountRoles(ctx context.Context, accountID uuid.UUID) ([]*entity.AccountRole, error) {
+	if _, err := u.accountRepo.GetById(ctx, u.pgPool, accountID); err != nil {
+		if errors.Is(err, repo.ErrNotFound) {
+			return nil, ErrAccountNotFound
+		}
+		return nil, err
+	}
+
+	return u.roleRepo.GetAccountRoles(ctx, u.pgPool, accountID)
+}
+
+// GrantRole is idempotent. The role shows up in access tokens issued after the
+// grant, i.e. at the next refresh.
+func (u *roleUsecase) GrantRole(ctx context.Context, actorID, accountID uuid.UUID, role string) error {
+	if _, err := u.accountRepo.GetById(ctx, u.pgPool, accountID); err != nil {
+		if errors.Is(err, repo.ErrNotFound) {
+			return ErrAccountNotFound
+		}
+		return err
+	}
+
+	return db.WithTx(ctx, u.pgPool, db.TxOptions{}, func(tx db.QueryExecutor) error {
+		if err := u.roleRepo.Grant(ctx, tx, accountID, role, &actorID); err != nil {
+			if errors.Is(err, repo.ErrNotFound) {
+				return ErrRoleNotFound
+			}
+			return err
+		}
+
+		return u.auditRepo.Create(ctx, tx, newAuditEntry(actorID, entity.AuditRoleGranted, accountID, map[string]any{"role": role}))
+	})
+}
+
+// RevokeRole also revokes the account's access tokens, so that the role stops
+// working immediately instead of when the tokens expire.
+func (u *roleUsecase) RevokeRole(ctx context.Context, actorID, accountID uuid.UUID, role string) error {
+	if err := db.WithTx(ctx, u.pgPool, db.TxOptions{}, func(tx db.QueryExecutor) error {
+		if role == entity.RoleAdmin {
+			holders, err := u.roleRepo.LockHolders(ctx, tx, role)
+			if err != nil {
+				return err
+			}
+			if len(holders) == 1 && holders[0] == accountID {
+				return ErrLastAdmin
+			}
+		}
+
+		if err := u.roleRepo.Revoke(ctx, tx, accountID, role); err != nil {
+			if errors.Is(err, repo.ErrNotFound) {
+				return ErrRoleNotGranted
+			}
+			return err
+		}
+
+		return u.auditRepo.Create(ctx, tx, newAuditEntry(actorID, entity.AuditRoleRevoked, accountID, map[string]any{"role": role}))
+	}); err != nil {
+		return err
+	}
+
+	if err := u.tokens.RevokeAccessTokens(ctx, accountID); err != nil {
+		return fmt.Errorf("role revoked, but access tokens were not: %w", err)
+	}
+
+	return nil
+}
+
+// BootstrapAdmin grants the admin role to an existing account, so that the
+// first admin does not have to be created by hand in the database.
+func (u *roleUsecase) BootstrapAdmin(ctx context.Context, username string) error {
+	account, err := u.accountRepo.GetByUsername(ctx, u.pgPool, username)
+	if err != nil {
+		if errors.Is(err, repo.ErrNotFound) {
+			return ErrAccountNotFound
+		}
+		return err
+	}
+
+	return u.roleRepo.Grant(ctx, u.pgPool, account.ID, entity.RoleAdmin, nil)
+}
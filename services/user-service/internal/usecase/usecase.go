@@ -2,17 +2,116 @@ package usecase
 
 import (
 	"context"
+	"time"
 
+	"github.com/SkySock/lode/libs/utils/jwt"
+	"github.com/SkySock/lode/services/user-service/internal/broker"
+	"github.com/SkySock/lode/services/user-service/internal/entity"
+	repo "github.com/SkySock/lode/services/user-service/internal/repository"
 	"github.com/google/uuid"
 )
 
 type AuthUsecase interface {
 	RegisterUser(ctx context.Context, userData RegistrationInfo) (uuid.UUID, error)
 	Login(ctx context.Context, login, password string) (*AuthTokens, error)
+	ChangePassword(ctx context.Context, login, password, newPassword string) error
+	RestoreAccount(ctx context.Context, login, password string) error
 	Logout(ctx context.Context, refreshToken string) error
+	Refresh(ctx context.Context, refreshToken string) (*AuthTokens, error)
+	ValidateSession(ctx context.Context, refreshToken string) (*entity.Session, error)
+	IntrospectAccessToken(ctx context.Context, accessToken string) (*AccessTokenClaims, error)
+	VerifyAccessToken(ctx context.Context, accessToken string) (*jwt.Claims, error)
+	RevokeToken(ctx context.Context, token, hint string) error
+	RevokeAccessTokens(ctx context.Context, accountID uuid.UUID) error
+	ActiveSessions(ctx context.Context, accountID uuid.UUID) ([]*entity.Session, error)
+	TerminateSessions(ctx context.Context, accountID uuid.UUID) (int, error)
 }
 
-type ProfileUsecase interface{}
+type AccountUsecase interface {
+	GetAccount(ctx context.Context, id uuid.UUID) (*entity.Account, error)
+	DeleteAccount(ctx context.Context, id uuid.UUID, password string) (time.Time, error)
+	ExportData(ctx context.Context, id uuid.UUID) (*AccountExport, error)
+	PurgeDeletedAccounts(ctx context.Context) (int, error)
+}
+
+type IdentityUsecase interface {
+	ChangeUsername(ctx context.Context, accountID uuid.UUID, password, username string) error
+	RequestEmailChange(ctx context.Context, accountID uuid.UUID, password, email string) error
+	ConfirmEmailChange(ctx context.Context, token string) error
+}
+
+type WebhookUsecase interface {
+	CreateSubscription(ctx context.Context, actorID uuid.UUID, url, description string, eventTypes []string) (*entity.WebhookSubscription, error)
+	ListSubscriptions(ctx context.Context) ([]*entity.WebhookSubscription, error)
+	DeleteSubscription(ctx context.Context, actorID, id uuid.UUID) error
+	ListDeliveries(ctx context.Context, subscriptionID uuid.UUID, status, cursor string, limit int) (*WebhookDeliveryPage, error)
+	ReplayDelivery(ctx context.Context, actorID, subscriptionID, deliveryID uuid.UUID) error
+	Publish(ctx context.Context, msg broker.Message) error
+	DeliverDue(ctx context.Context) (int, error)
+}
+
+type OutboxRelay interface {
+	Relay(ctx context.Context) (int, error)
+}
+
+type RoleUsecase interface {
+	ListRoles(ctx context.Context) ([]*entity.Role, error)
+	GetAccountRoles(ctx context.Context, accountID uuid.UUID) ([]*entity.AccountRole, error)
+	GrantRole(ctx context.Context, actorID, accountID uuid.UUID, role string) error
+	RevokeRole(ctx context.Context, actorID, accountID uuid.UUID, role string) error
+	BootstrapAdmin(ctx context.Context, username string) error
+}
+
+type AdminUsecase interface {
+	SearchAccounts(ctx context.Context, query, cursor string, limit int) (*AccountPage, error)
+	GetAccountDetails(ctx context.Context, accountID uuid.UUID) (*AccountDetails, error)
+	LockAccount(ctx context.Context, actorID, accountID uuid.UUID, reason string) error
+	UnlockAccount(ctx context.Context, actorID, accountID uuid.UUID) error
+	ForcePasswordReset(ctx context.Context, actorID, accountID uuid.UUID) error
+	ForceLogout(ctx context.Context, actorID, accountID uuid.UUID) (int, error)
+	UpdateProfile(ctx context.Context, actorID, profileID uuid.UUID, update ProfileUpdate) (*entity.Profile, error)
+}
+
+type AuthEventUsecase interface {
+	ListEvents(ctx context.Context, filter repo.AuthEventFilter, limit int) (*AuthEventPage, error)
+}
+
+// ProfileUsecase.ViewProfile and SearchProfiles hide what the privacy of
+// profiles keeps from their viewers; the getters return whole profiles, for
+// internal use and the trusted services calling the gRPC API.
+type ProfileUsecase interface {
+	GetUserProfile(ctx context.Context, id uuid.UUID) (*entity.Profile, error)
+	GetProfileByName(ctx context.Context, name string) (*entity.Profile, error)
+	ViewProfile(ctx context.Context, name string, viewer *Viewer) (*ProfileView, error)
+	GetProfilesByIDs(ctx context.Context, ids []uuid.UUID) ([]*entity.Profile, error)
+	SearchProfiles(ctx context.Context, query string, viewer *Viewer, cursor string, limit int) (*ProfilePage, error)
+	UpdateAvatar(ctx context.Context, accountID, profileID uuid.UUID, image []byte) (*entity.Profile, error)
+	DeleteAvatar(ctx context.Context, accountID, profileID uuid.UUID) error
+	UpdatePrivacy(ctx context.Context, accountID, profileID uuid.UUID, update PrivacyUpdate) (*entity.Profile, error)
+}
+
+type RelationUsecase interface {
+	Follow(ctx context.Context, accountID, profileID, targetID uuid.UUID) (bool, error)
+	Unfollow(ctx context.Context, accountID, profileID, targetID uuid.UUID) error
+	Block(ctx context.Context, accountID, profileID, targetID uuid.UUID) error
+	Unblock(ctx context.Context, accountID, profileID, targetID uuid.UUID) error
+	Mute(ctx context.Context, accountID, profileID, targetID uuid.UUID) error
+	Unmute(ctx context.Context, accountID, profileID, targetID uuid.UUID) error
+	ListFollowers(ctx context.Context, profileID uuid.UUID, viewer *Viewer, cursor string, limit int) (*RelationPage, error)
+	ListFollowing(ctx context.Context, profileID uuid.UUID, viewer *Viewer, cursor string, limit int) (*RelationPage, error)
+	ListBlocked(ctx context.Context, accountID, profileID uuid.UUID, cursor string, limit int) (*RelationPage, error)
+	ListMuted(ctx context.Context, accountID, profileID uuid.UUID, cursor string, limit int) (*RelationPage, error)
+	ListFollowRequests(ctx context.Context, accountID, profileID uuid.UUID, cursor string, limit int) (*RelationPage, error)
+	ApproveFollowRequest(ctx context.Context, accountID, profileID, requesterID uuid.UUID) error
+	DeclineFollowRequest(ctx context.Context, accountID, profileID, requesterID uuid.UUID) error
+}
+
+// Viewer is the signed in account that views profiles, as one of its
+// profiles. ProfileID is Nil when it views them as none of them.
+type Viewer struct {
+	AccountID uuid.UUID
+	ProfileID uuid.UUID
+}
 
 type RegistrationInfo struct {
 	Username string
@@ -24,3 +123,67 @@ type AuthTokens struct {
 	AccessToken  string
 	RefreshToken string
 }
+
+type AccessTokenClaims struct {
+	ID          string
+	Subject     string
+	ProfileID   string
+	SessionID   string
+	Issuer      string
+	Audience    []string
+	Roles       []string
+	Permissions []string
+	IssuedAt    time.Time
+	ExpiresAt   time.Time
+}
+
+type AccountPage struct {
+	Accounts []*entity.Account
+	// NextCursor is empty on the last page.
+	NextCursor string
+}
+
+type ProfilePage struct {
+	Profiles []*entity.Profile
+	// NextCursor is empty on the last page.
+	NextCursor string
+}
+
+type RelationPage struct {
+	Relations []*entity.Relation
+	// NextCursor is empty on the last page.
+	NextCursor string
+}
+
+type AuthEventPage struct {
+	Events []*entity.AuthEvent
+	// NextCursor is empty on the last page.
+	NextCursor string
+}
+
+type WebhookDeliveryPage struct {
+	Deliveries []*entity.WebhookDelivery
+	// NextCursor is empty on the last page.
+	NextCursor string
+}
+
+type AccountDetails struct {
+	Account  *entity.Account
+	Profiles []*entity.Profile
+	Sessions []*entity.Session
+}
+
+// ProfileUpdate holds the fields to change; nil fields are left as they are.
+type ProfileUpdate struct {
+	DisplayName *string
+	Bio         *string
+	Avatar      *string
+}
+
+type AccountExport struct {
+	Account         *entity.Account
+	Profiles        []*entity.Profile
+	Sessions        []*entity.Session
+	AuthEvents      []*entity.AuthEvent
+	IdentityChanges []*entity.IdentityChange
+}
@@ -0,0 +1,271 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	events "github.com/SkySock/lode/libs/shared-dto/user/events/v1"
+	"github.com/SkySock/lode/services/user-service/internal/broker"
+	"github.com/SkySock/lode/services/user-service/internal/config"
+	"github.com/SkySock/lode/services/user-service/internal/db"
+	"github.com/SkySock/lode/services/user-service/internal/entity"
+	repo "github.com/SkySock/lode/services/user-service/internal/repository"
+	"github.com/SkySock/lode/services/user-service/internal/webhook"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+var (
+	ErrWebhookNotFound  = errors.New("webhook not found")
+	ErrDeliveryNotFound = errors.New("webhook delivery not found")
+	ErrUnknownEventType = errors.New("unknown event type")
+)
+
+// WebhookEventTypes are the domain events partners can subscribe to.
+var WebhookEventTypes = []string{
+	events.TypeUserRegistered,
+	events.TypeProfileUpdated,
+	events.TypeProfileFollowed,
+	events.TypeProfileUnfollowed,
+	events.TypeAccountLocked,
+	events.TypeAccountUnlocked,
+	events.TypeAccountDeleted,
+	events.TypeAccountRestored,
+	events.TypeAccountPurged,
+}
+
+type webhookSender interface {
+	Send(ctx context.Context, url, secret, id string, body []byte) (int, error)
+}
+
+type webhookUsecase struct {
+	pgPool    *pgxpool.Pool
+	repo      repo.WebhookRepository
+	auditRepo repo.AuditRepository
+	sender    webhookSender
+	cfg       config.WebhookConfig
+}
+
+var _ broker.Publisher = (*webhookUsecase)(nil)
+
+func NewWebhookUsecase(
+	pool *pgxpool.Pool,
+	repo repo.WebhookRepository,
+	auditRepo repo.AuditRepository,
+	sender webhookSender,
+	cfg config.WebhookConfig,
+) WebhookUsecase {
+	return &webhookUsecase{
+		pgPool:    pool,
+		repo:      repo,
+		auditRepo: auditRepo,
+		sender:    sender,
+		cfg:       cfg,
+	}
+}
+
+// CreateSubscription returns the subscription with its signing secret, which
+// is generated here.
+func (u *webhookUsecase) CreateSubscription(ctx context.Context, actorID uuid.UUID, url, description string, eventTypes []string) (*entity.WebhookSubscription, error) {
+	for _, t := range eventTypes {
+		if !slices.Contains(WebhookEventTypes, t) {
+			return nil, fmt.Errorf("%w: %s", ErrUnknownEventType, t)
+		}
+	}
+
+	secret, err := generateOpaqueToken()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate secret: %w", err)
+	}
+
+	sub := &entity.WebhookSubscription{
+		URL:         url,
+		Secret:      secret,
+		EventTypes:  slices.Compact(slices.Sorted(slices.Values(eventTypes))),
+		Description: description,
+		CreatedBy:   &actorID,
+	}
+
+	if err := db.WithTx(ctx, u.pgPool, db.TxOptions{}, func(tx db.QueryExecutor) error {
+		if err := u.repo.CreateSubscription(ctx, tx, sub); err != nil {
+			return err
+		}
+		details := map[string]any{"url": url, "event_types": sub.EventTypes}
+		return u.auditRepo.Create(ctx, tx, newAuditEntry(actorID, entity.AuditWebhookCreated, sub.ID, details))
+	}); err != nil {
+		return nil, err
+	}
+
+	return sub, nil
+}
+
+func (u *webhookUsecase) ListSubscriptions(ctx context.Context) ([]*entity.WebhookSubscription, error) {
+	return u.repo.ListSubscriptions(ctx, u.pgPool)
+}
+
+func (u *webhookUsecase) DeleteSubscription(ctx context.Context, actorID, id uuid.UUID) error {
+	return db.WithTx(ctx, u.pgPool, db.TxOptions{}, func(tx db.QueryExecutor) error {
+		if err := u.repo.DeleteSubscription(ctx, tx, id); err != nil {
+			if errors.Is(err, repo.ErrNotFound) {
+				return ErrWebhookNotFound
+			}
+			return err
+		}
+		return u.auditRepo.Create(ctx, tx, newAuditEntry(actorID, entity.AuditWebhookDeleted, id, nil))
+	})
+}
+
+// ListDeliveries pages through deliveries of the subscription, newest first.
+// With the dead status it is the dead-letter list of the subscription.
+func (u *webhookUsecase) ListDeliveries(ctx context.Context, subscriptionID uuid.UUID, status, cursor string, limit int) (*WebhookDeliveryPage, error) {
+	if _, err := u.repo.GetSubscription(ctx, u.pgPool, subscriptionID); err != nil {
+		if errors.Is(err, repo.ErrNotFound) {
+			return nil, ErrWebhookNotFound
+		}
+		return nil, err
+	}
+
+	var before *uuid.UUID
+	if cursor != "" {
+		id, err := uuid.Parse(cursor)
+		if err != nil {
+			return nil, ErrInvalidCursor
+		}
+		before = &id
+	}
+
+	// One extra row tells whether there is a next page.
+	deliveries, err := u.repo.ListDeliveries(ctx, u.pgPool, subscriptionID, status, before, limit+1)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &WebhookDeliveryPage{Deliveries: deliveries}
+	if len(deliveries) > limit {
+		page.Deliveries = deliveries[:limit]
+		page.NextCursor = deliveries[limit-1].ID.String()
+	}
+
+	return page, nil
+}
+
+// ReplayDelivery sends the delivery again, also when it was delivered or ran
+// out of attempts.
+func (u *webhookUsecase) ReplayDelivery(ctx context.Context, actorID, subscriptionID, deliveryID uuid.UUID) error {
+	return db.WithTx(ctx, u.pgPool, db.TxOptions{}, func(tx db.QueryExecutor) error {
+		if err := u.repo.Replay(ctx, tx, subscriptionID, deliveryID, time.Now().UTC()); err != nil {
+			if errors.Is(err, repo.ErrNotFound) {
+				return ErrDeliveryNotFound
+			}
+			return err
+		}
+		details := map[string]any{"delivery_id": deliveryID}
+		return u.auditRepo.Create(ctx, tx, newAuditEntry(actorID, entity.AuditWebhookReplayed, subscriptionID, details))
+	})
+}
+
+// Publish schedules deliveries of the event to its subscribers. It is called
+// by the outbox relay, possibly more than once for an event.
+func (u *webhookUsecase) Publish(ctx context.Context, msg broker.Message) error {
+	eventID, err := uuid.Parse(msg.ID)
+	if err != nil {
+		return fmt.Errorf("invalid event id %q: %w", msg.ID, err)
+	}
+
+	subs, err := u.repo.SubscriptionsFor(ctx, u.pgPool, msg.Type)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now().UTC()
+	for _, sub := range subs {
+		delivery := &entity.WebhookDelivery{
+			SubscriptionID: sub.ID,
+			EventID:        eventID,
+			EventType:      msg.Type,
+			Payload:        msg.Payload,
+			OccurredAt:     msg.OccurredAt,
+			NextAttemptAt:  now,
+		}
+		if err := u.repo.AddDelivery(ctx, u.pgPool, delivery); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// DeliverDue sends a batch of due deliveries at once and returns their number.
+// A failed delivery is retried with an exponential backoff until it runs out
+// of attempts and is dead.
+func (u *webhookUsecase) DeliverDue(ctx context.Context) (int, error) {
+	now := time.Now().UTC()
+
+	// The lease outlasts the requests, which run at the same time.
+	deliveries, err := u.repo.ClaimDue(ctx, u.pgPool, now, now.Add(u.cfg.Timeout+time.Minute), u.cfg.BatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	var wg sync.WaitGroup
+	errs := make([]error, len(deliveries))
+	for i, d := range deliveries {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs[i] = u.deliver(ctx, d)
+		}()
+	}
+	wg.Wait()
+
+	return len(deliveries), errors.Join(errs...)
+}
+
+func (u *webhookUsecase) deliver(ctx context.Context, d *entity.WebhookDelivery) error {
+	body, err := json.Marshal(events.WebhookBody{
+		ID:         d.EventID.String(),
+		Type:       d.EventType,
+		OccurredAt: d.OccurredAt,
+		Data:       d.Payload,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to encode delivery %s: %w", d.ID, err)
+	}
+
+	code, sendErr := u.sender.Send(ctx, d.URL, d.Secret, d.EventID.String(), body)
+	if ctx.Err() != nil {
+		// Shutting down; the delivery is retried once its lease is over.
+		return nil
+	}
+
+	now := time.Now().UTC()
+	d.Attempts++
+	d.LastStatusCode = nil
+	if code != 0 {
+		d.LastStatusCode = &code
+	}
+
+	switch {
+	case sendErr == nil:
+		d.Status = entity.WebhookDelivered
+		d.DeliveredAt = &now
+		d.LastError = ""
+	case d.Attempts >= u.cfg.MaxAttempts:
+		d.Status = entity.WebhookDead
+		d.LastError = sendErr.Error()
+	default:
+		d.NextAttemptAt = now.Add(webhook.Backoff(u.cfg.RetryBase, u.cfg.RetryMax, d.Attempts))
+		d.LastError = sendErr.Error()
+	}
+
+	if err := u.repo.SaveAttempt(ctx, u.pgPool, d); err != nil {
+		return fmt.Errorf("failed to save attempt of delivery %s: %w", d.ID, err)
+	}
+
+	return nil
+}
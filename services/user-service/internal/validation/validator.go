@@ -18,3 +18,39 @@ func ValidateSignUpRequest(body *v1.SignUpRequest) error {
 func ValidateSignInRequest(body *v1.SignInRequest) error {
 	return v.Struct(body)
 }
+
+func ValidateChangePasswordRequest(body *v1.ChangePasswordRequest) error {
+	return v.Struct(body)
+}
+
+func ValidateLockAccountRequest(body *v1.LockAccountRequest) error {
+	return v.Struct(body)
+}
+
+func ValidateUpdateProfileRequest(body *v1.UpdateProfileRequest) error {
+	return v.Struct(body)
+}
+
+func ValidateUpdatePrivacyRequest(body *v1.UpdatePrivacyRequest) error {
+	return v.Struct(body)
+}
+
+func ValidateDeleteAccountRequest(body *v1.DeleteAccountRequest) error {
+	return v.Struct(body)
+}
+
+func ValidateChangeUsernameRequest(body *v1.ChangeUsernameRequest) error {
+	return v.Struct(body)
+}
+
+func ValidateChangeEmailRequest(body *v1.ChangeEmailRequest) error {
+	return v.Struct(body)
+}
+
+func ValidateConfirmEmailRequest(body *v1.ConfirmEmailRequest) error {
+	return v.Struct(body)
+}
+
+func ValidateCreateWebhookRequest(body *v1.CreateWebhookRequest) error {
+	return v.Struct(body)
+}
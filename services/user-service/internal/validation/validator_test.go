@@ -148,3 +148,19 @@ func TestValidateEmail(t *testing.T) {
 		}
 	})
 }
+
+func TestValidateChangePasswordRequest(t *testing.T) {
+	t.Run("New password is checked", func(t *testing.T) {
+		data := v1.ChangePasswordRequest{Login: "testuser", Password: "old", NewPassword: "weak"}
+		if err := ValidateChangePasswordRequest(&data); err == nil {
+			t.Error("Expected: error")
+		}
+	})
+
+	t.Run("Current password is not checked", func(t *testing.T) {
+		data := v1.ChangePasswordRequest{Login: "testuser", Password: "old", NewPassword: "Passw0rd!"}
+		if err := ValidateChangePasswordRequest(&data); err != nil {
+			t.Error(err)
+		}
+	})
+}
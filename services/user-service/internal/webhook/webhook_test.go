@@ -0,0 +1,67 @@
+package webhook
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestSign(t *testing.T) {
+	// echo -n '1700000000.{"id":"1"}' | openssl dgst -sha256 -hmac secret
+	want := "v1=086f6aff7bd084c98679825129c5a64dbad88c760016d6d2c0fb123f27951d54"
+	if got := Sign("secret", time.Unix(1700000000, 0), []byte(`{"id":"1"}`)); got != want {
+		t.Errorf("Sign() = %s, want %s", got, want)
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	tests := []struct {
+		attempt int
+		want    time.Duration
+	}{
+		{1, 30 * time.Second},
+		{2, time.Minute},
+		{4, 4 * time.Minute},
+		{20, time.Hour},
+	}
+	for _, tt := range tests {
+		if got := Backoff(30*time.Second, time.Hour, tt.attempt); got != tt.want {
+			t.Errorf("Backoff(%d) = %s, want %s", tt.attempt, got, tt.want)
+		}
+	}
+}
+
+func TestSend(t *testing.T) {
+	body := []byte(`{"id":"1"}`)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got, _ := io.ReadAll(r.Body)
+		ts, err := strconv.ParseInt(r.Header.Get(HeaderTimestamp), 10, 64)
+		if err != nil || r.Header.Get(HeaderSignature) != Sign("secret", time.Unix(ts, 0), got) {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if r.Header.Get(HeaderID) == "redirect" {
+			http.Redirect(w, r, "/elsewhere", http.StatusFound)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	s := NewSender(time.Second)
+
+	if code, err := s.Send(context.Background(), srv.URL, "secret", "1", body); err != nil || code != http.StatusNoContent {
+		t.Errorf("Signed delivery failed: %d, %v", code, err)
+	}
+	if code, err := s.Send(context.Background(), srv.URL, "wrong", "1", body); err == nil || code != http.StatusUnauthorized {
+		t.Errorf("Delivery with a wrong secret should fail: %d, %v", code, err)
+	}
+	if code, err := s.Send(context.Background(), srv.URL, "secret", "redirect", body); err == nil || code != http.StatusFound {
+		t.Errorf("Redirect should not be followed: %d, %v", code, err)
+	}
+}